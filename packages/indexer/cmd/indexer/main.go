@@ -8,14 +8,15 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
-	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/rpc"
 )
 
@@ -41,6 +42,9 @@ func main() {
 		rpcURL = "http://localhost:8545"
 	}
 
+	// Optional; without it new blocks are found by polling
+	wsURL := os.Getenv("PHOENIX_WS_URL")
+
 	batchSize := 10
 	if bs := os.Getenv("INDEXER_BATCH_SIZE"); bs != "" {
 		if parsed, err := strconv.Atoi(bs); err == nil {
@@ -48,12 +52,105 @@ func main() {
 		}
 	}
 
+	workers := 5
+	if w := os.Getenv("INDEXER_WORKERS"); w != "" {
+		if parsed, err := strconv.Atoi(w); err == nil {
+			workers = parsed
+		}
+	}
+
+	// "dag" also indexes blocks only reachable through parent hashes; "number" follows block numbers only
+	syncMode := os.Getenv("INDEXER_SYNC_MODE")
+	if syncMode == "" {
+		syncMode = "dag"
+	}
+	if syncMode != "dag" && syncMode != "number" {
+		logger.Fatal("Invalid INDEXER_SYNC_MODE, expected dag or number", zap.String("sync_mode", syncMode))
+	}
+
+	// Stages run for every block after it is saved, in order
+	stageNames := os.Getenv("INDEXER_STAGES")
+	if stageNames == "" {
+		stageNames = "receipts,dag"
+	}
+
+	// What a stage failure does to its block: fail rolls the block back, skip keeps it
+	stagePolicies := os.Getenv("INDEXER_STAGE_POLICIES")
+	if stagePolicies == "" {
+		stagePolicies = "receipts:fail,dag:skip"
+	}
+
+	// What malformed transactions and logs in RPC responses do: strict fails the block,
+	// lenient drops them and records each one in decode_anomalies
+	decodeModeName := os.Getenv("INDEXER_DECODE_MODE")
+	if decodeModeName == "" {
+		decodeModeName = "strict"
+	}
+	decodeMode, err := rpc.ParseDecodeMode(decodeModeName)
+	if err != nil {
+		logger.Fatal("Invalid INDEXER_DECODE_MODE, expected strict or lenient", zap.String("decode_mode", decodeModeName))
+	}
+
+	// How blocks are fetched: full returns transactions inside each block, hashes fetches
+	// blocks with transaction hashes only, then transactions and receipts concurrently
+	fetchMode := os.Getenv("INDEXER_FETCH_MODE")
+	if fetchMode == "" {
+		fetchMode = "full"
+	}
+	if fetchMode != "full" && fetchMode != "hashes" {
+		logger.Fatal("Invalid INDEXER_FETCH_MODE, expected full or hashes", zap.String("fetch_mode", fetchMode))
+	}
+
+	txWorkers := 8
+	if tw := os.Getenv("INDEXER_TX_WORKERS"); tw != "" {
+		if parsed, err := strconv.Atoi(tw); err == nil {
+			txWorkers = parsed
+		}
+	}
+
+	stageRetries := 2
+	if sr := os.Getenv("INDEXER_STAGE_RETRIES"); sr != "" {
+		if parsed, err := strconv.Atoi(sr); err == nil {
+			stageRetries = parsed
+		}
+	}
+
+	poolConfig := database.DefaultPoolConfig(dbURL)
+	if mc := os.Getenv("DATABASE_MAX_CONNS"); mc != "" {
+		if parsed, err := strconv.ParseInt(mc, 10, 32); err == nil {
+			poolConfig.MaxConns = int32(parsed)
+		}
+	}
+	if mc := os.Getenv("DATABASE_MIN_CONNS"); mc != "" {
+		if parsed, err := strconv.ParseInt(mc, 10, 32); err == nil {
+			poolConfig.MinConns = int32(parsed)
+		}
+	}
+
 	logger.Info("Configuration loaded",
 		zap.String("database_url", dbURL),
+		zap.Int32("database_max_conns", poolConfig.MaxConns),
+		zap.Int32("database_min_conns", poolConfig.MinConns),
 		zap.String("rpc_url", rpcURL),
+		zap.String("ws_url", wsURL),
 		zap.Int("batch_size", batchSize),
+		zap.Int("workers", workers),
+		zap.String("sync_mode", syncMode),
+		zap.String("stages", stageNames),
+		zap.String("stage_policies", stagePolicies),
+		zap.Int("stage_retries", stageRetries),
+		zap.String("decode_mode", decodeMode.String()),
+		zap.String("fetch_mode", fetchMode),
+		zap.Int("tx_workers", txWorkers),
 	)
 
+	if int(poolConfig.MaxConns) < workers {
+		logger.Warn("Database pool is smaller than the worker count, workers will wait for connections",
+			zap.Int32("database_max_conns", poolConfig.MaxConns),
+			zap.Int("workers", workers),
+		)
+	}
+
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -69,21 +166,21 @@ func main() {
 
 	// Connect to database
 	logger.Info("Connecting to database...")
-	conn, err := pgx.Connect(ctx, dbURL)
+	pool, err := database.NewPool(ctx, poolConfig, logger)
 	if err != nil {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
-	defer conn.Close(ctx)
+	defer pool.Close()
 
 	// Verify database connection
-	if err := conn.Ping(ctx); err != nil {
+	if err := pool.Ping(ctx); err != nil {
 		logger.Fatal("Failed to ping database", zap.Error(err))
 	}
 	logger.Info("Connected to database")
 
 	// Create RPC client
 	logger.Info("Connecting to Phoenix Node RPC...")
-	rpcClient := rpc.NewPhoenixClient(rpcURL, rpc.WithLogger(logger))
+	rpcClient := rpc.NewPhoenixClient(rpcURL, rpc.WithLogger(logger), rpc.WithDecodeMode(decodeMode))
 
 	// Verify RPC connection
 	blockNum, err := rpcClient.BlockNumber(ctx)
@@ -93,73 +190,263 @@ func main() {
 	logger.Info("Connected to Phoenix Node RPC", zap.String("current_block", blockNum.String()))
 
 	// Create repositories
-	blockRepo := database.NewBlockRepository(conn, logger)
-	txRepo := database.NewTransactionRepository(conn, logger)
+	store := database.NewStore(pool, logger)
+	blockRepo := database.NewBlockRepository(pool, logger)
+	txRepo := database.NewTransactionRepository(pool, logger)
+	checkpointRepo := database.NewCheckpointRepository(pool, logger)
+	reorgRepo := database.NewReorgEventRepository(pool, logger)
+	logRepo := database.NewLogRepository(pool, logger)
+	dagRepo := database.NewDAGRepository(pool, logger)
+	anomalyRepo := database.NewDecodeAnomalyRepository(pool, logger)
+
+	// Create reorg handler
+	reorgHandler := indexer.NewReorgHandler(indexer.ReorgHandlerDeps{
+		Blocks:      blockRepo,
+		Chain:       blockRepo,
+		BlockDB:     blockRepo,
+		TxDB:        txRepo,
+		InclusionDB: txRepo,
+		Events:      reorgRepo,
+		Logger:      logger,
+	})
+
+	// Create stage indexers
+	txDeps := indexer.TransactionIndexerDeps{
+		RPC:       rpcClient,
+		BatchRPC:  rpcClient,
+		TxDB:      txRepo,
+		LogDB:     logRepo,
+		AnomalyDB: anomalyRepo,
+		Logger:    logger,
+	}
+	blockDeps := indexer.BlockIndexerDeps{
+		RPC:       rpcClient,
+		BatchRPC:  rpcClient,
+		DB:        blockRepo,
+		TxDB:      txRepo,
+		AnomalyDB: anomalyRepo,
+		Store:     store,
+		Reorgs:    reorgHandler,
+		Workers:   workers,
+		BatchSize: batchSize,
+	}
+	if fetchMode == "hashes" {
+		blockDeps.TxRPC = rpcClient
+		blockDeps.TxWorkers = txWorkers
+		txDeps.BatchRPC = nil
+		txDeps.Workers = txWorkers
+	}
+
+	txIndexer := indexer.NewTransactionIndexer(txDeps)
+
+	dagIndexer := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
+		ParentsRPC:  rpcClient,
+		GHOSTDAGRPC: rpcClient,
+		DAGDB:       dagRepo,
+		Logger:      logger,
+	})
+
+	stages, err := buildStages(stageNames, stagePolicies, stageRetries, txIndexer, dagIndexer)
+	if err != nil {
+		logger.Fatal("Invalid stage configuration", zap.Error(err))
+	}
 
-	// Create block indexer
-	blockIndexer := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+	// Create indexing pipeline: block, then receipts/logs and DAG data in the same transaction
+	pipeline := indexer.NewPipeline(indexer.PipelineDeps{
+		Blocks: blockDeps,
+		Stages: stages,
+		Logger: logger,
+	})
+
+	// Resume from the last committed block
+	checkpointer := indexer.NewCheckpointer(indexer.CheckpointerDeps{
 		RPC:    rpcClient,
-		DB:     blockRepo,
-		TxDB:   txRepo,
+		Blocks: blockRepo,
+		Store:  checkpointRepo,
 		Logger: logger,
 	})
 
-	// Start indexing loop
-	logger.Info("Starting indexer loop")
+	nextBlock, err := checkpointer.Resume(ctx)
+	if err != nil {
+		logger.Fatal("Failed to resume from checkpoint", zap.Error(err))
+	}
+
+	// Index DAG blocks that never appear by number; blocks before the starting point are left alone
+	var dagSyncer *indexer.DAGSyncer
+	if syncMode == "dag" {
+		dagSyncer = indexer.NewDAGSyncer(indexer.DAGSyncerDeps{
+			RPC:       rpcClient,
+			Gaps:      blockRepo,
+			Indexer:   pipeline.Blocks(),
+			MinNumber: nextBlock,
+			Logger:    logger,
+		})
+	}
+
+	// Follow new blocks pushed over WebSocket, polling while the subscription is down
+	var subscriber interfaces.NewBlockSubscriber
+	if wsURL != "" {
+		wsClient := rpc.NewWSClient(wsURL, rpc.WithWSLogger(logger))
+		defer wsClient.Close()
+		subscriber = wsClient
+	}
+
+	headTracker := indexer.NewHeadTracker(indexer.HeadTrackerDeps{
+		RPC:          rpcClient,
+		Subscriber:   subscriber,
+		PollInterval: 2 * time.Second,
+		Logger:       logger,
+	})
 
-	// Track last indexed block
-	var lastIndexedBlock int64 = -1
+	// Start indexing loop
+	logger.Info("Starting indexer loop", zap.Int64("next_block", nextBlock))
 
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	heads := headTracker.Heads(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
 			logger.Info("Shutting down indexer")
 			return
-		case <-ticker.C:
-			// Get current block number
-			currentBlock, err := rpcClient.BlockNumber(ctx)
-			if err != nil {
-				logger.Error("Failed to get current block number", zap.Error(err))
-				continue
-			}
-
-			currentBlockNum := currentBlock.Int64()
-
+		case currentBlockNum := <-heads:
 			// Index new blocks
-			if currentBlockNum > lastIndexedBlock {
-				startBlock := lastIndexedBlock + 1
-				if startBlock < 0 {
-					startBlock = 0
-				}
+			if currentBlockNum >= nextBlock {
+				startBlock := nextBlock
 
 				endBlock := currentBlockNum
 				if endBlock-startBlock > int64(batchSize) {
 					endBlock = startBlock + int64(batchSize)
 				}
 
-				for blockNum := startBlock; blockNum <= endBlock; blockNum++ {
-					blockBigInt := big.NewInt(blockNum)
+				// Far behind the tip: write the whole batch in bulk
+				if currentBlockNum-startBlock > int64(batchSize) {
+					endHash, err := pipeline.IndexBlockRange(ctx, big.NewInt(startBlock), big.NewInt(endBlock))
+					if err != nil {
+						logger.Error("Failed to index block range",
+							zap.Int64("from", startBlock),
+							zap.Int64("to", endBlock),
+							zap.Error(err),
+						)
+						continue
+					}
 
-				// Index block
-				if err := blockIndexer.IndexBlock(ctx, blockBigInt); err != nil {
-					logger.Error("Failed to index block",
-						zap.Int64("block", blockNum),
-						zap.Error(err),
+					if dagSyncer != nil {
+						if _, err := dagSyncer.SyncRange(ctx, startBlock, endBlock); err != nil {
+							logger.Error("Failed to sync DAG blocks",
+								zap.Int64("from", startBlock),
+								zap.Int64("to", endBlock),
+								zap.Error(err),
+							)
+							continue
+						}
+					}
+
+					if err := checkpointer.Commit(ctx, endBlock, endHash); err != nil {
+						logger.Error("Failed to save checkpoint",
+							zap.Int64("block", endBlock),
+							zap.Error(err),
+						)
+						continue
+					}
+
+					nextBlock = endBlock + 1
+					logger.Info("Caught up block range",
+						zap.Int64("from", startBlock),
+						zap.Int64("to", endBlock),
+						zap.Int64("current", currentBlockNum),
 					)
 					continue
 				}
 
-				lastIndexedBlock = blockNum
-				logger.Info("Indexed block",
-					zap.Int64("block", blockNum),
-					zap.Int64("current", currentBlockNum),
-				)
+				for blockNum := startBlock; blockNum <= endBlock; blockNum++ {
+					blockBigInt := big.NewInt(blockNum)
+
+					// Index block; stop at the first failure so the checkpoint never skips a block
+					blockHash, err := pipeline.IndexBlock(ctx, blockBigInt)
+					if err != nil {
+						logger.Error("Failed to index block",
+							zap.Int64("block", blockNum),
+							zap.Error(err),
+						)
+						break
+					}
+
+					if dagSyncer != nil {
+						if _, err := dagSyncer.SyncRange(ctx, blockNum, blockNum); err != nil {
+							logger.Error("Failed to sync DAG blocks",
+								zap.Int64("block", blockNum),
+								zap.Error(err),
+							)
+							break
+						}
+					}
+
+					if err := checkpointer.Commit(ctx, blockNum, blockHash); err != nil {
+						logger.Error("Failed to save checkpoint",
+							zap.Int64("block", blockNum),
+							zap.Error(err),
+						)
+						break
+					}
+
+					nextBlock = blockNum + 1
+					logger.Info("Indexed block",
+						zap.Int64("block", blockNum),
+						zap.Int64("current", currentBlockNum),
+					)
 				}
 			}
 		}
 	}
 }
 
+// buildStages builds the pipeline stages named in names ("receipts,dag") with
+// policies given as "stage:fail" or "stage:skip"; unlisted stages fail the block
+func buildStages(
+	names string,
+	policies string,
+	retries int,
+	txIndexer *indexer.TransactionIndexer,
+	dagIndexer *indexer.DAGIndexer,
+) ([]indexer.Stage, error) {
+	onError := make(map[string]indexer.ErrorPolicy)
+	for _, entry := range strings.Split(policies, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("stage policy %q: expected stage:policy", entry)
+		}
+
+		policy, err := indexer.ParseErrorPolicy(value)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %w", name, err)
+		}
+		onError[name] = policy
+	}
+
+	var stages []indexer.Stage
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+
+		var stage indexer.Stage
+		switch name {
+		case "":
+			continue
+		case "receipts":
+			stage = indexer.ReceiptsStage(txIndexer, onError[name])
+		case "dag":
+			stage = indexer.DAGStage(dagIndexer, onError[name])
+		default:
+			return nil, fmt.Errorf("unknown stage %q", name)
+		}
+
+		stage.Retries = retries
+		stages = append(stages, stage)
+	}
+
+	return stages, nil
+}
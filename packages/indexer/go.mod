@@ -4,6 +4,7 @@ go 1.23.0
 
 require (
 	github.com/ethereum/go-ethereum v1.13.15
+	github.com/gorilla/websocket v1.5.3
 	github.com/jackc/pgx/v5 v5.7.6
 	github.com/stretchr/testify v1.9.0
 	go.uber.org/zap v1.27.1
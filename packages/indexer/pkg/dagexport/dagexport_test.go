@@ -0,0 +1,170 @@
+package dagexport_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/dagexport"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+func hash(n int) domain.Hash {
+	return domain.Hash(fmt.Sprintf("0x%064x", n))
+}
+
+// window is chain block 2 over parent 1 (outside the window), red block 3 and
+// unmerged block 4, which merges 2 (selected) and 3
+func window() []*domain.DAGBlock {
+	blue, red := true, false
+	return []*domain.DAGBlock{
+		{Hash: hash(2), Number: 10, BlueScore: 20, IsChainBlock: true, IsBlue: &blue,
+			Parents: []domain.Hash{hash(1)}, SelectedParent: hash(1)},
+		{Hash: hash(3), Number: 10, BlueScore: 20, IsBlue: &red,
+			Parents: []domain.Hash{hash(1)}, SelectedParent: hash(1)},
+		{Hash: hash(4), Number: 11, BlueScore: 21,
+			Parents: []domain.Hash{hash(2), hash(3)}, SelectedParent: hash(2)},
+	}
+}
+
+func expectedGraph() *dagexport.Graph {
+	return &dagexport.Graph{
+		Nodes: []dagexport.Node{
+			{Hash: hash(2), Number: 10, BlueScore: 20, Colour: dagexport.ColourBlue, IsChainBlock: true},
+			{Hash: hash(3), Number: 10, BlueScore: 20, Colour: dagexport.ColourRed},
+			{Hash: hash(4), Number: 11, BlueScore: 21, Colour: dagexport.ColourUnknown},
+		},
+		Edges: []dagexport.Edge{
+			{Parent: hash(2), Child: hash(4), IsSelectedParent: true},
+			{Parent: hash(3), Child: hash(4)},
+		},
+	}
+}
+
+func TestExporter_ByNumber(t *testing.T) {
+	mockDAG := new(mocks.MockDAGWindowReader)
+	ctx := context.Background()
+
+	mockDAG.On("GetDAGWindow", ctx, int64(10), int64(11)).Return(window(), nil)
+
+	exporter := dagexport.NewExporter(dagexport.ExporterDeps{DAG: mockDAG})
+	graph, err := exporter.ByNumber(ctx, 10, 11)
+	require.NoError(t, err)
+
+	// Edges to block 1 leave the window and are dropped
+	assert.Equal(t, expectedGraph(), graph)
+
+	_, err = exporter.ByNumber(ctx, 11, 10)
+	assert.Error(t, err)
+	mockDAG.AssertExpectations(t)
+}
+
+func TestExporter_ByBlueScore(t *testing.T) {
+	mockDAG := new(mocks.MockDAGWindowReader)
+	ctx := context.Background()
+
+	mockDAG.On("GetDAGWindowByBlueScore", ctx, uint64(20), uint64(21)).Return(window(), nil)
+	mockDAG.On("GetDAGWindowByBlueScore", ctx, uint64(30), uint64(30)).Return(nil, assert.AnError)
+	mockDAG.On("GetDAGWindowByBlueScore", ctx, uint64(40), uint64(40)).Return([]*domain.DAGBlock{}, nil)
+
+	exporter := dagexport.NewExporter(dagexport.ExporterDeps{DAG: mockDAG})
+	graph, err := exporter.ByBlueScore(ctx, 20, 21)
+	require.NoError(t, err)
+	assert.Equal(t, expectedGraph(), graph)
+
+	_, err = exporter.ByBlueScore(ctx, 30, 30)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// An empty window still has empty node and edge lists
+	empty, err := exporter.ByBlueScore(ctx, 40, 40)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, empty.Write(&out, dagexport.FormatJSON))
+	assert.JSONEq(t, `{"nodes": [], "edges": []}`, out.String())
+	mockDAG.AssertExpectations(t)
+}
+
+func TestGraph_Write_JSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, expectedGraph().Write(&out, dagexport.FormatJSON))
+
+	var doc struct {
+		Nodes []map[string]any `json:"nodes"`
+		Edges []map[string]any `json:"edges"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	require.Len(t, doc.Nodes, 3)
+	assert.Equal(t, map[string]any{
+		"hash": hash(2).String(), "number": float64(10), "blueScore": float64(20), "colour": "blue", "isChainBlock": true,
+	}, doc.Nodes[0])
+	assert.Equal(t, map[string]any{"parent": hash(2).String(), "child": hash(4).String(), "isSelectedParent": true}, doc.Edges[0])
+}
+
+func TestGraph_Write_DOT(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, expectedGraph().Write(&out, dagexport.FormatDOT))
+	dot := out.String()
+
+	assert.True(t, strings.HasPrefix(dot, "digraph dag {\n"))
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+	assert.Contains(t, dot, fmt.Sprintf("%q [label=", hash(2)))
+	assert.Contains(t, dot, `fillcolor="lightblue", peripheries=2, number=10, blue_score=20, colour="blue", chain_block=true];`)
+	assert.Contains(t, dot, `fillcolor="salmon", peripheries=1`)
+	assert.Contains(t, dot, fmt.Sprintf("%q -> %q [style=bold, selected_parent=true];", hash(4), hash(2)))
+	assert.Contains(t, dot, fmt.Sprintf("%q -> %q [style=solid, selected_parent=false];", hash(4), hash(3)))
+}
+
+func TestGraph_Write_GraphML(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, expectedGraph().Write(&out, dagexport.FormatGraphML))
+	assert.True(t, strings.HasPrefix(out.String(), `<?xml version="1.0" encoding="UTF-8"?>`))
+
+	var doc struct {
+		Keys []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"key"`
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &doc))
+
+	assert.Len(t, doc.Keys, 5)
+	assert.Equal(t, "directed", doc.Graph.EdgeDefault)
+	require.Len(t, doc.Graph.Nodes, 3)
+	assert.Equal(t, hash(3).String(), doc.Graph.Nodes[1].ID)
+	assert.Equal(t, "red", doc.Graph.Nodes[1].Data[2].Value)
+	require.Len(t, doc.Graph.Edges, 2)
+	assert.Equal(t, hash(4).String(), doc.Graph.Edges[0].Source)
+	assert.Equal(t, hash(2).String(), doc.Graph.Edges[0].Target)
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"dot", "graphml", "json"} {
+		format, err := dagexport.ParseFormat(name)
+		require.NoError(t, err)
+		assert.Equal(t, dagexport.Format(name), format)
+	}
+
+	_, err := dagexport.ParseFormat("svg")
+	assert.Error(t, err)
+}
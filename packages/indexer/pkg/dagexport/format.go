@@ -0,0 +1,189 @@
+package dagexport
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Format is an export file format
+type Format string
+
+// Supported formats
+const (
+	FormatDOT     Format = "dot"
+	FormatGraphML Format = "graphml"
+	FormatJSON    Format = "json"
+)
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatDOT, FormatGraphML, FormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q (want dot, graphml or json)", name)
+	}
+}
+
+// Write writes the graph to w in the given format
+func (g *Graph) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatDOT:
+		return g.writeDOT(w)
+	case FormatGraphML:
+		return g.writeGraphML(w)
+	case FormatJSON:
+		return g.writeJSON(w)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// dotFill is the Graphviz fill colour of each block colour
+var dotFill = map[Colour]string{
+	ColourBlue:    "lightblue",
+	ColourRed:     "salmon",
+	ColourUnknown: "lightgrey",
+}
+
+// writeDOT writes a digraph with edges pointing from child to parent. Chain blocks get a
+// double border and selected-parent edges are bold; the raw values are kept as attributes.
+func (g *Graph) writeDOT(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "digraph dag {")
+	fmt.Fprintln(out, "\trankdir=RL;")
+	fmt.Fprintln(out, "\tnode [shape=box, style=filled];")
+
+	for _, node := range g.Nodes {
+		peripheries := 1
+		if node.IsChainBlock {
+			peripheries = 2
+		}
+		fmt.Fprintf(out, "\t%q [label=\"%d\\n%s\", fillcolor=%q, peripheries=%d, number=%d, blue_score=%d, colour=%q, chain_block=%t];\n",
+			node.Hash, node.Number, shortHash(node.Hash.String()), dotFill[node.Colour], peripheries,
+			node.Number, node.BlueScore, node.Colour, node.IsChainBlock)
+	}
+
+	for _, edge := range g.Edges {
+		style := "solid"
+		if edge.IsSelectedParent {
+			style = "bold"
+		}
+		fmt.Fprintf(out, "\t%q -> %q [style=%s, selected_parent=%t];\n",
+			edge.Child, edge.Parent, style, edge.IsSelectedParent)
+	}
+
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+// shortHash abbreviates a hash for labels
+func shortHash(hash string) string {
+	if len(hash) <= 10 {
+		return hash
+	}
+	return hash[:10] + "…"
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLKeys declares the node and edge attributes
+var graphMLKeys = []graphMLKey{
+	{ID: "number", For: "node", AttrName: "number", AttrType: "long"},
+	{ID: "blue_score", For: "node", AttrName: "blue_score", AttrType: "long"},
+	{ID: "colour", For: "node", AttrName: "colour", AttrType: "string"},
+	{ID: "chain_block", For: "node", AttrName: "chain_block", AttrType: "boolean"},
+	{ID: "selected_parent", For: "edge", AttrName: "selected_parent", AttrType: "boolean"},
+}
+
+// writeGraphML writes a directed GraphML graph with edges pointing from child to parent
+func (g *Graph) writeGraphML(w io.Writer) error {
+	doc := graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  graphMLKeys,
+		Graph: graphMLGraph{ID: "dag", EdgeDefault: "directed"},
+	}
+
+	for _, node := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: node.Hash.String(),
+			Data: []graphMLData{
+				{Key: "number", Value: strconv.FormatInt(node.Number, 10)},
+				{Key: "blue_score", Value: strconv.FormatUint(node.BlueScore, 10)},
+				{Key: "colour", Value: string(node.Colour)},
+				{Key: "chain_block", Value: strconv.FormatBool(node.IsChainBlock)},
+			},
+		})
+	}
+
+	for _, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: edge.Child.String(),
+			Target: edge.Parent.String(),
+			Data:   []graphMLData{{Key: "selected_parent", Value: strconv.FormatBool(edge.IsSelectedParent)}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encode GraphML: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeJSON writes the graph as a nodes/edges document
+func (g *Graph) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(g); err != nil {
+		return fmt.Errorf("encode JSON: %w", err)
+	}
+	return nil
+}
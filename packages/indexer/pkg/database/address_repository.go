@@ -12,23 +12,27 @@ import (
 
 // AddressRepository implements AddressReader and AddressWriter interfaces
 type AddressRepository struct {
-	conn   *pgx.Conn
+	db     DBTX
 	logger *zap.Logger
 }
 
 // NewAddressRepository creates a new AddressRepository
-func NewAddressRepository(conn *pgx.Conn, logger *zap.Logger) *AddressRepository {
+func NewAddressRepository(db DBTX, logger *zap.Logger) *AddressRepository {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 	return &AddressRepository{
-		conn:   conn,
+		db:     db,
 		logger: logger,
 	}
 }
 
-// SaveAddress saves an address to the database
-func (r *AddressRepository) SaveAddress(ctx context.Context, address *domain.Address) error {
+// SaveAddress saves an account to the database
+func (r *AddressRepository) SaveAddress(ctx context.Context, account *domain.Account) error {
+	if !account.Address.IsValid() {
+		return fmt.Errorf("save address: %w", domain.ErrInvalidAddress)
+	}
+
 	query := `
 		INSERT INTO addresses (
 			address, balance, nonce, is_contract, contract_code,
@@ -45,26 +49,20 @@ func (r *AddressRepository) SaveAddress(ctx context.Context, address *domain.Add
 			updated_at = NOW()
 	`
 
-	var contractCode *string
-	if len(address.ContractCode) > 0 {
-		codeStr := fmt.Sprintf("%x", address.ContractCode)
-		contractCode = &codeStr
-	}
-
-	balanceStr := address.Balance.String()
+	balanceStr := account.Balance.String()
 
-	_, err := r.conn.Exec(ctx, query,
-		address.Address,
+	_, err := r.db.Exec(ctx, query,
+		account.Address,
 		balanceStr,
-		address.Nonce,
-		address.IsContract,
-		contractCode,
-		address.TransactionCount,
+		account.Nonce,
+		account.IsContract,
+		nullHexFromBytes(account.ContractCode),
+		account.TransactionCount,
 	)
 
 	if err != nil {
 		r.logger.Error("failed to save address",
-			zap.String("address", address.Address),
+			zap.String("address", account.Address.String()),
 			zap.Error(err))
 		return fmt.Errorf("save address: %w", err)
 	}
@@ -72,8 +70,8 @@ func (r *AddressRepository) SaveAddress(ctx context.Context, address *domain.Add
 	return nil
 }
 
-// GetAddress retrieves an address by its address string
-func (r *AddressRepository) GetAddress(ctx context.Context, address string) (*domain.Address, error) {
+// GetAddress retrieves the account of an address
+func (r *AddressRepository) GetAddress(ctx context.Context, address domain.Address) (*domain.Account, error) {
 	query := `
 		SELECT address, balance, nonce, is_contract, contract_code,
 		       transaction_count
@@ -81,12 +79,12 @@ func (r *AddressRepository) GetAddress(ctx context.Context, address string) (*do
 		WHERE address = $1
 	`
 
-	var addr domain.Address
+	var addr domain.Account
 	var balanceStr string
 	var contractCode *string
 	var txCount int64
 
-	err := r.conn.QueryRow(ctx, query, address).Scan(
+	err := r.db.QueryRow(ctx, query, address).Scan(
 		&addr.Address,
 		&balanceStr,
 		&addr.Nonce,
@@ -96,11 +94,11 @@ func (r *AddressRepository) GetAddress(ctx context.Context, address string) (*do
 	)
 
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("address not found: %s", address)
+		return nil, fmt.Errorf("address %w: %s", domain.ErrNotFound, address)
 	}
 	if err != nil {
 		r.logger.Error("failed to get address",
-			zap.String("address", address),
+			zap.String("address", address.String()),
 			zap.Error(err))
 		return nil, fmt.Errorf("get address: %w", err)
 	}
@@ -113,10 +111,10 @@ func (r *AddressRepository) GetAddress(ctx context.Context, address string) (*do
 	addr.Balance = balance
 
 	// Parse contract code
-	if contractCode != nil && *contractCode != "" {
-		// Convert hex string to bytes
-		// In production, we'd use proper hex decoding
-		addr.ContractCode = []byte(*contractCode)
+	if contractCode != nil {
+		if addr.ContractCode, err = bytesFromHex(contractCode); err != nil {
+			return nil, fmt.Errorf("parse contract code: %w", err)
+		}
 	}
 
 	addr.TransactionCount = txCount
@@ -125,7 +123,7 @@ func (r *AddressRepository) GetAddress(ctx context.Context, address string) (*do
 }
 
 // GetAddressBalance retrieves the balance for an address
-func (r *AddressRepository) GetAddressBalance(ctx context.Context, address string) (*big.Int, error) {
+func (r *AddressRepository) GetAddressBalance(ctx context.Context, address domain.Address) (*big.Int, error) {
 	query := `
 		SELECT balance
 		FROM addresses
@@ -133,7 +131,7 @@ func (r *AddressRepository) GetAddressBalance(ctx context.Context, address strin
 	`
 
 	var balanceStr string
-	err := r.conn.QueryRow(ctx, query, address).Scan(&balanceStr)
+	err := r.db.QueryRow(ctx, query, address).Scan(&balanceStr)
 
 	if err == pgx.ErrNoRows {
 		// Return zero balance for non-existent address
@@ -141,7 +139,7 @@ func (r *AddressRepository) GetAddressBalance(ctx context.Context, address strin
 	}
 	if err != nil {
 		r.logger.Error("failed to get address balance",
-			zap.String("address", address),
+			zap.String("address", address.String()),
 			zap.Error(err))
 		return nil, fmt.Errorf("get address balance: %w", err)
 	}
@@ -154,67 +152,74 @@ func (r *AddressRepository) GetAddressBalance(ctx context.Context, address strin
 	return balance, nil
 }
 
-// UpdateAddressBalance updates the balance for an address
-func (r *AddressRepository) UpdateAddressBalance(ctx context.Context, address string, balance *big.Int) error {
-	query := `
-		UPDATE addresses
-		SET balance = $1, updated_at = NOW()
-		WHERE address = $2
-	`
-
-	balanceStr := balance.String()
-
-	result, err := r.conn.Exec(ctx, query, balanceStr, address)
-	if err != nil {
-		r.logger.Error("failed to update address balance",
-			zap.String("address", address),
-			zap.Error(err))
-		return fmt.Errorf("update address balance: %w", err)
+// UpdateAddress sets the account fields that the update carries.
+// An address that is not stored yet is created with the update applied to an empty account.
+func (r *AddressRepository) UpdateAddress(ctx context.Context, address domain.Address, update domain.AccountUpdate) error {
+	if update.IsEmpty() {
+		return nil
 	}
-
-	if result.RowsAffected() == 0 {
-		// Address doesn't exist, create it
-		addr := &domain.Address{
-			Address:        address,
-			Balance:        balance,
-			Nonce:          0,
-			IsContract:     false,
-			TransactionCount: 0,
-		}
-		return r.SaveAddress(ctx, addr)
+	if err := update.Validate(); err != nil {
+		return fmt.Errorf("invalid address update: %w", err)
 	}
 
-	return nil
-}
+	var set assignments
+	if update.Balance != nil {
+		set.set("balance", update.Balance.String())
+	}
+	if update.Nonce != nil {
+		set.set("nonce", *update.Nonce)
+	}
+	if update.IsContract != nil {
+		set.set("is_contract", *update.IsContract)
+	}
+	if update.TransactionCount != nil {
+		set.set("transaction_count", *update.TransactionCount)
+	}
 
-// UpdateAddressNonce updates the nonce for an address
-func (r *AddressRepository) UpdateAddressNonce(ctx context.Context, address string, nonce uint64) error {
-	query := `
+	query := fmt.Sprintf(`
 		UPDATE addresses
-		SET nonce = $1, updated_at = NOW()
-		WHERE address = $2
-	`
+		SET %s
+		WHERE address = $%d
+	`, set.clause("updated_at = NOW()"), set.next())
 
-	result, err := r.conn.Exec(ctx, query, nonce, address)
+	result, err := r.db.Exec(ctx, query, append(set.args, address)...)
 	if err != nil {
-		r.logger.Error("failed to update address nonce",
-			zap.String("address", address),
+		r.logger.Error("failed to update address",
+			zap.String("address", address.String()),
 			zap.Error(err))
-		return fmt.Errorf("update address nonce: %w", err)
+		return fmt.Errorf("update address: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
 		// Address doesn't exist, create it
-		addr := &domain.Address{
-			Address:        address,
-			Balance:        big.NewInt(0),
-			Nonce:          nonce,
-			IsContract:     false,
-			TransactionCount: 0,
+		account := &domain.Account{
+			Address: address,
+			Balance: big.NewInt(0),
 		}
-		return r.SaveAddress(ctx, addr)
+		if update.Balance != nil {
+			account.Balance = update.Balance
+		}
+		if update.Nonce != nil {
+			account.Nonce = *update.Nonce
+		}
+		if update.IsContract != nil {
+			account.IsContract = *update.IsContract
+		}
+		if update.TransactionCount != nil {
+			account.TransactionCount = *update.TransactionCount
+		}
+		return r.SaveAddress(ctx, account)
 	}
 
 	return nil
 }
 
+// UpdateAddressBalance updates the balance for an address
+func (r *AddressRepository) UpdateAddressBalance(ctx context.Context, address domain.Address, balance *big.Int) error {
+	return r.UpdateAddress(ctx, address, domain.AccountUpdate{Balance: balance})
+}
+
+// UpdateAddressNonce updates the nonce for an address
+func (r *AddressRepository) UpdateAddressNonce(ctx context.Context, address domain.Address, nonce uint64) error {
+	return r.UpdateAddress(ctx, address, domain.AccountUpdate{Nonce: &nonce})
+}
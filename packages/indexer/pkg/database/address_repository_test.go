@@ -23,8 +23,8 @@ func TestAddressRepository_SaveAddress(t *testing.T) {
 	ctx := context.Background()
 	repo := database.NewAddressRepository(conn, zap.NewNop())
 
-	address := &domain.Address{
-		Address:        "0x" + strings.Repeat("a", 40),
+	address := &domain.Account{
+		Address:        domain.Address("0x" + strings.Repeat("a", 40)),
 		Balance:        big.NewInt(1000000000000000000), // 1 ETH
 		Nonce:          5,
 		IsContract:     false,
@@ -52,8 +52,8 @@ func TestAddressRepository_GetAddress(t *testing.T) {
 	ctx := context.Background()
 	repo := database.NewAddressRepository(conn, zap.NewNop())
 
-	address := &domain.Address{
-		Address:        "0x" + strings.Repeat("a", 40),
+	address := &domain.Account{
+		Address:        domain.Address("0x" + strings.Repeat("a", 40)),
 		Balance:        big.NewInt(1000000000000000000),
 		Nonce:          5,
 		IsContract:     false,
@@ -70,7 +70,7 @@ func TestAddressRepository_GetAddress(t *testing.T) {
 	assert.Equal(t, address.Balance.String(), saved.Balance.String())
 
 	// Test non-existent address
-	_, err = repo.GetAddress(ctx, "0x"+strings.Repeat("z", 40))
+	_, err = repo.GetAddress(ctx, domain.Address("0x"+strings.Repeat("z", 40)))
 	assert.Error(t, err)
 }
 
@@ -84,8 +84,8 @@ func TestAddressRepository_GetAddressBalance(t *testing.T) {
 	ctx := context.Background()
 	repo := database.NewAddressRepository(conn, zap.NewNop())
 
-	address := &domain.Address{
-		Address:        "0x" + strings.Repeat("a", 40),
+	address := &domain.Account{
+		Address:        domain.Address("0x" + strings.Repeat("a", 40)),
 		Balance:        big.NewInt(1000000000000000000),
 		Nonce:          5,
 		IsContract:     false,
@@ -111,8 +111,8 @@ func TestAddressRepository_UpdateAddressBalance(t *testing.T) {
 	ctx := context.Background()
 	repo := database.NewAddressRepository(conn, zap.NewNop())
 
-	address := &domain.Address{
-		Address:        "0x" + strings.Repeat("a", 40),
+	address := &domain.Account{
+		Address:        domain.Address("0x" + strings.Repeat("a", 40)),
 		Balance:        big.NewInt(1000000000000000000),
 		Nonce:          5,
 		IsContract:     false,
@@ -143,8 +143,8 @@ func TestAddressRepository_UpdateAddressNonce(t *testing.T) {
 	ctx := context.Background()
 	repo := database.NewAddressRepository(conn, zap.NewNop())
 
-	address := &domain.Address{
-		Address:        "0x" + strings.Repeat("a", 40),
+	address := &domain.Account{
+		Address:        domain.Address("0x" + strings.Repeat("a", 40)),
 		Balance:        big.NewInt(1000000000000000000),
 		Nonce:          5,
 		IsContract:     false,
@@ -164,6 +164,32 @@ func TestAddressRepository_UpdateAddressNonce(t *testing.T) {
 	assert.Equal(t, uint64(10), updated.Nonce)
 }
 
+func TestAddressRepository_UpdateAddress_CreatesMissingAddress(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := database.NewAddressRepository(conn, zap.NewNop())
+
+	address := domain.Address("0x" + strings.Repeat("b", 40))
+	isContract, txCount := true, int64(3)
+
+	err := repo.UpdateAddress(ctx, address, domain.AccountUpdate{
+		IsContract:       &isContract,
+		TransactionCount: &txCount,
+	})
+	require.NoError(t, err)
+
+	created, err := repo.GetAddress(ctx, address)
+	require.NoError(t, err)
+	assert.True(t, created.IsContract)
+	assert.Equal(t, int64(3), created.TransactionCount)
+	assert.Equal(t, int64(0), created.Balance.Int64())
+}
+
 func TestAddressRepository_ContractAddress(t *testing.T) {
 	conn, cleanup := setupBlockRepoTestDB(t)
 	if conn == nil {
@@ -175,8 +201,8 @@ func TestAddressRepository_ContractAddress(t *testing.T) {
 	repo := database.NewAddressRepository(conn, zap.NewNop())
 
 	// Contract address
-	address := &domain.Address{
-		Address:        "0x" + strings.Repeat("b", 40),
+	address := &domain.Account{
+		Address:        domain.Address("0x" + strings.Repeat("b", 40)),
 		Balance:        big.NewInt(0),
 		Nonce:          0,
 		IsContract:     true,
@@ -191,6 +217,6 @@ func TestAddressRepository_ContractAddress(t *testing.T) {
 	saved, err := repo.GetAddress(ctx, address.Address)
 	require.NoError(t, err)
 	assert.True(t, saved.IsContract)
-	assert.NotNil(t, saved.ContractCode)
+	assert.Equal(t, address.ContractCode, saved.ContractCode)
 }
 
@@ -0,0 +1,154 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// binaryPayloads are byte strings that raw TEXT storage could not hold
+func binaryPayloads() map[string][]byte {
+	every := make([]byte, 256)
+	for i := range every {
+		every[i] = byte(i)
+	}
+
+	return map[string][]byte{
+		"empty":         {},
+		"nul bytes":     {0x00, 0x00, 0x01},
+		"invalid utf-8": {0xff, 0xfe, 0xc3, 0x28},
+		"every byte":    every,
+		"selector":      {0xa9, 0x05, 0x9c, 0xbb},
+	}
+}
+
+func TestBinaryPayloads_RoundTrip(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	block := &domain.Block{
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
+		Number:       100,
+		ParentHashes: []domain.Hash{},
+		Timestamp:    time.Now().Unix(),
+	}
+	require.NoError(t, database.NewBlockRepository(conn, zap.NewNop()).SaveBlock(ctx, block))
+
+	txRepo := database.NewTransactionRepository(conn, zap.NewNop())
+	logRepo := database.NewLogRepository(conn, zap.NewNop())
+	addrRepo := database.NewAddressRepository(conn, zap.NewNop())
+
+	i := 0
+	for name, payload := range binaryPayloads() {
+		i++
+		t.Run(name, func(t *testing.T) {
+			tx := &domain.Transaction{
+				Hash:        domain.Hash(fmt.Sprintf("0x%064x", i)),
+				BlockHash:   block.Hash,
+				BlockNumber: block.Number,
+				From:        domain.Address("0x" + strings.Repeat("c", 40)),
+				Value:       big.NewInt(0),
+				Input:       payload,
+			}
+			require.NoError(t, txRepo.SaveTransaction(ctx, tx))
+
+			savedTx, err := txRepo.GetTransactionByHash(ctx, tx.Hash)
+			require.NoError(t, err)
+			assert.Equal(t, payload, savedTx.Input)
+
+			log := &domain.Log{
+				TransactionHash: tx.Hash,
+				Address:         domain.Address("0x" + strings.Repeat("e", 40)),
+				Topics:          []string{},
+				Data:            payload,
+				BlockNumber:     block.Number,
+				BlockHash:       block.Hash,
+			}
+			require.NoError(t, logRepo.SaveLog(ctx, log))
+
+			logs, err := logRepo.GetLogsByTransactionHash(ctx, tx.Hash)
+			require.NoError(t, err)
+			require.Len(t, logs, 1)
+			assert.Equal(t, payload, logs[0].Data)
+
+			address := &domain.Account{
+				Address:      domain.Address(fmt.Sprintf("0x%040x", i)),
+				Balance:      big.NewInt(0),
+				IsContract:   len(payload) > 0,
+				ContractCode: payload,
+			}
+			require.NoError(t, addrRepo.SaveAddress(ctx, address))
+
+			savedAddr, err := addrRepo.GetAddress(ctx, address.Address)
+			require.NoError(t, err)
+			if len(payload) == 0 {
+				assert.Nil(t, savedAddr.ContractCode, "accounts without code store NULL")
+			} else {
+				assert.Equal(t, payload, savedAddr.ContractCode)
+			}
+		})
+	}
+}
+
+func TestBulkWriter_BinaryPayloads_RoundTrip(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	payload := binaryPayloads()["every byte"]
+	txHash := domain.Hash("0x" + strings.Repeat("b", 64))
+
+	block := &domain.Block{
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
+		Number:       100,
+		ParentHashes: []domain.Hash{},
+		Timestamp:    time.Now().Unix(),
+		Transactions: []domain.Transaction{{
+			Hash:        txHash,
+			BlockHash:   domain.Hash("0x" + strings.Repeat("a", 64)),
+			BlockNumber: 100,
+			From:        domain.Address("0x" + strings.Repeat("c", 40)),
+			Value:       big.NewInt(0),
+			Input:       payload,
+		}},
+	}
+
+	writer := database.NewBulkWriter(conn, zap.NewNop())
+	require.NoError(t, writer.WriteBatch(ctx, []*domain.Block{block}))
+	require.NoError(t, writer.WriteLogs(ctx, []*domain.Log{{
+		TransactionHash: txHash,
+		Address:         domain.Address("0x" + strings.Repeat("e", 40)),
+		Topics:          []string{},
+		Data:            payload,
+		BlockNumber:     block.Number,
+		BlockHash:       block.Hash,
+	}}))
+
+	savedTx, err := database.NewTransactionRepository(conn, zap.NewNop()).GetTransactionByHash(ctx, txHash)
+	require.NoError(t, err)
+	assert.Equal(t, payload, savedTx.Input)
+
+	logs, err := database.NewLogRepository(conn, zap.NewNop()).GetLogsByTransactionHash(ctx, txHash)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, payload, logs[0].Data)
+}
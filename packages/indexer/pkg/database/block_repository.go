@@ -3,94 +3,76 @@ package database
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
 )
 
 // BlockRepository implements BlockReader and BlockWriter interfaces
 type BlockRepository struct {
-	conn   *pgx.Conn
+	db     DBTX
 	logger *zap.Logger
 }
 
 // NewBlockRepository creates a new BlockRepository
-func NewBlockRepository(conn *pgx.Conn, logger *zap.Logger) *BlockRepository {
+func NewBlockRepository(db DBTX, logger *zap.Logger) *BlockRepository {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 	return &BlockRepository{
-		conn:   conn,
+		db:     db,
 		logger: logger,
 	}
 }
 
+// blockColumns are the columns read by scanBlock, in scan order
+const blockColumns = `
+		hash, number, parent_hashes, timestamp, miner_address,
+		gas_limit, gas_used, base_fee_per_gas, blue_score,
+		is_chain_block, selected_parent_hash, transactions_root,
+		state_root, receipts_root, transaction_count,
+		size, difficulty, nonce, extra_data, logs_bloom, mix_hash,
+		blue_work, daa_score, pruning_point_hash`
+
+// blockHeaderUpdates refreshes a stored block on conflict. Header fields are fixed for
+// a hash, but rows indexed before they were recorded need them filled in.
+const blockHeaderUpdates = `
+			gas_used = EXCLUDED.gas_used,
+			base_fee_per_gas = EXCLUDED.base_fee_per_gas,
+			blue_score = EXCLUDED.blue_score,
+			is_chain_block = EXCLUDED.is_chain_block,
+			selected_parent_hash = EXCLUDED.selected_parent_hash,
+			transactions_root = EXCLUDED.transactions_root,
+			state_root = EXCLUDED.state_root,
+			receipts_root = EXCLUDED.receipts_root,
+			size = EXCLUDED.size,
+			difficulty = EXCLUDED.difficulty,
+			nonce = EXCLUDED.nonce,
+			extra_data = EXCLUDED.extra_data,
+			logs_bloom = EXCLUDED.logs_bloom,
+			mix_hash = EXCLUDED.mix_hash,
+			blue_work = EXCLUDED.blue_work,
+			daa_score = EXCLUDED.daa_score,
+			pruning_point_hash = EXCLUDED.pruning_point_hash,
+			indexed_at = NOW()`
+
 // SaveBlock saves a block to the database
 func (r *BlockRepository) SaveBlock(ctx context.Context, block *domain.Block) error {
 	query := `
-		INSERT INTO blocks (
-			hash, number, parent_hashes, timestamp, miner_address,
-			gas_limit, gas_used, base_fee_per_gas, blue_score,
-			is_chain_block, selected_parent_hash, transactions_root,
-			state_root, receipts_root, transaction_count
+		INSERT INTO blocks (` + blockColumns + `
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+			$16, $17, $18, $19, $20, $21, $22, $23, $24
 		)
-		ON CONFLICT (hash) DO UPDATE SET
-			gas_used = EXCLUDED.gas_used,
-			blue_score = EXCLUDED.blue_score,
-			indexed_at = NOW()
-	`
+		ON CONFLICT (hash) DO UPDATE SET` + blockHeaderUpdates
 
-	var baseFee *string
-	if block.BaseFeePerGas != nil {
-		baseFeeStr := fmt.Sprintf("%d", *block.BaseFeePerGas)
-		baseFee = &baseFeeStr
-	}
-
-	var selectedParent *string
-	if block.SelectedParent != "" {
-		selectedParent = &block.SelectedParent
-	}
-
-	var transactionsRoot *string
-	if block.TransactionsRoot != "" {
-		transactionsRoot = &block.TransactionsRoot
-	}
-
-	var stateRoot *string
-	if block.StateRoot != "" {
-		stateRoot = &block.StateRoot
-	}
-
-	var receiptsRoot *string
-	if block.ReceiptsRoot != "" {
-		receiptsRoot = &block.ReceiptsRoot
-	}
-
-	_, err := r.conn.Exec(ctx, query,
-		block.Hash,
-		block.Number,
-		block.ParentHashes,
-		block.Timestamp,
-		block.Miner,
-		block.GasLimit,
-		block.GasUsed,
-		baseFee,
-		block.BlueScore,
-		block.IsChainBlock,
-		selectedParent,
-		transactionsRoot,
-		stateRoot,
-		receiptsRoot,
-		len(block.Transactions),
-	)
+	_, err := r.db.Exec(ctx, query, blockArgs(block)...)
 
 	if err != nil {
 		r.logger.Error("failed to save block",
-			zap.String("hash", block.Hash),
+			zap.String("hash", block.Hash.String()),
 			zap.Error(err))
 		return fmt.Errorf("save block: %w", err)
 	}
@@ -99,105 +81,178 @@ func (r *BlockRepository) SaveBlock(ctx context.Context, block *domain.Block) er
 }
 
 // GetBlockByHash retrieves a block by its hash
-func (r *BlockRepository) GetBlockByHash(ctx context.Context, hash string) (*domain.Block, error) {
-	query := `
-		SELECT hash, number, parent_hashes, timestamp, miner_address,
-		       gas_limit, gas_used, base_fee_per_gas, blue_score,
-		       is_chain_block, selected_parent_hash, transactions_root,
-		       state_root, receipts_root, transaction_count
+func (r *BlockRepository) GetBlockByHash(ctx context.Context, hash domain.Hash) (*domain.Block, error) {
+	query := `SELECT ` + blockColumns + `
 		FROM blocks
 		WHERE hash = $1
 	`
 
-	var block domain.Block
-	var baseFee *string
-	var selectedParent *string
-	var transactionsRoot *string
-	var stateRoot *string
-	var receiptsRoot *string
-
-	var txCount int
-	err := r.conn.QueryRow(ctx, query, hash).Scan(
-		&block.Hash,
-		&block.Number,
-		&block.ParentHashes,
-		&block.Timestamp,
-		&block.Miner,
-		&block.GasLimit,
-		&block.GasUsed,
-		&baseFee,
-		&block.BlueScore,
-		&block.IsChainBlock,
-		&selectedParent,
-		&transactionsRoot,
-		&stateRoot,
-		&receiptsRoot,
-		&txCount,
-	)
-
+	block, err := scanBlock(r.db.QueryRow(ctx, query, hash))
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("block not found: %s", hash)
+		return nil, fmt.Errorf("block %w: %s", domain.ErrNotFound, hash)
 	}
 	if err != nil {
 		r.logger.Error("failed to get block by hash",
-			zap.String("hash", hash),
+			zap.String("hash", hash.String()),
 			zap.Error(err))
 		return nil, fmt.Errorf("get block by hash: %w", err)
 	}
 
-	// Parse optional fields
-	if baseFee != nil {
-		var val uint64
-		fmt.Sscanf(*baseFee, "%d", &val)
-		block.BaseFeePerGas = &val
+	return block, nil
+}
+
+// GetBlockByNumber retrieves a block by its number
+func (r *BlockRepository) GetBlockByNumber(ctx context.Context, number int64) (*domain.Block, error) {
+	query := `SELECT ` + blockColumns + `
+		FROM blocks
+		WHERE number = $1
+		ORDER BY blue_score DESC
+		LIMIT 1
+	`
+
+	block, err := scanBlock(r.db.QueryRow(ctx, query, number))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("block %w: number %d", domain.ErrNotFound, number)
+	}
+	if err != nil {
+		r.logger.Error("failed to get block by number",
+			zap.Int64("number", number),
+			zap.Error(err))
+		return nil, fmt.Errorf("get block by number: %w", err)
 	}
 
-	if selectedParent != nil {
-		block.SelectedParent = *selectedParent
+	return block, nil
+}
+
+// GetLatestBlocks retrieves the latest N blocks ordered by number DESC
+func (r *BlockRepository) GetLatestBlocks(ctx context.Context, limit int) ([]*domain.Block, error) {
+	query := `SELECT ` + blockColumns + `
+		FROM blocks
+		ORDER BY number DESC, blue_score DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("failed to get latest blocks",
+			zap.Int("limit", limit),
+			zap.Error(err))
+		return nil, fmt.Errorf("get latest blocks: %w", err)
 	}
 
-	if transactionsRoot != nil {
-		block.TransactionsRoot = *transactionsRoot
+	return collectBlocks(rows)
+}
+
+// GetChainBlocksAboveBlueScore retrieves all chain blocks with a blue score above the given one
+func (r *BlockRepository) GetChainBlocksAboveBlueScore(ctx context.Context, blueScore uint64) ([]*domain.Block, error) {
+	query := `SELECT ` + blockColumns + `
+		FROM blocks
+		WHERE is_chain_block = true
+		  AND blue_score > $1
+		ORDER BY blue_score ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, blueScore)
+	if err != nil {
+		r.logger.Error("failed to get chain blocks",
+			zap.Uint64("blueScore", blueScore),
+			zap.Error(err))
+		return nil, fmt.Errorf("get chain blocks: %w", err)
 	}
 
-	if stateRoot != nil {
-		block.StateRoot = *stateRoot
+	return collectBlocks(rows)
+}
+
+// collectBlocks scans and closes rows selected with blockColumns
+func collectBlocks(rows pgx.Rows) ([]*domain.Block, error) {
+	defer rows.Close()
+
+	var blocks []*domain.Block
+	for rows.Next() {
+		block, err := scanBlock(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan block: %w", err)
+		}
+		blocks = append(blocks, block)
 	}
 
-	if receiptsRoot != nil {
-		block.ReceiptsRoot = *receiptsRoot
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
 	}
 
-	return &block, nil
+	return blocks, nil
 }
 
-// GetBlockByNumber retrieves a block by its number
-func (r *BlockRepository) GetBlockByNumber(ctx context.Context, number int64) (*domain.Block, error) {
-	query := `
-		SELECT hash, number, parent_hashes, timestamp, miner_address,
-		       gas_limit, gas_used, base_fee_per_gas, blue_score,
-		       is_chain_block, selected_parent_hash, transactions_root,
-		       state_root, receipts_root, transaction_count
-		FROM blocks
-		WHERE number = $1
-		ORDER BY blue_score DESC
-		LIMIT 1
-	`
+// blockArgs encodes a block in blockColumns order
+func blockArgs(block *domain.Block) []any {
+	parentHashes := block.ParentHashes
+	if parentHashes == nil {
+		parentHashes = []domain.Hash{}
+	}
 
+	var extraData *string
+	if block.ExtraData != nil {
+		encoded := hexFromBytes(block.ExtraData)
+		extraData = &encoded
+	}
+
+	var size *int32
+	if block.Size > 0 {
+		sizeVal := int32(block.Size)
+		size = &sizeVal
+	}
+
+	var daaScore *int64
+	if block.DAAScore > 0 {
+		daaScoreVal := int64(block.DAAScore)
+		daaScore = &daaScoreVal
+	}
+
+	return []any{
+		block.Hash,
+		block.Number,
+		parentHashes,
+		block.Timestamp,
+		nullString(block.Miner.String()),
+		int64(block.GasLimit),
+		int64(block.GasUsed),
+		numericFromBig(block.BaseFeePerGas),
+		int64(block.BlueScore),
+		block.IsChainBlock,
+		nullString(block.SelectedParent.String()),
+		nullString(block.TransactionsRoot),
+		nullString(block.StateRoot),
+		nullString(block.ReceiptsRoot),
+		int32(block.CountTransactions()),
+		size,
+		numericFromBig(block.Difficulty),
+		nullString(block.Nonce),
+		extraData,
+		nullString(block.LogsBloom),
+		nullString(block.MixHash),
+		numericFromBig(block.BlueWork),
+		daaScore,
+		nullString(block.PruningPoint.String()),
+	}
+}
+
+// scanBlock reads a row selected with blockColumns
+func scanBlock(row pgx.Row) (*domain.Block, error) {
 	var block domain.Block
-	var baseFee *string
-	var selectedParent *string
-	var transactionsRoot *string
-	var stateRoot *string
-	var receiptsRoot *string
-
-	var txCount int
-	err := r.conn.QueryRow(ctx, query, number).Scan(
+	var baseFee, difficulty, blueWork pgtype.Numeric
+	var miner *domain.Address
+	var selectedParent, pruningPoint *domain.Hash
+	var transactionsRoot, stateRoot, receiptsRoot *string
+	var nonce, extraData, logsBloom, mixHash *string
+	var size *int32
+	var daaScore *int64
+
+	err := row.Scan(
 		&block.Hash,
 		&block.Number,
 		&block.ParentHashes,
 		&block.Timestamp,
-		&block.Miner,
+		&miner,
 		&block.GasLimit,
 		&block.GasUsed,
 		&baseFee,
@@ -207,162 +262,173 @@ func (r *BlockRepository) GetBlockByNumber(ctx context.Context, number int64) (*
 		&transactionsRoot,
 		&stateRoot,
 		&receiptsRoot,
-		&txCount,
+		&block.TransactionCount,
+		&size,
+		&difficulty,
+		&nonce,
+		&extraData,
+		&logsBloom,
+		&mixHash,
+		&blueWork,
+		&daaScore,
+		&pruningPoint,
 	)
-
-	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("block not found: number %d", number)
-	}
 	if err != nil {
-		r.logger.Error("failed to get block by number",
-			zap.Int64("number", number),
-			zap.Error(err))
-		return nil, fmt.Errorf("get block by number: %w", err)
+		return nil, err
 	}
 
 	// Parse optional fields
-	if baseFee != nil {
-		var val uint64
-		fmt.Sscanf(*baseFee, "%d", &val)
-		block.BaseFeePerGas = &val
+	if block.BaseFeePerGas, err = bigFromNumeric(baseFee); err != nil {
+		return nil, fmt.Errorf("parse base fee: %w", err)
 	}
 
-	if selectedParent != nil {
-		block.SelectedParent = *selectedParent
+	if block.Difficulty, err = bigFromNumeric(difficulty); err != nil {
+		return nil, fmt.Errorf("parse difficulty: %w", err)
+	}
+
+	if block.BlueWork, err = bigFromNumeric(blueWork); err != nil {
+		return nil, fmt.Errorf("parse blue work: %w", err)
+	}
+
+	if extraData != nil {
+		if block.ExtraData, err = bytesFromHex(extraData); err != nil {
+			return nil, fmt.Errorf("parse extra data: %w", err)
+		}
+	}
+
+	if size != nil {
+		block.Size = uint64(*size)
 	}
 
-	if transactionsRoot != nil {
-		block.TransactionsRoot = *transactionsRoot
+	if daaScore != nil {
+		block.DAAScore = uint64(*daaScore)
+	}
+
+	if miner != nil {
+		block.Miner = *miner
+	}
+	if selectedParent != nil {
+		block.SelectedParent = *selectedParent
 	}
 
-	if stateRoot != nil {
-		block.StateRoot = *stateRoot
+	if pruningPoint != nil {
+		block.PruningPoint = *pruningPoint
 	}
 
-	if receiptsRoot != nil {
-		block.ReceiptsRoot = *receiptsRoot
+	for _, field := range []struct {
+		src *string
+		dst *string
+	}{
+		{transactionsRoot, &block.TransactionsRoot},
+		{stateRoot, &block.StateRoot},
+		{receiptsRoot, &block.ReceiptsRoot},
+		{nonce, &block.Nonce},
+		{logsBloom, &block.LogsBloom},
+		{mixHash, &block.MixHash},
+	} {
+		if field.src != nil {
+			*field.dst = *field.src
+		}
 	}
 
 	return &block, nil
 }
 
-// GetLatestBlocks retrieves the latest N blocks ordered by number DESC
-func (r *BlockRepository) GetLatestBlocks(ctx context.Context, limit int) ([]*domain.Block, error) {
+// GetMissingParents returns parent hashes of blocks numbered from..to that are not indexed
+func (r *BlockRepository) GetMissingParents(ctx context.Context, fromNumber, toNumber int64) ([]domain.Hash, error) {
 	query := `
-		SELECT hash, number, parent_hashes, timestamp, miner_address,
-		       gas_limit, gas_used, base_fee_per_gas, blue_score,
-		       is_chain_block, selected_parent_hash, transactions_root,
-		       state_root, receipts_root, transaction_count
-		FROM blocks
-		ORDER BY number DESC, blue_score DESC
-		LIMIT $1
+		SELECT DISTINCT parent
+		FROM blocks b, unnest(b.parent_hashes) AS parent
+		WHERE b.number BETWEEN $1 AND $2
+		  AND NOT EXISTS (SELECT 1 FROM blocks p WHERE p.hash = parent)
+		ORDER BY parent
 	`
 
-	rows, err := r.conn.Query(ctx, query, limit)
-	if err != nil {
-		r.logger.Error("failed to get latest blocks",
-			zap.Int("limit", limit),
-			zap.Error(err))
-		return nil, fmt.Errorf("get latest blocks: %w", err)
-	}
-	defer rows.Close()
-
-	var blocks []*domain.Block
-	for rows.Next() {
-		var block domain.Block
-		var baseFee *string
-		var selectedParent *string
-		var transactionsRoot *string
-		var stateRoot *string
-		var receiptsRoot *string
-		var txCount int
-
-		err := rows.Scan(
-			&block.Hash,
-			&block.Number,
-			&block.ParentHashes,
-			&block.Timestamp,
-			&block.Miner,
-			&block.GasLimit,
-			&block.GasUsed,
-			&baseFee,
-			&block.BlueScore,
-			&block.IsChainBlock,
-			&selectedParent,
-			&transactionsRoot,
-			&stateRoot,
-			&receiptsRoot,
-			&txCount,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("scan block: %w", err)
-		}
+	return r.queryHashes(ctx, "get missing parents", query, fromNumber, toNumber)
+}
 
-		// Parse optional fields
-		if baseFee != nil {
-			var val uint64
-			fmt.Sscanf(*baseFee, "%d", &val)
-			block.BaseFeePerGas = &val
-		}
+// FilterMissingBlocks returns the hashes that are not indexed, in no particular order
+func (r *BlockRepository) FilterMissingBlocks(ctx context.Context, hashes []domain.Hash) ([]domain.Hash, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
 
-		if selectedParent != nil {
-			block.SelectedParent = *selectedParent
-		}
+	query := `
+		SELECT h
+		FROM unnest($1::text[]) AS h
+		WHERE NOT EXISTS (SELECT 1 FROM blocks WHERE hash = h)
+	`
 
-		if transactionsRoot != nil {
-			block.TransactionsRoot = *transactionsRoot
-		}
+	return r.queryHashes(ctx, "filter missing blocks", query, hashes)
+}
 
-		if stateRoot != nil {
-			block.StateRoot = *stateRoot
-		}
+// queryHashes runs a query returning a single hash column
+func (r *BlockRepository) queryHashes(ctx context.Context, op string, query string, args ...interface{}) ([]domain.Hash, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to "+op, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
 
-		if receiptsRoot != nil {
-			block.ReceiptsRoot = *receiptsRoot
+	var hashes []domain.Hash
+	for rows.Next() {
+		var hash domain.Hash
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("scan hash: %w", err)
 		}
-
-		blocks = append(blocks, &block)
+		hashes = append(hashes, hash)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows error: %w", err)
 	}
 
-	return blocks, nil
+	return hashes, nil
 }
 
-// UpdateBlock updates block fields
-func (r *BlockRepository) UpdateBlock(ctx context.Context, hash string, updates map[string]interface{}) error {
-	if len(updates) == 0 {
+// UpdateBlock sets the fields of a block that the update carries
+func (r *BlockRepository) UpdateBlock(ctx context.Context, hash domain.Hash, update domain.BlockUpdate) error {
+	if update.IsEmpty() {
 		return nil
 	}
-
-	setParts := []string{}
-	args := []interface{}{}
-	argIndex := 1
-
-	for field, value := range updates {
-		setParts = append(setParts, fmt.Sprintf("%s = $%d", field, argIndex))
-		args = append(args, value)
-		argIndex++
+	if err := update.Validate(); err != nil {
+		return fmt.Errorf("invalid block update: %w", err)
 	}
 
-	// Add indexed_at update (use NOW() function directly in SQL)
-	setParts = append(setParts, "indexed_at = NOW()")
-
-	// Add WHERE clause
-	args = append(args, hash)
+	var set assignments
+	if update.IsChainBlock != nil {
+		set.set("is_chain_block", *update.IsChainBlock)
+	}
+	if update.SelectedParent != nil {
+		set.set("selected_parent_hash", *update.SelectedParent)
+	}
+	if update.BlueScore != nil {
+		set.set("blue_score", int64(*update.BlueScore))
+	}
+	if update.BlueWork != nil {
+		set.set("blue_work", numericFromBig(update.BlueWork))
+	}
+	if update.DAAScore != nil {
+		set.set("daa_score", int64(*update.DAAScore))
+	}
+	if update.PruningPoint != nil {
+		set.set("pruning_point_hash", *update.PruningPoint)
+	}
+	if update.GasUsed != nil {
+		set.set("gas_used", int64(*update.GasUsed))
+	}
 
 	query := fmt.Sprintf(`
 		UPDATE blocks
 		SET %s
 		WHERE hash = $%d
-	`, strings.Join(setParts, ", "), argIndex)
+	`, set.clause("indexed_at = NOW()"), set.next())
 
-	_, err := r.conn.Exec(ctx, query, args...)
+	_, err := r.db.Exec(ctx, query, append(set.args, hash)...)
 	if err != nil {
 		r.logger.Error("failed to update block",
-			zap.String("hash", hash),
+			zap.String("hash", hash.String()),
 			zap.Error(err))
 		return fmt.Errorf("update block: %w", err)
 	}
@@ -0,0 +1,340 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// Staged columns. COPY writes them to a temporary table that is then merged
+// into the real table with the same conflict handling as the per-row repositories.
+var (
+	blockCopyColumns = []string{
+		"hash", "number", "parent_hashes", "timestamp", "miner_address",
+		"gas_limit", "gas_used", "base_fee_per_gas", "blue_score",
+		"is_chain_block", "selected_parent_hash", "transactions_root",
+		"state_root", "receipts_root", "transaction_count",
+		"size", "difficulty", "nonce", "extra_data", "logs_bloom", "mix_hash",
+		"blue_work", "daa_score", "pruning_point_hash",
+	}
+
+	transactionCopyColumns = []string{
+		"hash", "block_hash", "block_number", "transaction_index",
+		"from_address", "to_address", "value", "input_data", "nonce",
+		"gas_limit", "gas_price", "gas_used", "status",
+		"creates_contract", "contract_address", "timestamp",
+		"transaction_type", "chain_id", "max_fee_per_gas", "max_priority_fee_per_gas",
+		"effective_gas_price", "max_fee_per_blob_gas", "blob_versioned_hashes",
+		"v", "r", "s", "cumulative_gas_used", "logs_bloom",
+	}
+
+	accessListCopyColumns = []string{
+		"transaction_hash", "entry_index", "address", "storage_keys",
+	}
+
+	logCopyColumns = []string{
+		"transaction_hash", "log_index", "block_hash", "block_number",
+		"address", "topics", "data", "event_signature", "timestamp",
+	}
+)
+
+// BulkWriter writes blocks, transactions and logs in batches using COPY.
+// It is meant for backfilling, where per-row INSERTs dominate indexing time.
+type BulkWriter struct {
+	db     DBTX
+	logger *zap.Logger
+}
+
+// NewBulkWriter creates a new BulkWriter
+func NewBulkWriter(db DBTX, logger *zap.Logger) *BulkWriter {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &BulkWriter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WriteBatch saves blocks, their transactions and transaction inclusions in a single transaction.
+// Existing rows are updated the same way SaveBlock and SaveTransaction update them.
+func (w *BulkWriter) WriteBatch(ctx context.Context, blocks []*domain.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	blockRows := make([][]any, 0, len(blocks))
+	var txRows, accessListRows [][]any
+	for _, block := range blocks {
+		blockRows = append(blockRows, blockArgs(block))
+		for i := range block.Transactions {
+			tx := &block.Transactions[i]
+			txRows = append(txRows, transactionArgs(tx))
+			accessListRows = append(accessListRows, accessListCopyRows(tx)...)
+		}
+	}
+
+	err := w.inTx(ctx, func(tx pgx.Tx) error {
+		if err := w.stage(ctx, tx, "blocks", blockCopyColumns, blockRows); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO blocks (`+blockColumns+`
+			)
+			SELECT DISTINCT ON (hash) `+blockColumns+`
+			FROM blocks_staging
+			ORDER BY hash
+			ON CONFLICT (hash) DO UPDATE SET`+blockHeaderUpdates+`
+		`); err != nil {
+			return fmt.Errorf("merge blocks: %w", err)
+		}
+
+		if len(txRows) == 0 {
+			return w.unstage(ctx, tx, "blocks")
+		}
+
+		if err := w.stage(ctx, tx, "transactions", transactionCopyColumns, txRows); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO transactions (`+transactionColumns+`,
+				is_accepted
+			)
+			SELECT DISTINCT ON (hash) `+transactionColumns+`,
+				EXISTS (
+					SELECT 1 FROM blocks b
+					WHERE b.hash = transactions_staging.block_hash AND (`+blockAccepted+`)
+				)
+			FROM transactions_staging
+			ORDER BY hash, block_number, block_hash
+			ON CONFLICT (hash) DO UPDATE SET
+				value = EXCLUDED.value,
+				gas_price = EXCLUDED.gas_price,
+				gas_used = COALESCE(EXCLUDED.gas_used, transactions.gas_used),
+				status = COALESCE(EXCLUDED.status, transactions.status),
+				transaction_type = EXCLUDED.transaction_type,
+				chain_id = EXCLUDED.chain_id,
+				max_fee_per_gas = EXCLUDED.max_fee_per_gas,
+				max_priority_fee_per_gas = EXCLUDED.max_priority_fee_per_gas,
+				effective_gas_price = COALESCE(EXCLUDED.effective_gas_price, transactions.effective_gas_price),
+				max_fee_per_blob_gas = EXCLUDED.max_fee_per_blob_gas,
+				blob_versioned_hashes = EXCLUDED.blob_versioned_hashes,
+				v = EXCLUDED.v,
+				r = EXCLUDED.r,
+				s = EXCLUDED.s
+		`); err != nil {
+			return fmt.Errorf("merge transactions: %w", err)
+		}
+
+		if err := w.writeAccessLists(ctx, tx, accessListRows); err != nil {
+			return err
+		}
+
+		// Every staged row is an inclusion, including repeats of a transaction in other blocks
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO transaction_inclusions (transaction_hash, block_hash, block_number, transaction_index)
+			SELECT DISTINCT ON (hash, block_hash) hash, block_hash, block_number, transaction_index
+			FROM transactions_staging
+			ORDER BY hash, block_hash
+			ON CONFLICT (transaction_hash, block_hash) DO UPDATE SET
+				block_number = EXCLUDED.block_number,
+				transaction_index = EXCLUDED.transaction_index
+		`); err != nil {
+			return fmt.Errorf("merge transaction inclusions: %w", err)
+		}
+
+		if err := w.unstage(ctx, tx, "blocks"); err != nil {
+			return err
+		}
+		return w.unstage(ctx, tx, "transactions")
+	})
+	if err != nil {
+		w.logger.Error("failed to write block batch",
+			zap.Int64("from", blocks[0].Number),
+			zap.Int64("to", blocks[len(blocks)-1].Number),
+			zap.Error(err))
+		return fmt.Errorf("write block batch: %w", err)
+	}
+
+	w.logger.Debug("block batch written",
+		zap.Int("blocks", len(blockRows)),
+		zap.Int("transactions", len(txRows)))
+
+	return nil
+}
+
+// WriteLogs saves event logs in a single transaction. Existing logs are left untouched,
+// keeping the block their transaction was first stored with, as SaveLog does.
+func (w *BulkWriter) WriteLogs(ctx context.Context, logs []*domain.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, 0, len(logs))
+	for _, log := range logs {
+		rows = append(rows, logCopyRow(log))
+	}
+
+	err := w.inTx(ctx, func(tx pgx.Tx) error {
+		if err := w.stage(ctx, tx, "event_logs", logCopyColumns, rows); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO event_logs (
+				transaction_hash, log_index, block_hash, block_number,
+				address, topics, data, event_signature, timestamp
+			)
+			SELECT
+				s.transaction_hash, s.log_index, s.block_hash, s.block_number,
+				s.address, s.topics, s.data, s.event_signature,
+				-- Logs without block context take the timestamp of the stored block, as in SaveLog
+				COALESCE(NULLIF(s.timestamp, 0), (SELECT b.timestamp FROM blocks b WHERE b.hash = s.block_hash), 0)
+			FROM event_logs_staging s
+			ON CONFLICT (transaction_hash, log_index) DO NOTHING
+		`); err != nil {
+			return fmt.Errorf("merge logs: %w", err)
+		}
+
+		return w.unstage(ctx, tx, "event_logs")
+	})
+	if err != nil {
+		w.logger.Error("failed to write log batch",
+			zap.Int("logs", len(logs)),
+			zap.Error(err))
+		return fmt.Errorf("write log batch: %w", err)
+	}
+
+	return nil
+}
+
+// writeAccessLists replaces the access lists of the staged transactions
+func (w *BulkWriter) writeAccessLists(ctx context.Context, tx pgx.Tx, rows [][]any) error {
+	if err := w.stage(ctx, tx, "transaction_access_lists", accessListCopyColumns, rows); err != nil {
+		return err
+	}
+
+	// Entries beyond a transaction's new list length belong to an older version of it
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM transaction_access_lists a
+		USING transactions_staging t
+		WHERE a.transaction_hash = t.hash
+		  AND a.entry_index >= COALESCE((
+			SELECT MAX(s.entry_index) + 1
+			FROM transaction_access_lists_staging s
+			WHERE s.transaction_hash = t.hash
+		  ), 0)
+	`); err != nil {
+		return fmt.Errorf("delete stale access lists: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO transaction_access_lists (transaction_hash, entry_index, address, storage_keys)
+		SELECT DISTINCT ON (transaction_hash, entry_index)
+			transaction_hash, entry_index, address, storage_keys
+		FROM transaction_access_lists_staging
+		ORDER BY transaction_hash, entry_index
+		ON CONFLICT (transaction_hash, entry_index) DO UPDATE SET
+			address = EXCLUDED.address,
+			storage_keys = EXCLUDED.storage_keys
+	`); err != nil {
+		return fmt.Errorf("merge access lists: %w", err)
+	}
+
+	return w.unstage(ctx, tx, "transaction_access_lists")
+}
+
+// inTx runs fn in a transaction, or a savepoint if the writer is already inside one
+func (w *BulkWriter) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// stage creates <table>_staging shaped like table and copies rows into it
+func (w *BulkWriter) stage(ctx context.Context, tx pgx.Tx, table string, columns []string, rows [][]any) error {
+	staging := table + "_staging"
+
+	// Column types come from the target table; constraints are checked on merge
+	if _, err := tx.Exec(ctx, fmt.Sprintf(
+		"CREATE TEMP TABLE %s ON COMMIT DROP AS SELECT %s FROM %s WITH NO DATA",
+		staging, strings.Join(columns, ", "), table,
+	)); err != nil {
+		return fmt.Errorf("create %s: %w", staging, err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{staging}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("copy into %s: %w", staging, err)
+	}
+
+	return nil
+}
+
+// unstage drops a staging table so the batch can be repeated within the same transaction
+func (w *BulkWriter) unstage(ctx context.Context, tx pgx.Tx, table string) error {
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DROP TABLE %s_staging", table)); err != nil {
+		return fmt.Errorf("drop %s_staging: %w", table, err)
+	}
+	return nil
+}
+
+// accessListCopyRows encodes a transaction's access list entries in accessListCopyColumns order
+func accessListCopyRows(tx *domain.Transaction) [][]any {
+	rows := make([][]any, 0, len(tx.AccessList))
+	for i, tuple := range tx.AccessList {
+		storageKeys := tuple.StorageKeys
+		if storageKeys == nil {
+			storageKeys = []string{}
+		}
+		rows = append(rows, []any{tx.Hash, int32(i), tuple.Address, storageKeys})
+	}
+	return rows
+}
+
+// logCopyRow encodes a log in logCopyColumns order
+func logCopyRow(log *domain.Log) []any {
+	var eventSignature *string
+	if len(log.Topics) > 0 {
+		eventSignature = &log.Topics[0]
+	}
+
+	topics := log.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+
+	return []any{
+		log.TransactionHash,
+		int32(log.LogIndex),
+		log.BlockHash,
+		log.BlockNumber,
+		log.Address,
+		topics,
+		hexFromBytes(log.Data),
+		eventSignature,
+		log.Timestamp,
+	}
+}
+
+// nullString maps an empty string to NULL
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,253 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// bulkTestBlocks builds count blocks starting at from, each with txPerBlock transactions
+func bulkTestBlocks(from, count int64, txPerBlock int) []*domain.Block {
+	blocks := make([]*domain.Block, 0, count)
+	for n := from; n < from+count; n++ {
+		hash := domain.Hash(fmt.Sprintf("0x%064x", n))
+		block := &domain.Block{
+			Hash:         hash,
+			Number:       n,
+			Timestamp:    time.Now().Unix(),
+			Miner:        domain.Address("0x" + strings.Repeat("c", 40)),
+			GasLimit:     30000000,
+			GasUsed:      21000 * uint64(txPerBlock),
+			BlueScore:    uint64(n),
+			IsChainBlock: true,
+		}
+		if n > 0 {
+			block.ParentHashes = []domain.Hash{domain.Hash(fmt.Sprintf("0x%064x", n-1))}
+		}
+		for i := 0; i < txPerBlock; i++ {
+			block.Transactions = append(block.Transactions, domain.Transaction{
+				Hash:             domain.Hash(fmt.Sprintf("0x%032x%032x", n, i)),
+				BlockHash:        hash,
+				BlockNumber:      n,
+				TransactionIndex: i,
+				From:             domain.Address("0x" + strings.Repeat("d", 40)),
+				Value:            big.NewInt(1000),
+				GasLimit:         21000,
+				Nonce:            uint64(i),
+			})
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func TestBulkWriter_WriteBatch(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := database.NewBulkWriter(conn, zap.NewNop())
+	blocks := bulkTestBlocks(1, 20, 3)
+
+	err := writer.WriteBatch(ctx, blocks)
+	require.NoError(t, err)
+
+	var blockCount, txCount int
+	require.NoError(t, conn.QueryRow(ctx, "SELECT COUNT(*) FROM blocks").Scan(&blockCount))
+	require.NoError(t, conn.QueryRow(ctx, "SELECT COUNT(*) FROM transactions").Scan(&txCount))
+	assert.Equal(t, 20, blockCount)
+	assert.Equal(t, 60, txCount)
+
+	// Rows written in bulk read back like rows written one at a time
+	saved, err := database.NewBlockRepository(conn, zap.NewNop()).GetBlockByHash(ctx, blocks[4].Hash)
+	require.NoError(t, err)
+	assert.Equal(t, blocks[4].Number, saved.Number)
+	assert.Equal(t, blocks[4].ParentHashes, saved.ParentHashes)
+	assert.Equal(t, blocks[4].GasUsed, saved.GasUsed)
+}
+
+func TestBulkWriter_WriteBatch_MergesExistingRows(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := database.NewBulkWriter(conn, zap.NewNop())
+
+	require.NoError(t, writer.WriteBatch(ctx, bulkTestBlocks(1, 5, 1)))
+
+	// Re-writing an overlapping range updates chain membership instead of failing
+	blocks := bulkTestBlocks(3, 5, 1)
+	blocks[0].IsChainBlock = false
+	require.NoError(t, writer.WriteBatch(ctx, blocks))
+
+	var blockCount int
+	require.NoError(t, conn.QueryRow(ctx, "SELECT COUNT(*) FROM blocks").Scan(&blockCount))
+	assert.Equal(t, 7, blockCount)
+
+	saved, err := database.NewBlockRepository(conn, zap.NewNop()).GetBlockByHash(ctx, blocks[0].Hash)
+	require.NoError(t, err)
+	assert.False(t, saved.IsChainBlock)
+}
+
+func TestBulkWriter_WriteBatch_RepeatedTransaction(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := database.NewBulkWriter(conn, zap.NewNop())
+
+	// Block 2 repeats block 1's transaction at another index and is staged first
+	blocks := bulkTestBlocks(1, 2, 1)
+	repeated := blocks[0].Transactions[0]
+	repeated.BlockHash = blocks[1].Hash
+	repeated.BlockNumber = blocks[1].Number
+	repeated.TransactionIndex = 1
+	repeated.Timestamp = blocks[1].Timestamp + 1
+	blocks[1].Transactions = append(blocks[1].Transactions, repeated)
+	blocks[0], blocks[1] = blocks[1], blocks[0]
+	require.NoError(t, writer.WriteBatch(ctx, blocks))
+
+	// The transaction row describes the lowest block, like SaveTransaction's first insert
+	repo := database.NewTransactionRepository(conn, zap.NewNop())
+	saved, err := repo.GetTransactionByHash(ctx, repeated.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, blocks[1].Hash, saved.BlockHash)
+	assert.Equal(t, 0, saved.TransactionIndex)
+
+	inclusions, err := repo.GetInclusionsByTransactionHash(ctx, repeated.Hash)
+	require.NoError(t, err)
+	assert.Len(t, inclusions, 2)
+}
+
+func TestBulkWriter_WriteBatch_RollsBackOnInvalidRow(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := database.NewBulkWriter(conn, zap.NewNop())
+
+	blocks := bulkTestBlocks(1, 5, 1)
+	blocks[3].Transactions[0].From = "invalid"
+
+	err := writer.WriteBatch(ctx, blocks)
+	assert.Error(t, err)
+
+	var blockCount int
+	require.NoError(t, conn.QueryRow(ctx, "SELECT COUNT(*) FROM blocks").Scan(&blockCount))
+	assert.Equal(t, 0, blockCount)
+}
+
+func TestBulkWriter_WriteLogs(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = conn.Exec(ctx, "TRUNCATE TABLE event_logs")
+
+	writer := database.NewBulkWriter(conn, zap.NewNop())
+	blocks := bulkTestBlocks(1, 1, 1)
+	require.NoError(t, writer.WriteBatch(ctx, blocks))
+
+	tx := blocks[0].Transactions[0]
+	logs := []*domain.Log{
+		{
+			TransactionHash: tx.Hash,
+			LogIndex:        0,
+			BlockHash:       tx.BlockHash,
+			BlockNumber:     tx.BlockNumber,
+			Address:         domain.Address("0x" + strings.Repeat("e", 40)),
+			Topics:          []string{"0x" + strings.Repeat("f", 64)},
+			Data:            []byte("0x01"),
+		},
+		{
+			TransactionHash: tx.Hash,
+			LogIndex:        1,
+			BlockHash:       tx.BlockHash,
+			BlockNumber:     tx.BlockNumber,
+			Address:         domain.Address("0x" + strings.Repeat("e", 40)),
+		},
+	}
+
+	require.NoError(t, writer.WriteLogs(ctx, logs))
+	// Duplicates are ignored
+	require.NoError(t, writer.WriteLogs(ctx, logs))
+
+	saved, err := database.NewLogRepository(conn, zap.NewNop()).GetLogsByTransactionHash(ctx, tx.Hash)
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	for _, log := range saved {
+		assert.Equal(t, blocks[0].Timestamp, log.Timestamp, "logs without a timestamp take the block's")
+	}
+}
+
+// The benchmarks compare the bulk path with the per-row repositories on the same data
+const benchmarkTxPerBlock = 20
+
+func BenchmarkBlockRepository_PerRow(b *testing.B) {
+	conn, cleanup := setupBlockRepoTestDB(b)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
+	txRepo := database.NewTransactionRepository(conn, zap.NewNop())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, block := range bulkTestBlocks(int64(i)*100, 100, benchmarkTxPerBlock) {
+			if err := blockRepo.SaveBlock(ctx, block); err != nil {
+				b.Fatal(err)
+			}
+			for j := range block.Transactions {
+				if err := txRepo.SaveTransaction(ctx, &block.Transactions[j]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkBulkWriter_WriteBatch(b *testing.B) {
+	conn, cleanup := setupBlockRepoTestDB(b)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := database.NewBulkWriter(conn, zap.NewNop())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := writer.WriteBatch(ctx, bulkTestBlocks(int64(i)*100, 100, benchmarkTxPerBlock)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// CheckpointRepository implements the CheckpointStore interface
+type CheckpointRepository struct {
+	db     DBTX
+	logger *zap.Logger
+}
+
+// NewCheckpointRepository creates a new CheckpointRepository
+func NewCheckpointRepository(db DBTX, logger *zap.Logger) *CheckpointRepository {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &CheckpointRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetCheckpoint retrieves the checkpoint with the given name.
+// Returns nil without an error if no checkpoint has been saved yet.
+func (r *CheckpointRepository) GetCheckpoint(ctx context.Context, name string) (*domain.Checkpoint, error) {
+	query := `
+		SELECT name, last_block_number, last_block_hash, updated_at
+		FROM indexer_state
+		WHERE name = $1
+	`
+
+	var checkpoint domain.Checkpoint
+	err := r.db.QueryRow(ctx, query, name).Scan(
+		&checkpoint.Name,
+		&checkpoint.BlockNumber,
+		&checkpoint.BlockHash,
+		&checkpoint.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get checkpoint",
+			zap.String("name", name),
+			zap.Error(err))
+		return nil, fmt.Errorf("get checkpoint: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint creates or moves the checkpoint with the given name
+func (r *CheckpointRepository) SaveCheckpoint(ctx context.Context, checkpoint *domain.Checkpoint) error {
+	if err := checkpoint.Validate(); err != nil {
+		return fmt.Errorf("invalid checkpoint: %w", err)
+	}
+
+	query := `
+		INSERT INTO indexer_state (
+			name, last_block_number, last_block_hash
+		) VALUES (
+			$1, $2, $3
+		)
+		ON CONFLICT (name) DO UPDATE SET
+			last_block_number = EXCLUDED.last_block_number,
+			last_block_hash = EXCLUDED.last_block_hash,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		checkpoint.Name,
+		checkpoint.BlockNumber,
+		checkpoint.BlockHash,
+	)
+
+	if err != nil {
+		r.logger.Error("failed to save checkpoint",
+			zap.String("name", checkpoint.Name),
+			zap.Int64("blockNumber", checkpoint.BlockNumber),
+			zap.Error(err))
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+
+	return nil
+}
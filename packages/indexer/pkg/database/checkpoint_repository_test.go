@@ -0,0 +1,87 @@
+package database_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestCheckpointRepository_SaveCheckpoint(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = conn.Exec(ctx, "TRUNCATE TABLE indexer_state")
+
+	repo := database.NewCheckpointRepository(conn, zap.NewNop())
+
+	checkpoint := &domain.Checkpoint{
+		Name:        "test_sync",
+		BlockNumber: 100,
+		BlockHash:   domain.Hash("0x" + strings.Repeat("a", 64)),
+	}
+
+	err := repo.SaveCheckpoint(ctx, checkpoint)
+	require.NoError(t, err)
+
+	saved, err := repo.GetCheckpoint(ctx, "test_sync")
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, checkpoint.BlockNumber, saved.BlockNumber)
+	assert.Equal(t, checkpoint.BlockHash, saved.BlockHash)
+
+	// Moving the checkpoint overwrites the previous position
+	checkpoint.BlockNumber = 101
+	checkpoint.BlockHash = domain.Hash("0x" + strings.Repeat("b", 64))
+	err = repo.SaveCheckpoint(ctx, checkpoint)
+	require.NoError(t, err)
+
+	saved, err = repo.GetCheckpoint(ctx, "test_sync")
+	require.NoError(t, err)
+	assert.Equal(t, int64(101), saved.BlockNumber)
+	assert.Equal(t, checkpoint.BlockHash, saved.BlockHash)
+}
+
+func TestCheckpointRepository_GetCheckpoint_NotFound(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = conn.Exec(ctx, "TRUNCATE TABLE indexer_state")
+
+	repo := database.NewCheckpointRepository(conn, zap.NewNop())
+
+	saved, err := repo.GetCheckpoint(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+}
+
+func TestCheckpointRepository_SaveCheckpoint_Invalid(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := database.NewCheckpointRepository(conn, zap.NewNop())
+
+	err := repo.SaveCheckpoint(ctx, &domain.Checkpoint{
+		Name:        "test_sync",
+		BlockNumber: 100,
+		BlockHash:   "invalid",
+	})
+	assert.Error(t, err)
+}
@@ -12,23 +12,23 @@ import (
 
 // DAGRepository implements DAGReader and DAGWriter interfaces
 type DAGRepository struct {
-	conn   *pgx.Conn
+	db     DBTX
 	logger *zap.Logger
 }
 
 // NewDAGRepository creates a new DAGRepository
-func NewDAGRepository(conn *pgx.Conn, logger *zap.Logger) *DAGRepository {
+func NewDAGRepository(db DBTX, logger *zap.Logger) *DAGRepository {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 	return &DAGRepository{
-		conn:   conn,
+		db:     db,
 		logger: logger,
 	}
 }
 
 // SaveDAGRelationship saves a DAG parent-child relationship
-func (r *DAGRepository) SaveDAGRelationship(ctx context.Context, childHash, parentHash string, isSelectedParent bool) error {
+func (r *DAGRepository) SaveDAGRelationship(ctx context.Context, childHash, parentHash domain.Hash, isSelectedParent bool) error {
 	query := `
 		INSERT INTO dag_relationships (
 			child_hash, parent_hash, is_selected_parent
@@ -39,11 +39,11 @@ func (r *DAGRepository) SaveDAGRelationship(ctx context.Context, childHash, pare
 			is_selected_parent = EXCLUDED.is_selected_parent
 	`
 
-	_, err := r.conn.Exec(ctx, query, childHash, parentHash, isSelectedParent)
+	_, err := r.db.Exec(ctx, query, childHash, parentHash, isSelectedParent)
 	if err != nil {
 		r.logger.Error("failed to save DAG relationship",
-			zap.String("childHash", childHash),
-			zap.String("parentHash", parentHash),
+			zap.String("childHash", childHash.String()),
+			zap.String("parentHash", parentHash.String()),
 			zap.Error(err))
 		return fmt.Errorf("save DAG relationship: %w", err)
 	}
@@ -52,7 +52,7 @@ func (r *DAGRepository) SaveDAGRelationship(ctx context.Context, childHash, pare
 }
 
 // GetBlockParents retrieves all parent hashes for a block
-func (r *DAGRepository) GetBlockParents(ctx context.Context, blockHash string) ([]string, error) {
+func (r *DAGRepository) GetBlockParents(ctx context.Context, blockHash domain.Hash) ([]domain.Hash, error) {
 	query := `
 		SELECT parent_hash
 		FROM dag_relationships
@@ -60,18 +60,18 @@ func (r *DAGRepository) GetBlockParents(ctx context.Context, blockHash string) (
 		ORDER BY is_selected_parent DESC, parent_hash ASC
 	`
 
-	rows, err := r.conn.Query(ctx, query, blockHash)
+	rows, err := r.db.Query(ctx, query, blockHash)
 	if err != nil {
 		r.logger.Error("failed to get block parents",
-			zap.String("blockHash", blockHash),
+			zap.String("blockHash", blockHash.String()),
 			zap.Error(err))
 		return nil, fmt.Errorf("get block parents: %w", err)
 	}
 	defer rows.Close()
 
-	var parents []string
+	var parents []domain.Hash
 	for rows.Next() {
-		var parentHash string
+		var parentHash domain.Hash
 		if err := rows.Scan(&parentHash); err != nil {
 			return nil, fmt.Errorf("scan parent hash: %w", err)
 		}
@@ -86,7 +86,7 @@ func (r *DAGRepository) GetBlockParents(ctx context.Context, blockHash string) (
 }
 
 // GetBlockChildren retrieves all child hashes for a block
-func (r *DAGRepository) GetBlockChildren(ctx context.Context, blockHash string) ([]string, error) {
+func (r *DAGRepository) GetBlockChildren(ctx context.Context, blockHash domain.Hash) ([]domain.Hash, error) {
 	query := `
 		SELECT child_hash
 		FROM dag_relationships
@@ -94,18 +94,18 @@ func (r *DAGRepository) GetBlockChildren(ctx context.Context, blockHash string)
 		ORDER BY child_hash ASC
 	`
 
-	rows, err := r.conn.Query(ctx, query, blockHash)
+	rows, err := r.db.Query(ctx, query, blockHash)
 	if err != nil {
 		r.logger.Error("failed to get block children",
-			zap.String("blockHash", blockHash),
+			zap.String("blockHash", blockHash.String()),
 			zap.Error(err))
 		return nil, fmt.Errorf("get block children: %w", err)
 	}
 	defer rows.Close()
 
-	var children []string
+	var children []domain.Hash
 	for rows.Next() {
-		var childHash string
+		var childHash domain.Hash
 		if err := rows.Scan(&childHash); err != nil {
 			return nil, fmt.Errorf("scan child hash: %w", err)
 		}
@@ -120,41 +120,43 @@ func (r *DAGRepository) GetBlockChildren(ctx context.Context, blockHash string)
 }
 
 // SaveGHOSTDAGData saves GHOSTDAG data for a block
-func (r *DAGRepository) SaveGHOSTDAGData(ctx context.Context, blockHash string, data *domain.GHOSTDAGData) error {
+func (r *DAGRepository) SaveGHOSTDAGData(ctx context.Context, blockHash domain.Hash, data *domain.GHOSTDAGData) error {
 	query := `
 		INSERT INTO ghostdag_data (
 			block_hash, blue_score, blue_work, selected_parent,
-			merge_set_blues, merge_set_reds
+			merge_set_blues, merge_set_reds, blues_anticone_sizes
 		) VALUES (
-			$1, $2, $3, $4, $5, $6
+			$1, $2, $3, $4, $5, $6, $7
 		)
 		ON CONFLICT (block_hash) DO UPDATE SET
 			blue_score = EXCLUDED.blue_score,
 			blue_work = EXCLUDED.blue_work,
 			selected_parent = EXCLUDED.selected_parent,
 			merge_set_blues = EXCLUDED.merge_set_blues,
-			merge_set_reds = EXCLUDED.merge_set_reds
+			merge_set_reds = EXCLUDED.merge_set_reds,
+			blues_anticone_sizes = EXCLUDED.blues_anticone_sizes
 	`
 
-	var selectedParent *string
+	var selectedParent *domain.Hash
 	if data.SelectedParent != "" {
 		selectedParent = &data.SelectedParent
 	}
 
 	blueWorkStr := data.BlueWork.String()
 
-	_, err := r.conn.Exec(ctx, query,
+	_, err := r.db.Exec(ctx, query,
 		blockHash,
 		data.BlueScore,
 		blueWorkStr,
 		selectedParent,
 		data.MergeSetBlues,
 		data.MergeSetReds,
+		data.BluesAnticoneSizes,
 	)
 
 	if err != nil {
 		r.logger.Error("failed to save GHOSTDAG data",
-			zap.String("blockHash", blockHash),
+			zap.String("blockHash", blockHash.String()),
 			zap.Error(err))
 		return fmt.Errorf("save GHOSTDAG data: %w", err)
 	}
@@ -163,33 +165,34 @@ func (r *DAGRepository) SaveGHOSTDAGData(ctx context.Context, blockHash string,
 }
 
 // GetGHOSTDAGData retrieves GHOSTDAG data for a block
-func (r *DAGRepository) GetGHOSTDAGData(ctx context.Context, blockHash string) (*domain.GHOSTDAGData, error) {
+func (r *DAGRepository) GetGHOSTDAGData(ctx context.Context, blockHash domain.Hash) (*domain.GHOSTDAGData, error) {
 	query := `
 		SELECT block_hash, blue_score, blue_work, selected_parent,
-		       merge_set_blues, merge_set_reds
+		       merge_set_blues, merge_set_reds, blues_anticone_sizes
 		FROM ghostdag_data
 		WHERE block_hash = $1
 	`
 
 	var data domain.GHOSTDAGData
-	var selectedParent *string
+	var selectedParent *domain.Hash
 	var blueWorkStr string
 
-	err := r.conn.QueryRow(ctx, query, blockHash).Scan(
+	err := r.db.QueryRow(ctx, query, blockHash).Scan(
 		&data.BlockHash,
 		&data.BlueScore,
 		&blueWorkStr,
 		&selectedParent,
 		&data.MergeSetBlues,
 		&data.MergeSetReds,
+		&data.BluesAnticoneSizes,
 	)
 
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("GHOSTDAG data not found: %s", blockHash)
+		return nil, fmt.Errorf("GHOSTDAG data %w: %s", domain.ErrNotFound, blockHash)
 	}
 	if err != nil {
 		r.logger.Error("failed to get GHOSTDAG data",
-			zap.String("blockHash", blockHash),
+			zap.String("blockHash", blockHash.String()),
 			zap.Error(err))
 		return nil, fmt.Errorf("get GHOSTDAG data: %w", err)
 	}
@@ -208,3 +211,230 @@ func (r *DAGRepository) GetGHOSTDAGData(ctx context.Context, blockHash string) (
 	return &data, nil
 }
 
+// SaveMergeSet replaces the stored mergeset of a block in a single statement
+func (r *DAGRepository) SaveMergeSet(ctx context.Context, blockHash domain.Hash, mergeSet []domain.MergeSetMember) error {
+	hashes := make([]domain.Hash, 0, len(mergeSet))
+	blues := make([]bool, 0, len(mergeSet))
+	positions := make([]int32, 0, len(mergeSet))
+	for _, member := range mergeSet {
+		if err := member.Validate(); err != nil {
+			return fmt.Errorf("invalid mergeset member %s: %w", member.BlockHash, err)
+		}
+		hashes = append(hashes, member.BlockHash)
+		blues = append(blues, member.IsBlue)
+		positions = append(positions, int32(member.Position))
+	}
+
+	query := `
+		WITH stale AS (
+			DELETE FROM mergesets
+			WHERE merging_block_hash = $1
+			  AND NOT (merged_block_hash = ANY($2::TEXT[]))
+		)
+		INSERT INTO mergesets (merging_block_hash, merged_block_hash, is_blue, merge_order)
+		SELECT DISTINCT ON (m.hash) $1, m.hash, m.is_blue, m.merge_order
+		FROM unnest($2::TEXT[], $3::BOOLEAN[], $4::INTEGER[]) AS m(hash, is_blue, merge_order)
+		ORDER BY m.hash, m.merge_order
+		ON CONFLICT (merging_block_hash, merged_block_hash) DO UPDATE SET
+			is_blue = EXCLUDED.is_blue,
+			merge_order = EXCLUDED.merge_order
+	`
+
+	_, err := r.db.Exec(ctx, query, blockHash, hashes, blues, positions)
+	if err != nil {
+		r.logger.Error("failed to save mergeset",
+			zap.String("blockHash", blockHash.String()),
+			zap.Int("size", len(mergeSet)),
+			zap.Error(err))
+		return fmt.Errorf("save mergeset: %w", err)
+	}
+
+	return nil
+}
+
+// GetMergedBlocks retrieves the blocks merged by a block, in mergeset order
+func (r *DAGRepository) GetMergedBlocks(ctx context.Context, blockHash domain.Hash) ([]*domain.MergeSetMember, error) {
+	query := `
+		SELECT merging_block_hash, merged_block_hash, is_blue, merge_order
+		FROM mergesets
+		WHERE merging_block_hash = $1
+		ORDER BY merge_order ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, blockHash)
+	if err != nil {
+		r.logger.Error("failed to get merged blocks",
+			zap.String("blockHash", blockHash.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get merged blocks: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*domain.MergeSetMember
+	for rows.Next() {
+		var member domain.MergeSetMember
+		if err := rows.Scan(&member.MergingBlockHash, &member.BlockHash, &member.IsBlue, &member.Position); err != nil {
+			return nil, fmt.Errorf("scan mergeset member: %w", err)
+		}
+		members = append(members, &member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return members, nil
+}
+
+// GetMergingChainBlock finds the chain block that merged a block. If several chain
+// blocks list it (possible while a reorg is being applied), the earliest one wins.
+// Returns domain.ErrNotFound if no chain block has merged the block yet.
+func (r *DAGRepository) GetMergingChainBlock(ctx context.Context, blockHash domain.Hash) (*domain.MergeSetMember, error) {
+	query := `
+		SELECT m.merging_block_hash, m.merged_block_hash, m.is_blue, m.merge_order
+		FROM mergesets m
+		JOIN blocks c ON c.hash = m.merging_block_hash
+		WHERE m.merged_block_hash = $1
+		  AND c.is_chain_block = true
+		ORDER BY c.blue_score ASC, c.hash ASC
+		LIMIT 1
+	`
+
+	var member domain.MergeSetMember
+	err := r.db.QueryRow(ctx, query, blockHash).Scan(
+		&member.MergingBlockHash,
+		&member.BlockHash,
+		&member.IsBlue,
+		&member.Position,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("merging chain block %w: %s", domain.ErrNotFound, blockHash)
+	}
+	if err != nil {
+		r.logger.Error("failed to get merging chain block",
+			zap.String("blockHash", blockHash.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get merging chain block: %w", err)
+	}
+
+	return &member, nil
+}
+
+// GetDAGWindow retrieves the blocks numbered from..to with their parents, colour and
+// stored GHOSTDAG data, ordered by number, then hash
+func (r *DAGRepository) GetDAGWindow(ctx context.Context, fromNumber, toNumber int64) ([]*domain.DAGBlock, error) {
+	blocks, err := r.getDAGWindow(ctx, "b.number", fromNumber, toNumber)
+	if err != nil {
+		r.logger.Error("failed to get DAG window",
+			zap.Int64("from", fromNumber),
+			zap.Int64("to", toNumber),
+			zap.Error(err))
+		return nil, fmt.Errorf("get DAG window: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// GetDAGWindowByBlueScore retrieves the blocks with blue scores from..to with their parents,
+// colour and stored GHOSTDAG data, ordered by number, then hash
+func (r *DAGRepository) GetDAGWindowByBlueScore(ctx context.Context, fromBlueScore, toBlueScore uint64) ([]*domain.DAGBlock, error) {
+	blocks, err := r.getDAGWindow(ctx, "b.blue_score", int64(fromBlueScore), int64(toBlueScore))
+	if err != nil {
+		r.logger.Error("failed to get DAG window by blue score",
+			zap.Uint64("from", fromBlueScore),
+			zap.Uint64("to", toBlueScore),
+			zap.Error(err))
+		return nil, fmt.Errorf("get DAG window by blue score: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// getDAGWindow loads the blocks whose column lies in from..to. A block's colour comes from
+// the earliest chain block that merged it, as in GetMergingChainBlock.
+func (r *DAGRepository) getDAGWindow(ctx context.Context, column string, from, to int64) ([]*domain.DAGBlock, error) {
+	query := `
+		SELECT b.hash, b.number, b.blue_score, COALESCE(b.is_chain_block, false),
+		       CASE WHEN b.is_chain_block THEN true ELSE (
+		           SELECT m.is_blue FROM mergesets m
+		           JOIN blocks c ON c.hash = m.merging_block_hash
+		           WHERE m.merged_block_hash = b.hash AND c.is_chain_block = true
+		           ORDER BY c.blue_score ASC, c.hash ASC
+		           LIMIT 1
+		       ) END,
+		       ARRAY(
+		           SELECT r.parent_hash FROM dag_relationships r
+		           WHERE r.child_hash = b.hash
+		           ORDER BY r.is_selected_parent DESC, r.parent_hash ASC
+		       ),
+		       COALESCE((
+		           SELECT r.parent_hash FROM dag_relationships r
+		           WHERE r.child_hash = b.hash AND r.is_selected_parent = true
+		           ORDER BY r.parent_hash ASC
+		           LIMIT 1
+		       ), ''),
+		       g.blue_score, g.blue_work::TEXT, g.selected_parent,
+		       g.merge_set_blues, g.merge_set_reds, g.blues_anticone_sizes
+		FROM blocks b
+		LEFT JOIN ghostdag_data g ON g.block_hash = b.hash
+		WHERE ` + column + ` BETWEEN $1 AND $2
+		ORDER BY b.number ASC, b.hash ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []*domain.DAGBlock
+	for rows.Next() {
+		var (
+			block          domain.DAGBlock
+			blueScore      *uint64
+			blueWorkStr    *string
+			selectedParent *domain.Hash
+			data           domain.GHOSTDAGData
+		)
+		if err := rows.Scan(
+			&block.Hash,
+			&block.Number,
+			&block.BlueScore,
+			&block.IsChainBlock,
+			&block.IsBlue,
+			&block.Parents,
+			&block.SelectedParent,
+			&blueScore,
+			&blueWorkStr,
+			&selectedParent,
+			&data.MergeSetBlues,
+			&data.MergeSetReds,
+			&data.BluesAnticoneSizes,
+		); err != nil {
+			return nil, fmt.Errorf("scan DAG block: %w", err)
+		}
+
+		if blueScore != nil {
+			blueWork, ok := new(big.Int).SetString(*blueWorkStr, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid blue work format: %s", *blueWorkStr)
+			}
+			data.BlockHash = block.Hash
+			data.BlueScore = *blueScore
+			data.BlueWork = blueWork
+			if selectedParent != nil {
+				data.SelectedParent = *selectedParent
+			}
+			block.GHOSTDAG = &data
+		}
+
+		blocks = append(blocks, &block)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return blocks, nil
+}
@@ -26,9 +26,9 @@ func TestDAGRepository_SaveDAGRelationship(t *testing.T) {
 	// Setup blocks
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	parentBlock := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -40,9 +40,9 @@ func TestDAGRepository_SaveDAGRelationship(t *testing.T) {
 	require.NoError(t, err)
 
 	childBlock := &domain.Block{
-		Hash:         "0x" + strings.Repeat("b", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("b", 64)),
 		Number:      101,
-		ParentHashes: []string{parentBlock.Hash},
+		ParentHashes: []domain.Hash{parentBlock.Hash},
 		Timestamp:   time.Now().Unix() + 1,
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -76,9 +76,9 @@ func TestDAGRepository_SaveDAGRelationship_MultipleParents(t *testing.T) {
 	// Setup blocks
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	parent1 := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -89,9 +89,9 @@ func TestDAGRepository_SaveDAGRelationship_MultipleParents(t *testing.T) {
 	blockRepo.SaveBlock(ctx, parent1)
 
 	parent2 := &domain.Block{
-		Hash:         "0x" + strings.Repeat("c", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("c", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -102,9 +102,9 @@ func TestDAGRepository_SaveDAGRelationship_MultipleParents(t *testing.T) {
 	blockRepo.SaveBlock(ctx, parent2)
 
 	childBlock := &domain.Block{
-		Hash:         "0x" + strings.Repeat("b", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("b", 64)),
 		Number:      101,
-		ParentHashes: []string{parent1.Hash, parent2.Hash},
+		ParentHashes: []domain.Hash{parent1.Hash, parent2.Hash},
 		Timestamp:   time.Now().Unix() + 1,
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -142,9 +142,9 @@ func TestDAGRepository_GetBlockChildren(t *testing.T) {
 	// Setup blocks
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	parentBlock := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -155,9 +155,9 @@ func TestDAGRepository_GetBlockChildren(t *testing.T) {
 	blockRepo.SaveBlock(ctx, parentBlock)
 
 	child1 := &domain.Block{
-		Hash:         "0x" + strings.Repeat("b", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("b", 64)),
 		Number:      101,
-		ParentHashes: []string{parentBlock.Hash},
+		ParentHashes: []domain.Hash{parentBlock.Hash},
 		Timestamp:   time.Now().Unix() + 1,
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -168,9 +168,9 @@ func TestDAGRepository_GetBlockChildren(t *testing.T) {
 	blockRepo.SaveBlock(ctx, child1)
 
 	child2 := &domain.Block{
-		Hash:         "0x" + strings.Repeat("c", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("c", 64)),
 		Number:      101,
-		ParentHashes: []string{parentBlock.Hash},
+		ParentHashes: []domain.Hash{parentBlock.Hash},
 		Timestamp:   time.Now().Unix() + 1,
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -205,9 +205,9 @@ func TestDAGRepository_SaveGHOSTDAGData(t *testing.T) {
 	// Setup block
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -221,12 +221,13 @@ func TestDAGRepository_SaveGHOSTDAGData(t *testing.T) {
 	// Save GHOSTDAG data
 	repo := database.NewDAGRepository(conn, zap.NewNop())
 	ghostDAGData := &domain.GHOSTDAGData{
-		BlockHash:      block.Hash,
-		BlueScore:      1000,
-		BlueWork:       big.NewInt(5000),
-		SelectedParent: "0x" + strings.Repeat("b", 64),
-		MergeSetBlues:  []string{"0x" + strings.Repeat("c", 64)},
-		MergeSetReds:   []string{"0x" + strings.Repeat("d", 64)},
+		BlockHash:          block.Hash,
+		BlueScore:          1000,
+		BlueWork:           big.NewInt(5000),
+		SelectedParent:     domain.Hash("0x" + strings.Repeat("b", 64)),
+		MergeSetBlues:      []domain.Hash{domain.Hash("0x" + strings.Repeat("c", 64))},
+		MergeSetReds:       []domain.Hash{domain.Hash("0x" + strings.Repeat("d", 64))},
+		BluesAnticoneSizes: []int{3},
 	}
 
 	err = repo.SaveGHOSTDAGData(ctx, block.Hash, ghostDAGData)
@@ -238,6 +239,7 @@ func TestDAGRepository_SaveGHOSTDAGData(t *testing.T) {
 	assert.Equal(t, ghostDAGData.BlueScore, saved.BlueScore)
 	assert.Equal(t, ghostDAGData.BlueWork.String(), saved.BlueWork.String())
 	assert.Equal(t, ghostDAGData.SelectedParent, saved.SelectedParent)
+	assert.Equal(t, ghostDAGData.BluesAnticoneSizes, saved.BluesAnticoneSizes)
 }
 
 func TestDAGRepository_GetGHOSTDAGData_NotFound(t *testing.T) {
@@ -250,7 +252,135 @@ func TestDAGRepository_GetGHOSTDAGData_NotFound(t *testing.T) {
 	ctx := context.Background()
 
 	repo := database.NewDAGRepository(conn, zap.NewNop())
-	_, err := repo.GetGHOSTDAGData(ctx, "0x"+strings.Repeat("z", 64))
+	_, err := repo.GetGHOSTDAGData(ctx, domain.Hash("0x"+strings.Repeat("z", 64)))
 	assert.Error(t, err)
 }
 
+func TestDAGRepository_MergeSets(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Chain block C merges A as blue and R as red; non-chain block X also merges A
+	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
+	blockA := domain.Hash("0x" + strings.Repeat("a", 64))
+	blockR := domain.Hash("0x" + strings.Repeat("b", 64))
+	chainBlock := domain.Hash("0x" + strings.Repeat("c", 64))
+	sideBlock := domain.Hash("0x" + strings.Repeat("e", 64))
+	for _, block := range []*domain.Block{
+		{Hash: chainBlock, Number: 101, Timestamp: time.Now().Unix(), BlueScore: 11, IsChainBlock: true},
+		{Hash: sideBlock, Number: 101, Timestamp: time.Now().Unix(), BlueScore: 11, IsChainBlock: false},
+	} {
+		require.NoError(t, blockRepo.SaveBlock(ctx, block))
+	}
+
+	repo := database.NewDAGRepository(conn, zap.NewNop())
+	chainData := &domain.GHOSTDAGData{BlockHash: chainBlock, MergeSetBlues: []domain.Hash{blockA}, MergeSetReds: []domain.Hash{blockR}}
+	require.NoError(t, repo.SaveMergeSet(ctx, chainBlock, chainData.MergeSet()))
+	sideData := &domain.GHOSTDAGData{BlockHash: sideBlock, MergeSetBlues: []domain.Hash{blockA}}
+	require.NoError(t, repo.SaveMergeSet(ctx, sideBlock, sideData.MergeSet()))
+
+	merged, err := repo.GetMergedBlocks(ctx, chainBlock)
+	require.NoError(t, err)
+	require.Len(t, merged, 2)
+	assert.Equal(t, domain.MergeSetMember{MergingBlockHash: chainBlock, BlockHash: blockA, IsBlue: true, Position: 0}, *merged[0])
+	assert.Equal(t, domain.MergeSetMember{MergingBlockHash: chainBlock, BlockHash: blockR, IsBlue: false, Position: 1}, *merged[1])
+
+	// Only chain blocks count as the merging chain block
+	merging, err := repo.GetMergingChainBlock(ctx, blockA)
+	require.NoError(t, err)
+	assert.Equal(t, chainBlock, merging.MergingBlockHash)
+	assert.True(t, merging.IsBlue)
+
+	merging, err = repo.GetMergingChainBlock(ctx, blockR)
+	require.NoError(t, err)
+	assert.False(t, merging.IsBlue)
+
+	_, err = repo.GetMergingChainBlock(ctx, sideBlock)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Saving a new mergeset replaces the old one
+	chainData.MergeSetReds = nil
+	require.NoError(t, repo.SaveMergeSet(ctx, chainBlock, chainData.MergeSet()))
+
+	merged, err = repo.GetMergedBlocks(ctx, chainBlock)
+	require.NoError(t, err)
+	require.Len(t, merged, 1)
+	assert.Equal(t, blockA, merged[0].BlockHash)
+
+	_, err = repo.GetMergingChainBlock(ctx, blockR)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestDAGRepository_GetDAGWindow(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Chain block C merges A (selected, blue) and B (red); only A has GHOSTDAG data
+	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
+	blockA := domain.Hash("0x" + strings.Repeat("a", 64))
+	blockB := domain.Hash("0x" + strings.Repeat("b", 64))
+	blockC := domain.Hash("0x" + strings.Repeat("c", 64))
+	for _, block := range []*domain.Block{
+		{Hash: blockA, Number: 10, Timestamp: time.Now().Unix(), BlueScore: 5, IsChainBlock: true},
+		{Hash: blockB, Number: 10, Timestamp: time.Now().Unix(), BlueScore: 5, IsChainBlock: false},
+		{Hash: blockC, Number: 11, ParentHashes: []domain.Hash{blockB, blockA}, Timestamp: time.Now().Unix(), BlueScore: 7, IsChainBlock: true},
+	} {
+		require.NoError(t, blockRepo.SaveBlock(ctx, block))
+	}
+
+	repo := database.NewDAGRepository(conn, zap.NewNop())
+	require.NoError(t, repo.SaveDAGRelationship(ctx, blockC, blockB, false))
+	require.NoError(t, repo.SaveDAGRelationship(ctx, blockC, blockA, true))
+	require.NoError(t, repo.SaveGHOSTDAGData(ctx, blockA, &domain.GHOSTDAGData{
+		BlockHash:          blockA,
+		BlueScore:          5,
+		BlueWork:           big.NewInt(500),
+		MergeSetBlues:      []domain.Hash{},
+		MergeSetReds:       []domain.Hash{},
+		BluesAnticoneSizes: []int{},
+	}))
+	chainData := &domain.GHOSTDAGData{BlockHash: blockC, MergeSetBlues: []domain.Hash{blockA}, MergeSetReds: []domain.Hash{blockB}}
+	require.NoError(t, repo.SaveMergeSet(ctx, blockC, chainData.MergeSet()))
+
+	blocks, err := repo.GetDAGWindow(ctx, 10, 11)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+
+	assert.Equal(t, blockA, blocks[0].Hash)
+	require.NotNil(t, blocks[0].GHOSTDAG)
+	assert.Equal(t, uint64(5), blocks[0].GHOSTDAG.BlueScore)
+	require.NotNil(t, blocks[0].IsBlue)
+	assert.True(t, *blocks[0].IsBlue)
+
+	assert.Nil(t, blocks[1].GHOSTDAG)
+	assert.False(t, blocks[1].IsChainBlock)
+	require.NotNil(t, blocks[1].IsBlue)
+	assert.False(t, *blocks[1].IsBlue)
+
+	// The selected parent comes first
+	assert.Equal(t, blockC, blocks[2].Hash)
+	assert.Equal(t, int64(11), blocks[2].Number)
+	assert.Equal(t, uint64(7), blocks[2].BlueScore)
+	assert.Equal(t, []domain.Hash{blockA, blockB}, blocks[2].Parents)
+	assert.Equal(t, blockA, blocks[2].SelectedParent)
+	assert.Empty(t, blocks[0].SelectedParent)
+
+	blocks, err = repo.GetDAGWindow(ctx, 12, 20)
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+
+	blocks, err = repo.GetDAGWindowByBlueScore(ctx, 6, 7)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, blockC, blocks[0].Hash)
+}
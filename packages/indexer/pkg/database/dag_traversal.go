@@ -0,0 +1,261 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// maxSelectedParentWalk bounds selected-parent walks that look for a chain block
+const maxSelectedParentWalk = 10000
+
+// GetPast retrieves the blocks reachable through parent links within depth edges of a block,
+// nearest first. A block reached along several paths is reported at its shortest distance.
+func (r *DAGRepository) GetPast(ctx context.Context, hash domain.Hash, depth int, page domain.DAGPage) ([]*domain.DAGNode, error) {
+	query := `
+		WITH RECURSIVE past(hash, distance) AS (
+			SELECT parent_hash, 1
+			FROM dag_relationships
+			WHERE child_hash = $1 AND $2 > 0
+			UNION
+			SELECT r.parent_hash, p.distance + 1
+			FROM past p
+			JOIN dag_relationships r ON r.child_hash = p.hash
+			WHERE p.distance < $2
+		)
+		SELECT n.hash, b.blue_score, COALESCE(b.is_chain_block, false), n.distance
+		FROM (SELECT hash, MIN(distance) AS distance FROM past GROUP BY hash) n
+		JOIN blocks b ON b.hash = n.hash
+		WHERE (n.distance, n.hash) > ($3, $4)
+		ORDER BY n.distance, n.hash
+		LIMIT $5
+	`
+
+	nodes, err := r.traverse(ctx, query, hash, depth, page)
+	if err != nil {
+		r.logger.Error("failed to get past",
+			zap.String("hash", hash.String()),
+			zap.Int("depth", depth),
+			zap.Error(err))
+		return nil, fmt.Errorf("get past: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// GetFuture retrieves the blocks reachable through child links within depth edges of a block,
+// nearest first. A block reached along several paths is reported at its shortest distance.
+func (r *DAGRepository) GetFuture(ctx context.Context, hash domain.Hash, depth int, page domain.DAGPage) ([]*domain.DAGNode, error) {
+	query := `
+		WITH RECURSIVE future(hash, distance) AS (
+			SELECT child_hash, 1
+			FROM dag_relationships
+			WHERE parent_hash = $1 AND $2 > 0
+			UNION
+			SELECT r.child_hash, f.distance + 1
+			FROM future f
+			JOIN dag_relationships r ON r.parent_hash = f.hash
+			WHERE f.distance < $2
+		)
+		SELECT n.hash, b.blue_score, COALESCE(b.is_chain_block, false), n.distance
+		FROM (SELECT hash, MIN(distance) AS distance FROM future GROUP BY hash) n
+		JOIN blocks b ON b.hash = n.hash
+		WHERE (n.distance, n.hash) > ($3, $4)
+		ORDER BY n.distance, n.hash
+		LIMIT $5
+	`
+
+	nodes, err := r.traverse(ctx, query, hash, depth, page)
+	if err != nil {
+		r.logger.Error("failed to get future",
+			zap.String("hash", hash.String()),
+			zap.Int("depth", depth),
+			zap.Error(err))
+		return nil, fmt.Errorf("get future: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// GetAnticone retrieves the blocks within window blue score of a block that are neither
+// in its past nor in its future, nearest blue score first. The past and future are only
+// walked inside the window, which is exact as long as blue scores grow along every edge.
+func (r *DAGRepository) GetAnticone(ctx context.Context, hash domain.Hash, window uint64, page domain.DAGPage) ([]*domain.DAGNode, error) {
+	query := `
+		WITH RECURSIVE origin AS (
+			SELECT hash, blue_score FROM blocks WHERE hash = $1
+		),
+		past(hash) AS (
+			SELECT hash FROM origin
+			UNION
+			SELECT r.parent_hash
+			FROM past p
+			JOIN dag_relationships r ON r.child_hash = p.hash
+			JOIN blocks b ON b.hash = r.parent_hash
+			WHERE b.blue_score >= (SELECT blue_score FROM origin) - $2
+		),
+		future(hash) AS (
+			SELECT hash FROM origin
+			UNION
+			SELECT r.child_hash
+			FROM future f
+			JOIN dag_relationships r ON r.parent_hash = f.hash
+			JOIN blocks b ON b.hash = r.child_hash
+			WHERE b.blue_score <= (SELECT blue_score FROM origin) + $2
+		),
+		anticone AS (
+			SELECT b.hash, b.blue_score, COALESCE(b.is_chain_block, false) AS is_chain_block,
+				ABS(b.blue_score - o.blue_score)::INTEGER AS distance
+			FROM blocks b, origin o
+			WHERE b.blue_score BETWEEN o.blue_score - $2 AND o.blue_score + $2
+			  AND b.hash NOT IN (SELECT hash FROM past)
+			  AND b.hash NOT IN (SELECT hash FROM future)
+		)
+		SELECT hash, blue_score, is_chain_block, distance
+		FROM anticone
+		WHERE (distance, hash) > ($3, $4)
+		ORDER BY distance, hash
+		LIMIT $5
+	`
+
+	nodes, err := r.traverse(ctx, query, hash, int64(window), page)
+	if err != nil {
+		r.logger.Error("failed to get anticone",
+			zap.String("hash", hash.String()),
+			zap.Uint64("window", window),
+			zap.Error(err))
+		return nil, fmt.Errorf("get anticone: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// traverse runs a traversal query taking the start hash, its bound, the page cursor and the page size
+func (r *DAGRepository) traverse(ctx context.Context, query string, hash domain.Hash, bound any, page domain.DAGPage) ([]*domain.DAGNode, error) {
+	if err := page.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid page: %w", err)
+	}
+
+	afterDistance, afterHash := page.Cursor()
+	rows, err := r.db.Query(ctx, query, hash, bound, afterDistance, afterHash, page.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	return collectDAGNodes(rows)
+}
+
+// GetLowestCommonChainAncestor finds the highest chain block in the selected-parent
+// chains of both blocks. Each block's selected parents are followed until they reach
+// the chain; the lower of the two chain blocks reached is an ancestor of the other.
+// Returns domain.ErrNotFound if either walk ends before reaching a chain block.
+func (r *DAGRepository) GetLowestCommonChainAncestor(ctx context.Context, a, b domain.Hash) (*domain.DAGNode, error) {
+	query := `
+		WITH RECURSIVE walk(start, hash, blue_score, is_chain_block, steps) AS (
+			SELECT hash, hash, blue_score, COALESCE(is_chain_block, false), 0
+			FROM blocks
+			WHERE hash IN ($1, $2)
+			UNION ALL
+			SELECT w.start, p.hash, p.blue_score, COALESCE(p.is_chain_block, false), w.steps + 1
+			FROM walk w
+			JOIN dag_relationships r ON r.child_hash = w.hash AND r.is_selected_parent = true
+			JOIN blocks p ON p.hash = r.parent_hash
+			WHERE NOT w.is_chain_block AND w.steps < $3
+		),
+		reached AS (
+			SELECT start, hash, blue_score FROM walk WHERE is_chain_block
+		)
+		SELECT hash, blue_score, (SELECT COUNT(DISTINCT start) FROM reached)
+		FROM reached
+		ORDER BY blue_score ASC, hash ASC
+		LIMIT 1
+	`
+
+	node := domain.DAGNode{IsChainBlock: true}
+	var starts int
+	err := r.db.QueryRow(ctx, query, a, b, maxSelectedParentWalk).Scan(&node.Hash, &node.BlueScore, &starts)
+
+	wanted := 2
+	if a == b {
+		wanted = 1
+	}
+	if err == pgx.ErrNoRows || (err == nil && starts < wanted) {
+		return nil, fmt.Errorf("common chain ancestor %w: %s, %s", domain.ErrNotFound, a, b)
+	}
+	if err != nil {
+		r.logger.Error("failed to get lowest common chain ancestor",
+			zap.String("a", a.String()),
+			zap.String("b", b.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get lowest common chain ancestor: %w", err)
+	}
+
+	return &node, nil
+}
+
+// GetSelectedChainPath retrieves the blocks on to's selected-parent chain from `from` up
+// to and including `to`, in that order. Distances count selected-parent edges from `from`.
+// Returns domain.ErrNotFound if `from` is not on to's selected-parent chain.
+func (r *DAGRepository) GetSelectedChainPath(ctx context.Context, from, to domain.Hash) ([]*domain.DAGNode, error) {
+	query := `
+		WITH RECURSIVE walk(hash, blue_score, is_chain_block, steps) AS (
+			SELECT hash, blue_score, COALESCE(is_chain_block, false), 0
+			FROM blocks
+			WHERE hash = $2
+			UNION ALL
+			SELECT p.hash, p.blue_score, COALESCE(p.is_chain_block, false), w.steps + 1
+			FROM walk w
+			JOIN dag_relationships r ON r.child_hash = w.hash AND r.is_selected_parent = true
+			JOIN blocks p ON p.hash = r.parent_hash
+			WHERE w.hash <> $1
+			  AND p.blue_score >= (SELECT blue_score FROM blocks WHERE hash = $1)
+		)
+		SELECT hash, blue_score, is_chain_block, (MAX(steps) OVER ()) - steps
+		FROM walk
+		WHERE EXISTS (SELECT 1 FROM walk WHERE hash = $1)
+		ORDER BY steps DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error("failed to get selected chain path",
+			zap.String("from", from.String()),
+			zap.String("to", to.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get selected chain path: %w", err)
+	}
+
+	path, err := collectDAGNodes(rows)
+	if err != nil {
+		return nil, fmt.Errorf("get selected chain path: %w", err)
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("selected chain path %w: %s is not below %s", domain.ErrNotFound, from, to)
+	}
+
+	return path, nil
+}
+
+// collectDAGNodes scans hash, blue score, chain flag and distance rows and closes them
+func collectDAGNodes(rows pgx.Rows) ([]*domain.DAGNode, error) {
+	defer rows.Close()
+
+	var nodes []*domain.DAGNode
+	for rows.Next() {
+		var node domain.DAGNode
+		if err := rows.Scan(&node.Hash, &node.BlueScore, &node.IsChainBlock, &node.Distance); err != nil {
+			return nil, fmt.Errorf("scan DAG node: %w", err)
+		}
+		nodes = append(nodes, &node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return nodes, nil
+}
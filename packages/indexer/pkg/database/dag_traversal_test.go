@@ -0,0 +1,332 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// wideDAG is a synthetic DAG of levels × width blocks. Block (l, i) has parents
+// (l-1, i/2) (selected), (l-1, i) and (l-1, i+1 mod width); the blue score is the level
+// and column 0 is the selected chain.
+type wideDAG struct {
+	levels, width int
+}
+
+func (d wideDAG) hash(level, index int) domain.Hash {
+	return domain.Hash(fmt.Sprintf("0x%064x", level*100000+index+1))
+}
+
+func (d wideDAG) parents(level, index int) [][2]int {
+	if level == 0 {
+		return nil
+	}
+	parents := [][2]int{{level - 1, index / 2}}
+	for _, p := range []int{index, (index + 1) % d.width} {
+		if p != index/2 {
+			parents = append(parents, [2]int{level - 1, p})
+		}
+	}
+	return parents
+}
+
+// save writes the DAG through the bulk writer and the DAG repository
+func (d wideDAG) save(t testing.TB, ctx context.Context, conn database.DBTX) {
+	blocks := make([]*domain.Block, 0, d.levels*d.width)
+	for l := 0; l < d.levels; l++ {
+		for i := 0; i < d.width; i++ {
+			var parentHashes []domain.Hash
+			for _, p := range d.parents(l, i) {
+				parentHashes = append(parentHashes, d.hash(p[0], p[1]))
+			}
+			blocks = append(blocks, &domain.Block{
+				Hash:         d.hash(l, i),
+				Number:       int64(l),
+				ParentHashes: parentHashes,
+				Timestamp:    time.Now().Unix(),
+				BlueScore:    uint64(l),
+				IsChainBlock: i == 0,
+			})
+		}
+	}
+	require.NoError(t, database.NewBulkWriter(conn, zap.NewNop()).WriteBatch(ctx, blocks))
+
+	repo := database.NewDAGRepository(conn, zap.NewNop())
+	for l := 1; l < d.levels; l++ {
+		for i := 0; i < d.width; i++ {
+			for j, p := range d.parents(l, i) {
+				require.NoError(t, repo.SaveDAGRelationship(ctx, d.hash(l, i), d.hash(p[0], p[1]), j == 0))
+			}
+		}
+	}
+}
+
+// distances walks the DAG in memory, returning the shortest distance to every block within depth
+func (d wideDAG) distances(level, index, depth int, up bool) map[domain.Hash]int {
+	result := make(map[domain.Hash]int)
+	frontier := [][2]int{{level, index}}
+	for distance := 1; distance <= depth && len(frontier) > 0; distance++ {
+		var next [][2]int
+		for _, block := range frontier {
+			for _, neighbour := range d.neighbours(block, up) {
+				if _, seen := result[d.hash(neighbour[0], neighbour[1])]; seen {
+					continue
+				}
+				result[d.hash(neighbour[0], neighbour[1])] = distance
+				next = append(next, neighbour)
+			}
+		}
+		frontier = next
+	}
+	return result
+}
+
+func (d wideDAG) neighbours(block [2]int, up bool) [][2]int {
+	if up {
+		return d.parents(block[0], block[1])
+	}
+	var children [][2]int
+	if block[0]+1 >= d.levels {
+		return nil
+	}
+	for i := 0; i < d.width; i++ {
+		for _, p := range d.parents(block[0]+1, i) {
+			if p == block {
+				children = append(children, [2]int{block[0] + 1, i})
+			}
+		}
+	}
+	return children
+}
+
+func setupWideDAG(t testing.TB, dag wideDAG) (*database.DAGRepository, func()) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return nil, nil
+	}
+	dag.save(t, context.Background(), conn)
+	return database.NewDAGRepository(conn, zap.NewNop()), cleanup
+}
+
+// collectPages fetches every page of a traversal
+func collectPages(t *testing.T, limit int, fetch func(page domain.DAGPage) ([]*domain.DAGNode, error)) []*domain.DAGNode {
+	var all []*domain.DAGNode
+	for page := &(domain.DAGPage{Limit: limit}); page != nil; {
+		nodes, err := fetch(*page)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(nodes), limit)
+		all = append(all, nodes...)
+		page = page.Next(nodes)
+	}
+	return all
+}
+
+func assertNodeDistances(t *testing.T, expected map[domain.Hash]int, nodes []*domain.DAGNode) {
+	got := make(map[domain.Hash]int, len(nodes))
+	for i, node := range nodes {
+		_, duplicate := got[node.Hash]
+		require.False(t, duplicate, "node %s returned twice", node.Hash)
+		got[node.Hash] = node.Distance
+
+		if i > 0 {
+			prev := nodes[i-1]
+			assert.True(t, prev.Distance < node.Distance || (prev.Distance == node.Distance && prev.Hash < node.Hash),
+				"nodes must be ordered by distance, then hash")
+		}
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestDAGRepository_GetPast_Pagination(t *testing.T) {
+	dag := wideDAG{levels: 12, width: 16}
+	repo, cleanup := setupWideDAG(t, dag)
+	if repo == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	start := dag.hash(11, 9)
+
+	nodes := collectPages(t, 7, func(page domain.DAGPage) ([]*domain.DAGNode, error) {
+		return repo.GetPast(ctx, start, 4, page)
+	})
+
+	assertNodeDistances(t, dag.distances(11, 9, 4, true), nodes)
+
+	// Depth zero reaches nothing
+	nodes, err := repo.GetPast(ctx, start, 0, domain.DAGPage{})
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+}
+
+func TestDAGRepository_GetFuture_Pagination(t *testing.T) {
+	dag := wideDAG{levels: 12, width: 16}
+	repo, cleanup := setupWideDAG(t, dag)
+	if repo == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	nodes := collectPages(t, 5, func(page domain.DAGPage) ([]*domain.DAGNode, error) {
+		return repo.GetFuture(ctx, dag.hash(2, 3), 3, page)
+	})
+
+	assertNodeDistances(t, dag.distances(2, 3, 3, false), nodes)
+}
+
+func TestDAGRepository_GetAnticone(t *testing.T) {
+	dag := wideDAG{levels: 12, width: 16}
+	repo, cleanup := setupWideDAG(t, dag)
+	if repo == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	const window = 2
+
+	nodes := collectPages(t, 10, func(page domain.DAGPage) ([]*domain.DAGNode, error) {
+		return repo.GetAnticone(ctx, dag.hash(6, 4), window, page)
+	})
+
+	past := dag.distances(6, 4, dag.levels, true)
+	future := dag.distances(6, 4, dag.levels, false)
+	expected := make(map[domain.Hash]int)
+	for l := 6 - window; l <= 6+window; l++ {
+		for i := 0; i < dag.width; i++ {
+			hash := dag.hash(l, i)
+			_, inPast := past[hash]
+			_, inFuture := future[hash]
+			if hash != dag.hash(6, 4) && !inPast && !inFuture {
+				expected[hash] = max(l-6, 6-l)
+			}
+		}
+	}
+
+	require.NotEmpty(t, expected)
+	assertNodeDistances(t, expected, nodes)
+}
+
+func TestDAGRepository_GetLowestCommonChainAncestor(t *testing.T) {
+	dag := wideDAG{levels: 12, width: 16}
+	repo, cleanup := setupWideDAG(t, dag)
+	if repo == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// 13 -> 6 -> 3 -> 1 -> 0 reaches the chain at level 6; 6 -> 3 -> 1 -> 0 at level 7
+	ancestor, err := repo.GetLowestCommonChainAncestor(ctx, dag.hash(10, 13), dag.hash(10, 6))
+	require.NoError(t, err)
+	assert.Equal(t, dag.hash(6, 0), ancestor.Hash)
+	assert.Equal(t, uint64(6), ancestor.BlueScore)
+	assert.True(t, ancestor.IsChainBlock)
+
+	// A chain block is its own chain ancestor
+	ancestor, err = repo.GetLowestCommonChainAncestor(ctx, dag.hash(9, 0), dag.hash(9, 0))
+	require.NoError(t, err)
+	assert.Equal(t, dag.hash(9, 0), ancestor.Hash)
+
+	_, err = repo.GetLowestCommonChainAncestor(ctx, dag.hash(9, 0), domain.Hash(fmt.Sprintf("0x%064x", 0)))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestDAGRepository_GetSelectedChainPath(t *testing.T) {
+	dag := wideDAG{levels: 12, width: 16}
+	repo, cleanup := setupWideDAG(t, dag)
+	if repo == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	path, err := repo.GetSelectedChainPath(ctx, dag.hash(5, 0), dag.hash(10, 13))
+	require.NoError(t, err)
+
+	expected := []domain.Hash{dag.hash(5, 0), dag.hash(6, 0), dag.hash(7, 1), dag.hash(8, 3), dag.hash(9, 6), dag.hash(10, 13)}
+	require.Len(t, path, len(expected))
+	for i, node := range path {
+		assert.Equal(t, expected[i], node.Hash)
+		assert.Equal(t, i, node.Distance)
+	}
+	assert.True(t, path[0].IsChainBlock)
+	assert.False(t, path[len(path)-1].IsChainBlock)
+
+	// A block beside the chain is not on the path
+	_, err = repo.GetSelectedChainPath(ctx, dag.hash(5, 1), dag.hash(10, 13))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestDAGRepository_Traversal_Performance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping traversal performance test in short mode")
+	}
+
+	dag := wideDAG{levels: 40, width: 64}
+	repo, cleanup := setupWideDAG(t, dag)
+	if repo == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	top := dag.hash(dag.levels-1, dag.width/2)
+
+	// Every traversal over the 2,560-block DAG must stay interactive
+	const budget = 2 * time.Second
+
+	started := time.Now()
+	past, err := repo.GetPast(ctx, top, 20, domain.DAGPage{Limit: domain.MaxDAGPageLimit})
+	require.NoError(t, err)
+	assert.NotEmpty(t, past)
+	assert.Less(t, time.Since(started), budget, "GetPast")
+
+	started = time.Now()
+	future, err := repo.GetFuture(ctx, dag.hash(0, 0), 20, domain.DAGPage{Limit: domain.MaxDAGPageLimit})
+	require.NoError(t, err)
+	assert.NotEmpty(t, future)
+	assert.Less(t, time.Since(started), budget, "GetFuture")
+
+	started = time.Now()
+	_, err = repo.GetAnticone(ctx, dag.hash(20, 7), 5, domain.DAGPage{Limit: domain.MaxDAGPageLimit})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), budget, "GetAnticone")
+
+	started = time.Now()
+	_, err = repo.GetSelectedChainPath(ctx, dag.hash(0, 0), top)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), budget, "GetSelectedChainPath")
+}
+
+func BenchmarkDAGRepository_GetPast(b *testing.B) {
+	dag := wideDAG{levels: 40, width: 64}
+	repo, cleanup := setupWideDAG(b, dag)
+	if repo == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	top := dag.hash(dag.levels-1, dag.width/2)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetPast(ctx, top, 20, domain.DAGPage{Limit: domain.MaxDAGPageLimit}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// DecodeAnomalyRepository implements the DecodeAnomalyWriter interface
+type DecodeAnomalyRepository struct {
+	db     DBTX
+	logger *zap.Logger
+}
+
+// NewDecodeAnomalyRepository creates a new DecodeAnomalyRepository
+func NewDecodeAnomalyRepository(db DBTX, logger *zap.Logger) *DecodeAnomalyRepository {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &DecodeAnomalyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveDecodeAnomaly records a dropped item. Recording the same item again
+// (same block, transaction, kind and index) replaces its raw item and reason.
+func (r *DecodeAnomalyRepository) SaveDecodeAnomaly(ctx context.Context, anomaly *domain.DecodeAnomaly) error {
+	if err := anomaly.Validate(); err != nil {
+		return fmt.Errorf("invalid decode anomaly: %w", err)
+	}
+
+	query := `
+		INSERT INTO decode_anomalies (
+			block_hash, transaction_hash, kind, item_index, raw_item, reason
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+		ON CONFLICT (block_hash, (COALESCE(transaction_hash, '')), kind, item_index) DO UPDATE SET
+			raw_item = EXCLUDED.raw_item,
+			reason = EXCLUDED.reason,
+			detected_at = NOW()
+		RETURNING id, detected_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		anomaly.BlockHash,
+		anomaly.TransactionHash,
+		anomaly.Kind,
+		anomaly.ItemIndex,
+		anomaly.RawItem,
+		anomaly.Reason,
+	).Scan(&anomaly.ID, &anomaly.DetectedAt)
+
+	if err != nil {
+		r.logger.Error("failed to save decode anomaly",
+			zap.String("blockHash", anomaly.BlockHash.String()),
+			zap.String("kind", anomaly.Kind),
+			zap.Int("itemIndex", anomaly.ItemIndex),
+			zap.Error(err))
+		return fmt.Errorf("save decode anomaly: %w", err)
+	}
+
+	return nil
+}
+
+// GetDecodeAnomaliesByBlockHash retrieves every item dropped from a block and its receipts
+func (r *DecodeAnomalyRepository) GetDecodeAnomaliesByBlockHash(ctx context.Context, blockHash domain.Hash) ([]*domain.DecodeAnomaly, error) {
+	query := `
+		SELECT id, block_hash, transaction_hash, kind, item_index, raw_item, reason, detected_at
+		FROM decode_anomalies
+		WHERE block_hash = $1
+		ORDER BY transaction_hash NULLS FIRST, kind, item_index
+	`
+
+	rows, err := r.db.Query(ctx, query, blockHash)
+	if err != nil {
+		r.logger.Error("failed to get decode anomalies",
+			zap.String("blockHash", blockHash.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get decode anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	var anomalies []*domain.DecodeAnomaly
+	for rows.Next() {
+		var anomaly domain.DecodeAnomaly
+		if err := rows.Scan(
+			&anomaly.ID,
+			&anomaly.BlockHash,
+			&anomaly.TransactionHash,
+			&anomaly.Kind,
+			&anomaly.ItemIndex,
+			&anomaly.RawItem,
+			&anomaly.Reason,
+			&anomaly.DetectedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan decode anomaly: %w", err)
+		}
+		anomalies = append(anomalies, &anomaly)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate decode anomalies: %w", err)
+	}
+
+	return anomalies, nil
+}
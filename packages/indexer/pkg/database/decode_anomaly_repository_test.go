@@ -0,0 +1,73 @@
+package database_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestDecodeAnomalyRepository_SaveDecodeAnomaly(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = conn.Exec(ctx, "TRUNCATE TABLE decode_anomalies")
+
+	repo := database.NewDecodeAnomalyRepository(conn, zap.NewNop())
+
+	blockHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	txHash := domain.Hash("0x" + strings.Repeat("b", 64))
+
+	dropped := &domain.DecodeAnomaly{
+		BlockHash: blockHash,
+		Kind:      domain.AnomalyKindTransaction,
+		ItemIndex: 3,
+		RawItem:   `{"gas":"lots"}`,
+		Reason:    "invalid gas",
+	}
+	require.NoError(t, repo.SaveDecodeAnomaly(ctx, dropped))
+	assert.NotZero(t, dropped.ID)
+
+	droppedLog := &domain.DecodeAnomaly{
+		BlockHash:       blockHash,
+		TransactionHash: &txHash,
+		Kind:            domain.AnomalyKindLog,
+		ItemIndex:       3,
+		RawItem:         `{"data":"0xabc"}`,
+		Reason:          "odd length hex string",
+	}
+	require.NoError(t, repo.SaveDecodeAnomaly(ctx, droppedLog))
+
+	// Re-indexing the block replaces the anomaly instead of adding another
+	dropped.Reason = "invalid gas quantity"
+	require.NoError(t, repo.SaveDecodeAnomaly(ctx, dropped))
+
+	anomalies, err := repo.GetDecodeAnomaliesByBlockHash(ctx, blockHash)
+	require.NoError(t, err)
+	require.Len(t, anomalies, 2)
+
+	assert.Nil(t, anomalies[0].TransactionHash)
+	assert.Equal(t, "invalid gas quantity", anomalies[0].Reason)
+	assert.Equal(t, `{"gas":"lots"}`, anomalies[0].RawItem)
+
+	require.NotNil(t, anomalies[1].TransactionHash)
+	assert.Equal(t, txHash, *anomalies[1].TransactionHash)
+	assert.Equal(t, domain.AnomalyKindLog, anomalies[1].Kind)
+}
+
+func TestDecodeAnomalyRepository_SaveDecodeAnomaly_Invalid(t *testing.T) {
+	repo := database.NewDecodeAnomalyRepository(nil, zap.NewNop())
+
+	err := repo.SaveDecodeAnomaly(context.Background(), &domain.DecodeAnomaly{BlockHash: "0xabc"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Binary payloads (input data, log data, contract code) are stored as lowercase
+// 0x-prefixed hex in TEXT columns, the same form the node and the API use.
+// Raw bytes cannot go into TEXT: Postgres rejects invalid UTF-8 and NUL bytes.
+
+// hexFromBytes encodes a payload for storage; nil and empty both become "0x"
+func hexFromBytes(b []byte) string {
+	return hexutil.Encode(b)
+}
+
+// nullHexFromBytes encodes an optional payload; nil and empty become NULL
+func nullHexFromBytes(b []byte) *string {
+	if len(b) == 0 {
+		return nil
+	}
+	s := hexutil.Encode(b)
+	return &s
+}
+
+// bytesFromHex decodes a stored payload; NULL and "" decode to an empty slice
+func bytesFromHex(s *string) ([]byte, error) {
+	if s == nil || *s == "" {
+		return []byte{}, nil
+	}
+
+	b, err := hexutil.Decode(*s)
+	if err != nil {
+		return nil, fmt.Errorf("decode hex payload: %w", err)
+	}
+	return b, nil
+}
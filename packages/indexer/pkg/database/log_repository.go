@@ -4,36 +4,39 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
 )
 
 // LogRepository implements LogReader and LogWriter interfaces
 type LogRepository struct {
-	conn   *pgx.Conn
+	db     DBTX
 	logger *zap.Logger
 }
 
 // NewLogRepository creates a new LogRepository
-func NewLogRepository(conn *pgx.Conn, logger *zap.Logger) *LogRepository {
+func NewLogRepository(db DBTX, logger *zap.Logger) *LogRepository {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 	return &LogRepository{
-		conn:   conn,
+		db:     db,
 		logger: logger,
 	}
 }
 
-// SaveLog saves an event log to the database
+// SaveLog saves an event log to the database. A log that is already stored is left
+// untouched: like the transaction row, it keeps the block its transaction was first
+// stored with, and later inclusions are recorded in transaction_inclusions.
 func (r *LogRepository) SaveLog(ctx context.Context, log *domain.Log) error {
 	query := `
 		INSERT INTO event_logs (
 			transaction_hash, log_index, block_hash, block_number,
 			address, topics, data, timestamp
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8
+			$1, $2, $3, $4, $5, $6, $7,
+			-- Callers without block context leave the timestamp to the stored block
+			COALESCE(NULLIF($8::BIGINT, 0), (SELECT timestamp FROM blocks WHERE hash = $3), 0)
 		)
 		ON CONFLICT (transaction_hash, log_index) DO NOTHING
 	`
@@ -44,24 +47,20 @@ func (r *LogRepository) SaveLog(ctx context.Context, log *domain.Log) error {
 		eventSignature = &log.Topics[0]
 	}
 
-	// Use block number as timestamp placeholder
-	// In production, we'd get actual timestamp from block
-	timestamp := log.BlockNumber
-
-	_, err := r.conn.Exec(ctx, query,
+	_, err := r.db.Exec(ctx, query,
 		log.TransactionHash,
 		log.LogIndex,
 		log.BlockHash,
 		log.BlockNumber,
 		log.Address,
 		log.Topics,
-		string(log.Data),
-		timestamp,
+		hexFromBytes(log.Data),
+		log.Timestamp,
 	)
 
 	if err != nil {
 		r.logger.Error("failed to save log",
-			zap.String("transactionHash", log.TransactionHash),
+			zap.String("transactionHash", log.TransactionHash.String()),
 			zap.Uint64("logIndex", log.LogIndex),
 			zap.Error(err))
 		return fmt.Errorf("save log: %w", err)
@@ -74,14 +73,14 @@ func (r *LogRepository) SaveLog(ctx context.Context, log *domain.Log) error {
 			SET event_signature = $1
 			WHERE transaction_hash = $2 AND log_index = $3
 		`
-		_, _ = r.conn.Exec(ctx, updateQuery, eventSignature, log.TransactionHash, log.LogIndex)
+		_, _ = r.db.Exec(ctx, updateQuery, eventSignature, log.TransactionHash, log.LogIndex)
 	}
 
 	return nil
 }
 
 // GetLogsByTransactionHash retrieves all logs for a transaction
-func (r *LogRepository) GetLogsByTransactionHash(ctx context.Context, txHash string) ([]*domain.Log, error) {
+func (r *LogRepository) GetLogsByTransactionHash(ctx context.Context, txHash domain.Hash) ([]*domain.Log, error) {
 	query := `
 		SELECT transaction_hash, log_index, address, topics, data,
 		       block_number, block_hash, timestamp
@@ -90,10 +89,10 @@ func (r *LogRepository) GetLogsByTransactionHash(ctx context.Context, txHash str
 		ORDER BY log_index ASC
 	`
 
-	rows, err := r.conn.Query(ctx, query, txHash)
+	rows, err := r.db.Query(ctx, query, txHash)
 	if err != nil {
 		r.logger.Error("failed to get logs by transaction hash",
-			zap.String("txHash", txHash),
+			zap.String("txHash", txHash.String()),
 			zap.Error(err))
 		return nil, fmt.Errorf("get logs by transaction hash: %w", err)
 	}
@@ -103,8 +102,7 @@ func (r *LogRepository) GetLogsByTransactionHash(ctx context.Context, txHash str
 	for rows.Next() {
 		var log domain.Log
 		var topics []string
-		var data string
-		var timestamp int64
+		var data *string
 
 		err := rows.Scan(
 			&log.TransactionHash,
@@ -114,14 +112,16 @@ func (r *LogRepository) GetLogsByTransactionHash(ctx context.Context, txHash str
 			&data,
 			&log.BlockNumber,
 			&log.BlockHash,
-			&timestamp,
+			&log.Timestamp,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan log: %w", err)
 		}
 
 		log.Topics = topics
-		log.Data = []byte(data)
+		if log.Data, err = bytesFromHex(data); err != nil {
+			return nil, fmt.Errorf("parse log data: %w", err)
+		}
 
 		logs = append(logs, &log)
 	}
@@ -134,7 +134,7 @@ func (r *LogRepository) GetLogsByTransactionHash(ctx context.Context, txHash str
 }
 
 // GetLogsByAddress retrieves logs for an address within a block range
-func (r *LogRepository) GetLogsByAddress(ctx context.Context, address string, fromBlock, toBlock int64) ([]*domain.Log, error) {
+func (r *LogRepository) GetLogsByAddress(ctx context.Context, address domain.Address, fromBlock, toBlock int64) ([]*domain.Log, error) {
 	query := `
 		SELECT transaction_hash, log_index, address, topics, data,
 		       block_number, block_hash, timestamp
@@ -145,10 +145,10 @@ func (r *LogRepository) GetLogsByAddress(ctx context.Context, address string, fr
 		ORDER BY block_number ASC, log_index ASC
 	`
 
-	rows, err := r.conn.Query(ctx, query, address, fromBlock, toBlock)
+	rows, err := r.db.Query(ctx, query, address, fromBlock, toBlock)
 	if err != nil {
 		r.logger.Error("failed to get logs by address",
-			zap.String("address", address),
+			zap.String("address", address.String()),
 			zap.Int64("fromBlock", fromBlock),
 			zap.Int64("toBlock", toBlock),
 			zap.Error(err))
@@ -160,8 +160,7 @@ func (r *LogRepository) GetLogsByAddress(ctx context.Context, address string, fr
 	for rows.Next() {
 		var log domain.Log
 		var topics []string
-		var data string
-		var timestamp int64
+		var data *string
 
 		err := rows.Scan(
 			&log.TransactionHash,
@@ -171,14 +170,16 @@ func (r *LogRepository) GetLogsByAddress(ctx context.Context, address string, fr
 			&data,
 			&log.BlockNumber,
 			&log.BlockHash,
-			&timestamp,
+			&log.Timestamp,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan log: %w", err)
 		}
 
 		log.Topics = topics
-		log.Data = []byte(data)
+		if log.Data, err = bytesFromHex(data); err != nil {
+			return nil, fmt.Errorf("parse log data: %w", err)
+		}
 
 		logs = append(logs, &log)
 	}
@@ -2,6 +2,7 @@ package database_test
 
 import (
 	"context"
+	"math/big"
 	"strings"
 	"testing"
 	"time"
@@ -25,9 +26,9 @@ func TestLogRepository_SaveLog(t *testing.T) {
 	// Setup block and transaction
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -40,13 +41,13 @@ func TestLogRepository_SaveLog(t *testing.T) {
 
 	txRepo := database.NewTransactionRepository(conn, zap.NewNop())
 	tx := &domain.Transaction{
-		Hash:             "0x" + strings.Repeat("b", 64),
+		Hash:             domain.Hash("0x" + strings.Repeat("b", 64)),
 		BlockHash:        block.Hash,
 		BlockNumber:      block.Number,
 		TransactionIndex: 0,
-		From:             "0x" + strings.Repeat("c", 40),
-		To:               stringPtr("0x" + strings.Repeat("d", 40)),
-		Value:            1000000000000000000,
+		From:             domain.Address("0x" + strings.Repeat("c", 40)),
+		To:               addressPtr(domain.Address("0x" + strings.Repeat("d", 40))),
+		Value:            big.NewInt(1000000000000000000),
 		GasLimit:         21000,
 		Nonce:            5,
 		Input:            []byte{},
@@ -61,7 +62,7 @@ func TestLogRepository_SaveLog(t *testing.T) {
 	log := &domain.Log{
 		TransactionHash: tx.Hash,
 		LogIndex:        0,
-		Address:         "0x" + strings.Repeat("e", 40),
+		Address:         domain.Address("0x" + strings.Repeat("e", 40)),
 		Topics:          []string{"0x" + strings.Repeat("f", 64)},
 		Data:            []byte{0x01, 0x02, 0x03},
 		BlockNumber:     block.Number,
@@ -90,9 +91,9 @@ func TestLogRepository_GetLogsByTransactionHash(t *testing.T) {
 	// Setup
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -104,13 +105,13 @@ func TestLogRepository_GetLogsByTransactionHash(t *testing.T) {
 
 	txRepo := database.NewTransactionRepository(conn, zap.NewNop())
 	tx := &domain.Transaction{
-		Hash:             "0x" + strings.Repeat("b", 64),
+		Hash:             domain.Hash("0x" + strings.Repeat("b", 64)),
 		BlockHash:        block.Hash,
 		BlockNumber:      block.Number,
 		TransactionIndex: 0,
-		From:             "0x" + strings.Repeat("c", 40),
-		To:               stringPtr("0x" + strings.Repeat("d", 40)),
-		Value:            1000000000000000000,
+		From:             domain.Address("0x" + strings.Repeat("c", 40)),
+		To:               addressPtr(domain.Address("0x" + strings.Repeat("d", 40))),
+		Value:            big.NewInt(1000000000000000000),
 		GasLimit:         21000,
 		Nonce:            5,
 		Input:            []byte{},
@@ -126,7 +127,7 @@ func TestLogRepository_GetLogsByTransactionHash(t *testing.T) {
 		log := &domain.Log{
 			TransactionHash: tx.Hash,
 			LogIndex:        uint64(i),
-			Address:         "0x" + strings.Repeat("e", 40),
+			Address:         domain.Address("0x" + strings.Repeat("e", 40)),
 			Topics:          []string{"0x" + strings.Repeat(string(rune('f'+i)), 64)},
 			Data:            []byte{byte(i)},
 			BlockNumber:     block.Number,
@@ -158,9 +159,9 @@ func TestLogRepository_GetLogsByAddress(t *testing.T) {
 	// Setup blocks
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block1 := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -171,9 +172,9 @@ func TestLogRepository_GetLogsByAddress(t *testing.T) {
 	blockRepo.SaveBlock(ctx, block1)
 
 	block2 := &domain.Block{
-		Hash:         "0x" + strings.Repeat("b", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("b", 64)),
 		Number:      101,
-		ParentHashes: []string{block1.Hash},
+		ParentHashes: []domain.Hash{block1.Hash},
 		Timestamp:   time.Now().Unix() + 1,
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -186,13 +187,13 @@ func TestLogRepository_GetLogsByAddress(t *testing.T) {
 	// Setup transactions
 	txRepo := database.NewTransactionRepository(conn, zap.NewNop())
 	tx1 := &domain.Transaction{
-		Hash:             "0x" + strings.Repeat("c", 64),
+		Hash:             domain.Hash("0x" + strings.Repeat("c", 64)),
 		BlockHash:        block1.Hash,
 		BlockNumber:      block1.Number,
 		TransactionIndex: 0,
-		From:             "0x" + strings.Repeat("d", 40),
-		To:               stringPtr("0x" + strings.Repeat("e", 40)),
-		Value:            1000000000000000000,
+		From:             domain.Address("0x" + strings.Repeat("d", 40)),
+		To:               addressPtr(domain.Address("0x" + strings.Repeat("e", 40))),
+		Value:            big.NewInt(1000000000000000000),
 		GasLimit:         21000,
 		Nonce:            5,
 		Input:            []byte{},
@@ -202,13 +203,13 @@ func TestLogRepository_GetLogsByAddress(t *testing.T) {
 	txRepo.SaveTransaction(ctx, tx1)
 
 	tx2 := &domain.Transaction{
-		Hash:             "0x" + strings.Repeat("f", 64),
+		Hash:             domain.Hash("0x" + strings.Repeat("f", 64)),
 		BlockHash:        block2.Hash,
 		BlockNumber:      block2.Number,
 		TransactionIndex: 0,
-		From:             "0x" + strings.Repeat("d", 40),
-		To:               stringPtr("0x" + strings.Repeat("e", 40)),
-		Value:            1000000000000000000,
+		From:             domain.Address("0x" + strings.Repeat("d", 40)),
+		To:               addressPtr(domain.Address("0x" + strings.Repeat("e", 40))),
+		Value:            big.NewInt(1000000000000000000),
 		GasLimit:         21000,
 		Nonce:            6,
 		Input:            []byte{},
@@ -219,7 +220,7 @@ func TestLogRepository_GetLogsByAddress(t *testing.T) {
 
 	// Save logs for same address
 	repo := database.NewLogRepository(conn, zap.NewNop())
-	targetAddress := "0x" + strings.Repeat("e", 40)
+	targetAddress := domain.Address("0x" + strings.Repeat("e", 40))
 
 	log1 := &domain.Log{
 		TransactionHash: tx1.Hash,
@@ -259,7 +260,7 @@ func TestLogRepository_GetLogsByAddress_EmptyResult(t *testing.T) {
 	ctx := context.Background()
 
 	repo := database.NewLogRepository(conn, zap.NewNop())
-	logs, err := repo.GetLogsByAddress(ctx, "0x"+strings.Repeat("z", 40), 0, 1000)
+	logs, err := repo.GetLogsByAddress(ctx, domain.Address("0x"+strings.Repeat("z", 40)), 0, 1000)
 	require.NoError(t, err)
 	assert.Len(t, logs, 0)
 }
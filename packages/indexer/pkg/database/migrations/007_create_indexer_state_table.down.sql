@@ -0,0 +1,2 @@
+-- Rollback: Drop indexer state table
+DROP TABLE IF EXISTS indexer_state;
@@ -0,0 +1,20 @@
+-- Migration: Create indexer state table
+-- Created: 2025-01-24
+-- Description: Creates the indexer_state table for storing durable sync checkpoints
+
+CREATE TABLE IF NOT EXISTS indexer_state (
+    -- Primary Key (one row per sync process)
+    name VARCHAR(64) PRIMARY KEY,
+    
+    -- Last fully committed block
+    last_block_number BIGINT NOT NULL,
+    last_block_hash VARCHAR(66) NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    
+    -- Constraints
+    CONSTRAINT chk_state_block_hash_format CHECK (last_block_hash ~ '^0x[0-9a-f]{64}$'),
+    CONSTRAINT chk_state_block_number_positive CHECK (last_block_number >= 0)
+);
@@ -0,0 +1,6 @@
+-- Rollback: Drop reorg tracking
+DROP INDEX IF EXISTS idx_reorg_events_fork_point;
+DROP INDEX IF EXISTS idx_reorg_events_detected_at;
+DROP TABLE IF EXISTS reorg_events;
+DROP INDEX IF EXISTS idx_transactions_not_accepted;
+ALTER TABLE transactions DROP COLUMN IF EXISTS is_accepted;
@@ -0,0 +1,46 @@
+-- Migration: Create reorg tracking
+-- Created: 2025-01-24
+-- Description: Adds transaction acceptance and the reorg_events table used to
+--              notify consumers when the virtual selected-parent chain changes
+
+-- Transactions start unaccepted; the indexer accepts them once their block is a
+-- chain block or is merged as blue by one
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS is_accepted BOOLEAN NOT NULL DEFAULT false;
+
+-- Accept the transactions already stored the same way
+UPDATE transactions t
+SET is_accepted = true
+FROM blocks b
+WHERE t.block_hash = b.hash
+  AND (
+    b.is_chain_block OR EXISTS (
+        SELECT 1
+        FROM ghostdag_data g
+        JOIN blocks c ON c.hash = g.block_hash
+        WHERE c.is_chain_block = true
+          AND b.hash = ANY(g.merge_set_blues)
+    )
+  );
+
+CREATE INDEX IF NOT EXISTS idx_transactions_not_accepted ON transactions(is_accepted) WHERE is_accepted = false;
+
+CREATE TABLE IF NOT EXISTS reorg_events (
+    -- Primary Key
+    id BIGSERIAL PRIMARY KEY,
+    
+    -- Last chain block shared by the old and new chain
+    fork_point_hash VARCHAR(66) NOT NULL,
+    fork_point_blue_score BIGINT NOT NULL,
+    
+    -- Chain blocks that left and joined the selected-parent chain
+    removed_hashes TEXT[] NOT NULL DEFAULT '{}',
+    added_hashes TEXT[] NOT NULL DEFAULT '{}',
+    
+    -- Timestamps
+    detected_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_reorg_events_detected_at ON reorg_events(detected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reorg_events_fork_point ON reorg_events(fork_point_hash);
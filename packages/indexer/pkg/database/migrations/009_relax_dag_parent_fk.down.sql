@@ -0,0 +1,5 @@
+-- Rollback: Restore DAG parent foreign key
+-- NOT VALID keeps existing relationships to parents that were never indexed
+ALTER TABLE dag_relationships
+    ADD CONSTRAINT fk_dag_parent FOREIGN KEY (parent_hash)
+        REFERENCES blocks(hash) ON DELETE CASCADE NOT VALID;
@@ -0,0 +1,7 @@
+-- Migration: Relax DAG parent foreign key
+-- Created: 2025-01-24
+-- Description: Parents may be indexed after their children (concurrent range
+--              workers, DAG sync of merged blocks), so relationships can no
+--              longer require the parent block to exist when they are saved
+
+ALTER TABLE dag_relationships DROP CONSTRAINT IF EXISTS fk_dag_parent;
@@ -0,0 +1,14 @@
+-- Rollback: Remove typed transaction fields
+DROP INDEX IF EXISTS idx_access_lists_address;
+DROP TABLE IF EXISTS transaction_access_lists;
+
+DROP INDEX IF EXISTS idx_transactions_type;
+
+-- Signatures with v beyond INTEGER cannot be kept
+UPDATE transactions SET v = NULL WHERE v > 2147483647;
+ALTER TABLE transactions ALTER COLUMN v TYPE INTEGER;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS blob_versioned_hashes,
+    DROP COLUMN IF EXISTS max_fee_per_blob_gas,
+    DROP COLUMN IF EXISTS chain_id;
@@ -0,0 +1,36 @@
+-- Migration: Add typed transaction fields
+-- Created: 2025-01-24
+-- Description: Stores EIP-2718 typed transaction fields (chain ID, blob fees and
+--              versioned hashes) and EIP-2930 access lists. v is widened because
+--              EIP-155 legacy signatures encode the chain ID and can exceed INTEGER.
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS chain_id NUMERIC(78, 0),
+    ADD COLUMN IF NOT EXISTS max_fee_per_blob_gas NUMERIC(78, 0),
+    ADD COLUMN IF NOT EXISTS blob_versioned_hashes TEXT[];
+
+ALTER TABLE transactions ALTER COLUMN v TYPE NUMERIC(78, 0);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_type ON transactions(transaction_type);
+
+CREATE TABLE IF NOT EXISTS transaction_access_lists (
+    -- Entry Identification
+    transaction_hash VARCHAR(66) NOT NULL,
+    entry_index INTEGER NOT NULL,
+    
+    -- Accessed Account and Storage
+    address VARCHAR(42) NOT NULL,
+    storage_keys TEXT[] NOT NULL DEFAULT '{}',
+    
+    PRIMARY KEY (transaction_hash, entry_index),
+    
+    -- Foreign Keys
+    CONSTRAINT fk_access_lists_transaction FOREIGN KEY (transaction_hash)
+        REFERENCES transactions(hash) ON DELETE CASCADE,
+    
+    -- Constraints
+    CONSTRAINT chk_access_list_address_format CHECK (address ~ '^0x[0-9a-fA-F]{40}$')
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_access_lists_address ON transaction_access_lists(address);
@@ -0,0 +1,4 @@
+-- Rollback: Remove receipt fields
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS logs_bloom,
+    DROP COLUMN IF EXISTS cumulative_gas_used;
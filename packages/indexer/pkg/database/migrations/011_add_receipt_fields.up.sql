@@ -0,0 +1,8 @@
+-- Migration: Add receipt fields
+-- Created: 2025-01-24
+-- Description: Stores the receipt fields that have no column yet. Contract
+--              address, effective gas price and type reuse existing columns.
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS cumulative_gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS logs_bloom VARCHAR(514);
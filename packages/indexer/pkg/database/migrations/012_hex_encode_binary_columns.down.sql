@@ -0,0 +1,25 @@
+-- Rollback: Restore raw binary columns
+ALTER TABLE addresses DROP CONSTRAINT IF EXISTS chk_contract_code_hex;
+ALTER TABLE event_logs DROP CONSTRAINT IF EXISTS chk_log_data_hex;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_input_data_hex;
+
+UPDATE addresses
+SET contract_code = substr(contract_code, 3)
+WHERE contract_code IS NOT NULL;
+
+-- Payloads that are not valid UTF-8 cannot be stored as raw text and become NULL
+CREATE FUNCTION pg_temp.hex_to_raw_text(payload TEXT) RETURNS TEXT AS $$
+BEGIN
+    RETURN convert_from(decode(substr(payload, 3), 'hex'), 'UTF8');
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE transactions
+SET input_data = pg_temp.hex_to_raw_text(input_data)
+WHERE input_data IS NOT NULL;
+
+UPDATE event_logs
+SET data = pg_temp.hex_to_raw_text(data)
+WHERE data IS NOT NULL;
@@ -0,0 +1,30 @@
+-- Migration: Hex-encode binary columns
+-- Created: 2025-01-24
+-- Description: Input data and log data were stored as raw bytes cast to text and
+--              contract code as unprefixed hex. All three now hold lowercase
+--              0x-prefixed hex, so existing values are re-encoded in place.
+
+-- Raw bytes that reached TEXT were valid UTF-8, so convert_to recovers them exactly
+UPDATE transactions
+SET input_data = '0x' || encode(convert_to(input_data, 'UTF8'), 'hex')
+WHERE input_data IS NOT NULL;
+
+UPDATE event_logs
+SET data = '0x' || encode(convert_to(data, 'UTF8'), 'hex')
+WHERE data IS NOT NULL;
+
+UPDATE addresses
+SET contract_code = CASE
+        WHEN contract_code = '' THEN NULL
+        ELSE '0x' || lower(contract_code)
+    END
+WHERE contract_code IS NOT NULL;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT chk_input_data_hex CHECK (input_data ~ '^0x([0-9a-f]{2})*$');
+
+ALTER TABLE event_logs
+    ADD CONSTRAINT chk_log_data_hex CHECK (data ~ '^0x([0-9a-f]{2})*$');
+
+ALTER TABLE addresses
+    ADD CONSTRAINT chk_contract_code_hex CHECK (contract_code ~ '^0x([0-9a-f]{2})+$');
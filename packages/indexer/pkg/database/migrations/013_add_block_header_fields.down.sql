@@ -0,0 +1,9 @@
+-- Rollback: Remove block header fields
+DROP INDEX IF EXISTS idx_blocks_daa_score;
+ALTER TABLE blocks DROP CONSTRAINT IF EXISTS chk_extra_data_hex;
+ALTER TABLE blocks
+    DROP COLUMN IF EXISTS pruning_point_hash,
+    DROP COLUMN IF EXISTS daa_score,
+    DROP COLUMN IF EXISTS mix_hash,
+    DROP COLUMN IF EXISTS logs_bloom,
+    DROP COLUMN IF EXISTS extra_data;
@@ -0,0 +1,17 @@
+-- Migration: Add block header fields
+-- Created: 2025-01-24
+-- Description: Adds the header fields that have no column yet. Size, difficulty,
+--              nonce and blue work already exist and are now populated as well.
+
+ALTER TABLE blocks
+    ADD COLUMN IF NOT EXISTS extra_data TEXT,
+    ADD COLUMN IF NOT EXISTS logs_bloom VARCHAR(514),
+    ADD COLUMN IF NOT EXISTS mix_hash VARCHAR(66),
+    ADD COLUMN IF NOT EXISTS daa_score BIGINT,
+    ADD COLUMN IF NOT EXISTS pruning_point_hash VARCHAR(66);
+
+-- Binary payloads are 0x-hex, like input and log data
+ALTER TABLE blocks
+    ADD CONSTRAINT chk_extra_data_hex CHECK (extra_data ~ '^0x([0-9a-f]{2})*$');
+
+CREATE INDEX IF NOT EXISTS idx_blocks_daa_score ON blocks(daa_score DESC) WHERE daa_score IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Rollback: Drop decode anomalies table
+DROP TABLE IF EXISTS decode_anomalies CASCADE;
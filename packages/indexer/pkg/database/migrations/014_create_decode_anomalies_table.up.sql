@@ -0,0 +1,32 @@
+-- Migration: Create decode anomalies table
+-- Created: 2025-01-24
+-- Description: Records transactions and logs that were dropped from RPC responses
+--              because they could not be decoded (lenient decode mode only)
+
+CREATE TABLE IF NOT EXISTS decode_anomalies (
+    -- Primary Key
+    id BIGSERIAL PRIMARY KEY,
+    
+    -- Where the item was dropped from; transaction_hash is set for receipt logs
+    block_hash VARCHAR(66) NOT NULL,
+    transaction_hash VARCHAR(66),
+    kind VARCHAR(16) NOT NULL,
+    item_index INTEGER NOT NULL,
+    
+    -- The item as received and why it was dropped
+    raw_item TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    
+    -- Timestamps
+    detected_at TIMESTAMP DEFAULT NOW(),
+    
+    -- Constraints
+    CONSTRAINT chk_anomaly_kind CHECK (kind IN ('transaction', 'log')),
+    CONSTRAINT chk_anomaly_item_index_positive CHECK (item_index >= 0)
+);
+
+-- Re-indexing a block updates its anomalies instead of duplicating them
+CREATE UNIQUE INDEX IF NOT EXISTS idx_decode_anomalies_item
+    ON decode_anomalies(block_hash, (COALESCE(transaction_hash, '')), kind, item_index);
+
+CREATE INDEX IF NOT EXISTS idx_decode_anomalies_detected_at ON decode_anomalies(detected_at DESC);
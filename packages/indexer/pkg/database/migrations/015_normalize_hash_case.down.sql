@@ -0,0 +1,22 @@
+-- Rollback: Accept addresses in any case
+-- Lowercased values are valid under the original constraints and are kept
+ALTER TABLE blocks DROP CONSTRAINT IF EXISTS chk_miner_address_format;
+
+ALTER TABLE event_logs DROP CONSTRAINT IF EXISTS chk_log_address_format;
+ALTER TABLE event_logs
+    ADD CONSTRAINT chk_log_address_format CHECK (address ~ '^0x[0-9a-fA-F]{40}$');
+
+ALTER TABLE transaction_access_lists DROP CONSTRAINT IF EXISTS chk_access_list_address_format;
+ALTER TABLE transaction_access_lists
+    ADD CONSTRAINT chk_access_list_address_format CHECK (address ~ '^0x[0-9a-fA-F]{40}$');
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_address_format;
+ALTER TABLE transactions
+    ADD CONSTRAINT chk_address_format CHECK (
+        from_address ~ '^0x[0-9a-fA-F]{40}$' AND
+        (to_address IS NULL OR to_address ~ '^0x[0-9a-fA-F]{40}$')
+    );
+
+ALTER TABLE addresses DROP CONSTRAINT IF EXISTS chk_address_format;
+ALTER TABLE addresses
+    ADD CONSTRAINT chk_address_format CHECK (address ~ '^0x[0-9a-fA-F]{40}$');
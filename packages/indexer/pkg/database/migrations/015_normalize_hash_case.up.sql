@@ -0,0 +1,88 @@
+-- Migration: Normalize hash and address case
+-- Created: 2025-01-24
+-- Description: Hashes and addresses are stored in canonical lowercase form so
+--              lookups are exact matches. Addresses were accepted in any case
+--              and hashes that no constraint checked were stored as the node
+--              reported them, so existing values are lowercased and the
+--              address constraints tightened to lowercase hex.
+
+-- Blocks without a reported miner were stored with an empty address
+UPDATE blocks
+SET miner_address = NULL
+WHERE miner_address = '';
+
+UPDATE blocks
+SET miner_address = lower(miner_address),
+    selected_parent_hash = lower(selected_parent_hash),
+    pruning_point_hash = lower(pruning_point_hash)
+WHERE miner_address <> lower(miner_address)
+   OR selected_parent_hash <> lower(selected_parent_hash)
+   OR pruning_point_hash <> lower(pruning_point_hash);
+
+UPDATE transactions
+SET from_address = lower(from_address),
+    to_address = lower(to_address),
+    contract_address = lower(contract_address)
+WHERE from_address <> lower(from_address)
+   OR to_address <> lower(to_address)
+   OR contract_address <> lower(contract_address);
+
+UPDATE transaction_access_lists
+SET address = lower(address)
+WHERE address <> lower(address);
+
+UPDATE event_logs
+SET address = lower(address)
+WHERE address <> lower(address);
+
+-- The same parent may already be recorded in lowercase
+DELETE FROM dag_relationships r
+USING dag_relationships c
+WHERE r.parent_hash <> lower(r.parent_hash)
+  AND c.child_hash = r.child_hash
+  AND c.parent_hash = lower(r.parent_hash);
+
+UPDATE dag_relationships
+SET parent_hash = lower(parent_hash)
+WHERE parent_hash <> lower(parent_hash);
+
+UPDATE ghostdag_data
+SET selected_parent = lower(selected_parent),
+    merge_set_blues = lower(merge_set_blues::TEXT)::TEXT[],
+    merge_set_reds = lower(merge_set_reds::TEXT)::TEXT[]
+WHERE selected_parent <> lower(selected_parent)
+   OR merge_set_blues::TEXT <> lower(merge_set_blues::TEXT)
+   OR merge_set_reds::TEXT <> lower(merge_set_reds::TEXT);
+
+-- An account saved under several spellings keeps its lowercase row
+DELETE FROM addresses a
+USING addresses b
+WHERE a.address <> lower(a.address)
+  AND b.address = lower(a.address);
+
+UPDATE addresses
+SET address = lower(address)
+WHERE address <> lower(address);
+
+ALTER TABLE addresses DROP CONSTRAINT IF EXISTS chk_address_format;
+ALTER TABLE addresses
+    ADD CONSTRAINT chk_address_format CHECK (address ~ '^0x[0-9a-f]{40}$');
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_address_format;
+ALTER TABLE transactions
+    ADD CONSTRAINT chk_address_format CHECK (
+        from_address ~ '^0x[0-9a-f]{40}$' AND
+        (to_address IS NULL OR to_address ~ '^0x[0-9a-f]{40}$') AND
+        (contract_address IS NULL OR contract_address ~ '^0x[0-9a-f]{40}$')
+    );
+
+ALTER TABLE transaction_access_lists DROP CONSTRAINT IF EXISTS chk_access_list_address_format;
+ALTER TABLE transaction_access_lists
+    ADD CONSTRAINT chk_access_list_address_format CHECK (address ~ '^0x[0-9a-f]{40}$');
+
+ALTER TABLE event_logs DROP CONSTRAINT IF EXISTS chk_log_address_format;
+ALTER TABLE event_logs
+    ADD CONSTRAINT chk_log_address_format CHECK (address ~ '^0x[0-9a-f]{40}$');
+
+ALTER TABLE blocks
+    ADD CONSTRAINT chk_miner_address_format CHECK (miner_address IS NULL OR miner_address ~ '^0x[0-9a-f]{40}$');
@@ -0,0 +1,5 @@
+-- Rollback: Drop transaction inclusions table
+DROP INDEX IF EXISTS idx_transactions_sender_nonce;
+DROP INDEX IF EXISTS idx_inclusions_accepting_block;
+DROP INDEX IF EXISTS idx_inclusions_block;
+DROP TABLE IF EXISTS transaction_inclusions;
@@ -0,0 +1,73 @@
+-- Migration: Create transaction inclusions table
+-- Created: 2025-01-24
+-- Description: Records every block that includes a transaction, the chain block that
+--              accepted it through its mergeset, and whether the inclusion was the
+--              accepted one, a duplicate of it, or a conflicting nonce
+
+CREATE TABLE IF NOT EXISTS transaction_inclusions (
+    -- Primary Key
+    transaction_hash VARCHAR(66) NOT NULL,
+    block_hash VARCHAR(66) NOT NULL,
+    
+    -- Position of the transaction in the including block
+    block_number BIGINT NOT NULL,
+    transaction_index INTEGER NOT NULL,
+    
+    -- Chain block whose mergeset accepted the including block (the block itself for chain blocks)
+    accepting_block_hash VARCHAR(66),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    
+    PRIMARY KEY (transaction_hash, block_hash),
+    
+    -- Foreign Keys
+    CONSTRAINT fk_inclusions_transaction FOREIGN KEY (transaction_hash)
+        REFERENCES transactions(hash) ON DELETE CASCADE,
+    CONSTRAINT fk_inclusions_block FOREIGN KEY (block_hash)
+        REFERENCES blocks(hash) ON DELETE CASCADE,
+    
+    -- Constraints
+    CONSTRAINT chk_inclusion_hash_format CHECK (
+        transaction_hash ~ '^0x[0-9a-f]{64}$' AND
+        block_hash ~ '^0x[0-9a-f]{64}$' AND
+        (accepting_block_hash IS NULL OR accepting_block_hash ~ '^0x[0-9a-f]{64}$')
+    ),
+    CONSTRAINT chk_inclusion_status CHECK (status IN ('pending', 'accepted', 'duplicate', 'conflict')),
+    CONSTRAINT chk_inclusion_accepting_block CHECK (
+        (status = 'pending') = (accepting_block_hash IS NULL)
+    ),
+    CONSTRAINT chk_inclusion_index_positive CHECK (transaction_index >= 0)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_inclusions_block ON transaction_inclusions(block_hash, transaction_index);
+CREATE INDEX IF NOT EXISTS idx_inclusions_accepting_block ON transaction_inclusions(accepting_block_hash)
+    WHERE accepting_block_hash IS NOT NULL;
+
+-- Conflicting transactions are found by sender and nonce
+CREATE INDEX IF NOT EXISTS idx_transactions_sender_nonce ON transactions(from_address, nonce);
+
+-- Every stored transaction was included at least by the block it points to
+INSERT INTO transaction_inclusions (transaction_hash, block_hash, block_number, transaction_index)
+SELECT hash, block_hash, block_number, transaction_index
+FROM transactions
+ON CONFLICT (transaction_hash, block_hash) DO NOTHING;
+
+-- Settle the backfilled inclusions the way transactions.is_accepted was derived
+UPDATE transaction_inclusions i
+SET accepting_block_hash = a.hash,
+    status = 'accepted'
+FROM blocks b
+CROSS JOIN LATERAL (
+    SELECT c.hash
+    FROM blocks c
+    LEFT JOIN ghostdag_data g ON g.block_hash = c.hash
+    WHERE c.is_chain_block = true
+      AND (c.hash = b.hash OR b.hash = ANY(g.merge_set_blues))
+    ORDER BY c.blue_score, c.hash
+    LIMIT 1
+) a
+WHERE b.hash = i.block_hash;
@@ -0,0 +1,3 @@
+-- Rollback: Drop mergesets table
+DROP INDEX IF EXISTS idx_mergesets_merged_block;
+DROP TABLE IF EXISTS mergesets;
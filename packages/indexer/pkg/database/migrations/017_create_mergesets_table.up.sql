@@ -0,0 +1,45 @@
+-- Migration: Create mergesets table
+-- Created: 2025-01-24
+-- Description: Normalizes ghostdag_data.merge_set_blues/merge_set_reds into one row
+--              per merged block so explorers can list what a chain block merged
+--              and find the chain block that merged a given block
+
+CREATE TABLE IF NOT EXISTS mergesets (
+    -- Primary Key
+    merging_block_hash VARCHAR(66) NOT NULL,
+    merged_block_hash VARCHAR(66) NOT NULL,
+    
+    -- GHOSTDAG colouring and position in the merging block's mergeset (blues first)
+    is_blue BOOLEAN NOT NULL,
+    merge_order INTEGER NOT NULL,
+    
+    -- Timestamps
+    created_at TIMESTAMP DEFAULT NOW(),
+    
+    PRIMARY KEY (merging_block_hash, merged_block_hash),
+    
+    -- Merged blocks may be indexed after the block that merges them, so only
+    -- the merging block is required to exist
+    CONSTRAINT fk_mergesets_merging_block FOREIGN KEY (merging_block_hash)
+        REFERENCES blocks(hash) ON DELETE CASCADE,
+    
+    -- Constraints
+    CONSTRAINT chk_mergeset_hash_format CHECK (
+        merging_block_hash ~ '^0x[0-9a-f]{64}$' AND
+        merged_block_hash ~ '^0x[0-9a-f]{64}$'
+    ),
+    CONSTRAINT chk_mergeset_order_positive CHECK (merge_order >= 0)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_mergesets_merged_block ON mergesets(merged_block_hash);
+
+-- Backfill from the GHOSTDAG data indexed so far
+INSERT INTO mergesets (merging_block_hash, merged_block_hash, is_blue, merge_order)
+SELECT DISTINCT ON (g.block_hash, m.hash)
+    g.block_hash, m.hash, m.position <= cardinality(COALESCE(g.merge_set_blues, '{}')), m.position - 1
+FROM ghostdag_data g
+CROSS JOIN LATERAL unnest(COALESCE(g.merge_set_blues, '{}') || COALESCE(g.merge_set_reds, '{}'))
+    WITH ORDINALITY AS m(hash, position)
+ORDER BY g.block_hash, m.hash, m.position
+ON CONFLICT (merging_block_hash, merged_block_hash) DO NOTHING;
@@ -19,14 +19,14 @@ var migrationsFS embed.FS
 
 // Migrator handles database migrations
 type Migrator struct {
-	conn   *pgx.Conn
+	db     DBTX
 	logger *zap.Logger
 }
 
 // NewMigrator creates a new migrator instance
-func NewMigrator(conn *pgx.Conn, logger *zap.Logger) *Migrator {
+func NewMigrator(db DBTX, logger *zap.Logger) *Migrator {
 	return &Migrator{
-		conn:   conn,
+		db:     db,
 		logger: logger,
 	}
 }
@@ -152,13 +152,13 @@ func (m *Migrator) createMigrationsTable(ctx context.Context) error {
 		);
 	`
 
-	_, err := m.conn.Exec(ctx, query)
+	_, err := m.db.Exec(ctx, query)
 	return err
 }
 
 func (m *Migrator) getAppliedMigrations(ctx context.Context) (map[string]bool, error) {
 	query := `SELECT version FROM schema_migrations`
-	rows, err := m.conn.Query(ctx, query)
+	rows, err := m.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -178,7 +178,7 @@ func (m *Migrator) getAppliedMigrations(ctx context.Context) (map[string]bool, e
 
 func (m *Migrator) applyMigration(ctx context.Context, migration migrationFile) error {
 	// Begin transaction
-	tx, err := m.conn.Begin(ctx)
+	tx, err := m.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
@@ -209,7 +209,7 @@ func (m *Migrator) applyMigration(ctx context.Context, migration migrationFile)
 func (m *Migrator) Rollback(ctx context.Context) error {
 	// Get last applied migration
 	var lastMigration string
-	err := m.conn.QueryRow(ctx,
+	err := m.db.QueryRow(ctx,
 		`SELECT version FROM schema_migrations ORDER BY applied_at DESC LIMIT 1`,
 	).Scan(&lastMigration)
 	if err != nil {
@@ -239,7 +239,7 @@ func (m *Migrator) Rollback(ctx context.Context) error {
 	}
 
 	// Begin transaction
-	tx, err := m.conn.Begin(ctx)
+	tx, err := m.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
@@ -271,7 +271,7 @@ func (m *Migrator) Rollback(ctx context.Context) error {
 // GetVersion returns the current migration version
 func (m *Migrator) GetVersion(ctx context.Context) (string, error) {
 	var version string
-	err := m.conn.QueryRow(ctx,
+	err := m.db.QueryRow(ctx,
 		`SELECT version FROM schema_migrations ORDER BY applied_at DESC LIMIT 1`,
 	).Scan(&version)
 	if err != nil {
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var bigTen = big.NewInt(10)
+
+// numericFromBig converts an optional amount to a NUMERIC value; nil becomes NULL.
+// pgtype.Numeric is binary-encodable, so it works for both Exec and COPY.
+func numericFromBig(v *big.Int) pgtype.Numeric {
+	if v == nil {
+		return pgtype.Numeric{}
+	}
+	return pgtype.Numeric{Int: new(big.Int).Set(v), Valid: true}
+}
+
+// bigFromNumeric converts a scanned NUMERIC to an amount; NULL becomes nil.
+// Postgres strips trailing zeros, so 10^20 arrives as Int=1, Exp=20 and must be scaled back.
+func bigFromNumeric(n pgtype.Numeric) (*big.Int, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite || n.Int == nil {
+		return nil, fmt.Errorf("numeric is not a finite integer")
+	}
+
+	v := new(big.Int).Set(n.Int)
+	switch {
+	case n.Exp > 0:
+		v.Mul(v, new(big.Int).Exp(bigTen, big.NewInt(int64(n.Exp)), nil))
+	case n.Exp < 0:
+		remainder := new(big.Int)
+		v.DivMod(v, new(big.Int).Exp(bigTen, big.NewInt(int64(-n.Exp)), nil), remainder)
+		if remainder.Sign() != 0 {
+			return nil, fmt.Errorf("numeric has a fractional part")
+		}
+	}
+
+	return v, nil
+}
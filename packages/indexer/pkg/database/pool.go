@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// PoolConfig configures the connection pool shared by all repositories
+type PoolConfig struct {
+	URL               string
+	MaxConns          int32         // Upper bound on open connections; should cover indexer workers
+	MinConns          int32         // Connections kept open while idle
+	MaxConnLifetime   time.Duration // Connections are recycled after this age
+	MaxConnIdleTime   time.Duration // Idle connections above MinConns are closed after this
+	HealthCheckPeriod time.Duration // How often idle connections are checked
+}
+
+// DefaultPoolConfig returns pool settings suitable for the indexer
+func DefaultPoolConfig(url string) PoolConfig {
+	return PoolConfig{
+		URL:               url,
+		MaxConns:          20,
+		MinConns:          2,
+		MaxConnLifetime:   time.Hour,
+		MaxConnIdleTime:   30 * time.Minute,
+		HealthCheckPeriod: time.Minute,
+	}
+}
+
+// Validate checks the pool settings
+func (c PoolConfig) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("database URL is required")
+	}
+	if c.MaxConns < 1 {
+		return fmt.Errorf("max connections must be positive, got %d", c.MaxConns)
+	}
+	if c.MinConns < 0 || c.MinConns > c.MaxConns {
+		return fmt.Errorf("min connections must be between 0 and %d, got %d", c.MaxConns, c.MinConns)
+	}
+	return nil
+}
+
+// NewPool creates a connection pool. Connections are opened lazily;
+// call Ping to verify the database is reachable.
+func NewPool(ctx context.Context, cfg PoolConfig, logger *zap.Logger) (*pgxpool.Pool, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pool config: %w", err)
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse database URL: %w", err)
+	}
+
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MinConns = cfg.MinConns
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		logger.Error("failed to create connection pool", zap.Error(err))
+		return nil, fmt.Errorf("create pool: %w", err)
+	}
+
+	logger.Debug("connection pool created",
+		zap.Int32("maxConns", poolConfig.MaxConns),
+		zap.Int32("minConns", poolConfig.MinConns))
+
+	return pool, nil
+}
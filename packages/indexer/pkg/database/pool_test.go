@@ -0,0 +1,139 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+func setupPoolTestDB(t *testing.T, maxConns int32) (*pgxpool.Pool, func()) {
+	// Migrate and clean tables over a single connection first
+	conn, cleanupConn := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return nil, nil
+	}
+	cleanupConn()
+
+	cfg := database.DefaultPoolConfig(blockRepoTestDatabaseURL)
+	cfg.MaxConns = maxConns
+	cfg.MinConns = 0
+
+	pool, err := database.NewPool(context.Background(), cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	return pool, pool.Close
+}
+
+func TestPoolConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*database.PoolConfig)
+		wantErr bool
+	}{
+		{name: "defaults", modify: func(c *database.PoolConfig) {}},
+		{name: "missing URL", modify: func(c *database.PoolConfig) { c.URL = "" }, wantErr: true},
+		{name: "zero max connections", modify: func(c *database.PoolConfig) { c.MaxConns = 0 }, wantErr: true},
+		{name: "min above max", modify: func(c *database.PoolConfig) { c.MinConns = c.MaxConns + 1 }, wantErr: true},
+		{name: "negative min", modify: func(c *database.PoolConfig) { c.MinConns = -1 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := database.DefaultPoolConfig(blockRepoTestDatabaseURL)
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewPool_AppliesConfig(t *testing.T) {
+	cfg := database.PoolConfig{
+		URL:             blockRepoTestDatabaseURL,
+		MaxConns:        7,
+		MinConns:        0,
+		MaxConnLifetime: 10 * time.Minute,
+	}
+
+	// Connections are opened lazily, so this works without a database
+	pool, err := database.NewPool(context.Background(), cfg, nil)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	assert.Equal(t, int32(7), pool.Config().MaxConns)
+	assert.Equal(t, int32(0), pool.Config().MinConns)
+	assert.Equal(t, 10*time.Minute, pool.Config().MaxConnLifetime)
+}
+
+func TestNewPool_InvalidURL(t *testing.T) {
+	cfg := database.DefaultPoolConfig("://not-a-url")
+
+	_, err := database.NewPool(context.Background(), cfg, nil)
+	assert.Error(t, err)
+}
+
+func TestPool_ConcurrentRangeIndexing(t *testing.T) {
+	pool, cleanup := setupPoolTestDB(t, 4)
+	if pool == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	mockRPC := new(mocks.MockPhoenixClient)
+
+	const blockCount = 50
+	for i := int64(1); i <= blockCount; i++ {
+		hash := fmt.Sprintf("0x%064x", i)
+		mockRPC.On("GetBlockByNumber", ctx, big.NewInt(i), true).
+			Return(&interfaces.Block{
+				Hash:         hash,
+				Number:       i,
+				ParentHashes: []string{"0x" + strings.Repeat("f", 64)},
+				Timestamp:    time.Now().Unix(),
+				GasLimit:     21000,
+				Transactions: []interfaces.Transaction{
+					{
+						Hash: fmt.Sprintf("0x%064x", i+blockCount),
+						From: "0x" + strings.Repeat("d", 40),
+						Gas:  21000,
+					},
+				},
+			}, nil)
+	}
+
+	// More workers than connections: workers must queue for the pool, not share one
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:     mockRPC,
+		Store:   database.NewStore(pool, zap.NewNop()),
+		Workers: 10,
+	})
+
+	_, err := idx.IndexBlockRange(ctx, big.NewInt(1), big.NewInt(blockCount))
+	require.NoError(t, err)
+
+	var blocks, txs int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM blocks").Scan(&blocks))
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM transactions").Scan(&txs))
+	assert.Equal(t, blockCount, blocks)
+	assert.Equal(t, blockCount, txs)
+	mockRPC.AssertExpectations(t)
+}
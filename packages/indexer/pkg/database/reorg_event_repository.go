@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// ReorgNotifyChannel is the PostgreSQL NOTIFY channel reorg events are published on
+const ReorgNotifyChannel = "phoenix_reorg"
+
+// ReorgEventRepository implements the ReorgEventPublisher interface
+type ReorgEventRepository struct {
+	db     DBTX
+	logger *zap.Logger
+}
+
+// NewReorgEventRepository creates a new ReorgEventRepository
+func NewReorgEventRepository(db DBTX, logger *zap.Logger) *ReorgEventRepository {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &ReorgEventRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// reorgNotification is the NOTIFY payload; consumers load the full event by ID
+type reorgNotification struct {
+	ID                 int64       `json:"id"`
+	ForkPoint          domain.Hash `json:"forkPoint"`
+	ForkPointBlueScore uint64      `json:"forkPointBlueScore"`
+	RemovedCount       int         `json:"removedCount"`
+	AddedCount         int         `json:"addedCount"`
+}
+
+// PublishReorg stores a reorg event and notifies listeners on ReorgNotifyChannel
+func (r *ReorgEventRepository) PublishReorg(ctx context.Context, event *domain.ReorgEvent) error {
+	query := `
+		INSERT INTO reorg_events (
+			fork_point_hash, fork_point_blue_score, removed_hashes, added_hashes
+		) VALUES (
+			$1, $2, $3, $4
+		)
+		RETURNING id, detected_at
+	`
+
+	removed := event.Removed
+	if removed == nil {
+		removed = []domain.Hash{}
+	}
+
+	added := event.Added
+	if added == nil {
+		added = []domain.Hash{}
+	}
+
+	err := r.db.QueryRow(ctx, query,
+		event.ForkPoint,
+		event.ForkPointBlueScore,
+		removed,
+		added,
+	).Scan(&event.ID, &event.DetectedAt)
+
+	if err != nil {
+		r.logger.Error("failed to save reorg event",
+			zap.String("forkPoint", event.ForkPoint.String()),
+			zap.Error(err))
+		return fmt.Errorf("save reorg event: %w", err)
+	}
+
+	payload, err := json.Marshal(reorgNotification{
+		ID:                 event.ID,
+		ForkPoint:          event.ForkPoint,
+		ForkPointBlueScore: event.ForkPointBlueScore,
+		RemovedCount:       len(event.Removed),
+		AddedCount:         len(event.Added),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal reorg notification: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, `SELECT pg_notify($1, $2)`, ReorgNotifyChannel, string(payload)); err != nil {
+		r.logger.Error("failed to notify reorg event",
+			zap.Int64("id", event.ID),
+			zap.Error(err))
+		return fmt.Errorf("notify reorg event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package database_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestReorgEventRepository_PublishReorg(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	_, _ = conn.Exec(ctx, "TRUNCATE TABLE reorg_events")
+
+	// Listen on the notification channel from the same session
+	_, err := conn.Exec(ctx, "LISTEN "+database.ReorgNotifyChannel)
+	require.NoError(t, err)
+
+	repo := database.NewReorgEventRepository(conn, zap.NewNop())
+
+	event := &domain.ReorgEvent{
+		ForkPoint:          domain.Hash("0x" + strings.Repeat("a", 64)),
+		ForkPointBlueScore: 1000,
+		Removed:            []domain.Hash{domain.Hash("0x" + strings.Repeat("b", 64))},
+		Added:              []domain.Hash{domain.Hash("0x" + strings.Repeat("c", 64))},
+	}
+
+	err = repo.PublishReorg(ctx, event)
+	require.NoError(t, err)
+	assert.NotZero(t, event.ID)
+
+	var removed, added []string
+	err = conn.QueryRow(ctx,
+		"SELECT removed_hashes, added_hashes FROM reorg_events WHERE id = $1", event.ID,
+	).Scan(&removed, &added)
+	require.NoError(t, err)
+	assert.Equal(t, event.Removed, removed)
+	assert.Equal(t, event.Added, added)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	notification, err := conn.WaitForNotification(waitCtx)
+	require.NoError(t, err)
+	assert.Equal(t, database.ReorgNotifyChannel, notification.Channel)
+	assert.Contains(t, notification.Payload, event.ForkPoint)
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// Repairer fixes rows written by older indexer versions, which stored the block
+// number as the timestamp of transactions and logs and 0 as every transaction index
+type Repairer struct {
+	db     DBTX
+	logger *zap.Logger
+}
+
+// NewRepairer creates a new Repairer
+func NewRepairer(db DBTX, logger *zap.Logger) *Repairer {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Repairer{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// MaxBlockNumber returns the highest stored block number, or -1 when there are no blocks
+func (r *Repairer) MaxBlockNumber(ctx context.Context) (int64, error) {
+	var number int64
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(MAX(number), -1) FROM blocks`).Scan(&number)
+	if err != nil {
+		return 0, fmt.Errorf("get max block number: %w", err)
+	}
+	return number, nil
+}
+
+// BackfillTimestamps copies block timestamps onto the transactions and logs of blocks numbered from..to
+// and returns how many transaction and log rows changed
+func (r *Repairer) BackfillTimestamps(ctx context.Context, from, to int64) (int64, int64, error) {
+	txResult, err := r.db.Exec(ctx, `
+		UPDATE transactions t
+		SET timestamp = b.timestamp
+		FROM blocks b
+		WHERE t.block_hash = b.hash
+		  AND b.number BETWEEN $1 AND $2
+		  AND t.timestamp <> b.timestamp
+	`, from, to)
+	if err != nil {
+		r.logger.Error("failed to backfill transaction timestamps",
+			zap.Int64("from", from),
+			zap.Int64("to", to),
+			zap.Error(err))
+		return 0, 0, fmt.Errorf("backfill transaction timestamps: %w", err)
+	}
+
+	logResult, err := r.db.Exec(ctx, `
+		UPDATE event_logs l
+		SET timestamp = b.timestamp,
+		    block_number = b.number
+		FROM blocks b
+		WHERE l.block_hash = b.hash
+		  AND b.number BETWEEN $1 AND $2
+		  AND (l.timestamp <> b.timestamp OR l.block_number <> b.number)
+	`, from, to)
+	if err != nil {
+		r.logger.Error("failed to backfill log timestamps",
+			zap.Int64("from", from),
+			zap.Int64("to", to),
+			zap.Error(err))
+		return 0, 0, fmt.Errorf("backfill log timestamps: %w", err)
+	}
+
+	return txResult.RowsAffected(), logResult.RowsAffected(), nil
+}
+
+// GetBlocksWithUnorderedTransactions returns the hashes of blocks numbered from..to
+// that have several transactions stored at the same index
+func (r *Repairer) GetBlocksWithUnorderedTransactions(ctx context.Context, from, to int64) ([]domain.Hash, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT block_hash
+		FROM transactions
+		WHERE block_number BETWEEN $1 AND $2
+		GROUP BY block_hash
+		HAVING COUNT(*) > 1 AND MIN(transaction_index) = MAX(transaction_index)
+		ORDER BY MIN(block_number)
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get blocks with unordered transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []domain.Hash
+	for rows.Next() {
+		var hash domain.Hash
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("scan block hash: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// SetTransactionIndexes stores each transaction's position in a block, given the
+// block's transaction hashes in order, on both the transaction rows and the block's
+// transaction_inclusions rows in one statement, and returns how many rows changed
+func (r *Repairer) SetTransactionIndexes(ctx context.Context, blockHash domain.Hash, txHashes []domain.Hash) (int64, error) {
+	var changed int64
+	err := r.db.QueryRow(ctx, `
+		WITH positions AS (
+			SELECT o.hash, (o.position - 1)::INTEGER AS transaction_index
+			FROM unnest($2::TEXT[]) WITH ORDINALITY AS o(hash, position)
+		),
+		transactions_set AS (
+			UPDATE transactions t
+			SET transaction_index = p.transaction_index
+			FROM positions p
+			WHERE t.hash = p.hash
+			  AND t.block_hash = $1
+			  AND t.transaction_index <> p.transaction_index
+			RETURNING 1
+		),
+		inclusions_set AS (
+			UPDATE transaction_inclusions i
+			SET transaction_index = p.transaction_index
+			FROM positions p
+			WHERE i.transaction_hash = p.hash
+			  AND i.block_hash = $1
+			  AND i.transaction_index <> p.transaction_index
+			RETURNING 1
+		)
+		SELECT (SELECT COUNT(*) FROM transactions_set) + (SELECT COUNT(*) FROM inclusions_set)
+	`, blockHash, txHashes).Scan(&changed)
+	if err != nil {
+		r.logger.Error("failed to set transaction indexes",
+			zap.String("blockHash", blockHash.String()),
+			zap.Error(err))
+		return 0, fmt.Errorf("set transaction indexes: %w", err)
+	}
+
+	return changed, nil
+}
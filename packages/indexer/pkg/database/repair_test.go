@@ -0,0 +1,81 @@
+package database_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestRepairer_BackfillsTimestampsAndIndexes(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	block := &domain.Block{
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
+		Number:       100,
+		ParentHashes: []domain.Hash{},
+		Timestamp:    1706150400,
+	}
+	require.NoError(t, database.NewBlockRepository(conn, zap.NewNop()).SaveBlock(ctx, block))
+
+	// Rows as older versions wrote them: block number as timestamp, every index 0
+	txRepo := database.NewTransactionRepository(conn, zap.NewNop())
+	first, second := domain.Hash("0x"+strings.Repeat("b", 64)), domain.Hash("0x"+strings.Repeat("c", 64))
+	for _, hash := range []domain.Hash{first, second} {
+		require.NoError(t, txRepo.SaveTransaction(ctx, &domain.Transaction{
+			Hash:        hash,
+			BlockHash:   block.Hash,
+			BlockNumber: block.Number,
+			From:        domain.Address("0x" + strings.Repeat("d", 40)),
+			Timestamp:   block.Number,
+		}))
+	}
+
+	repairer := database.NewRepairer(conn, zap.NewNop())
+
+	maxNumber, err := repairer.MaxBlockNumber(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), maxNumber)
+
+	txs, _, err := repairer.BackfillTimestamps(ctx, 0, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), txs)
+
+	unordered, err := repairer.GetBlocksWithUnorderedTransactions(ctx, 0, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Hash{block.Hash}, unordered)
+
+	fixed, err := repairer.SetTransactionIndexes(ctx, block.Hash, []domain.Hash{second, first})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), fixed, "the second transaction already has index 0; the first's row and inclusion change")
+
+	saved, err := txRepo.GetTransactionByHash(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved.TransactionIndex)
+	assert.Equal(t, block.Timestamp, saved.Timestamp)
+
+	inclusions, err := txRepo.GetInclusionsByBlockHash(ctx, block.Hash)
+	require.NoError(t, err)
+	if assert.Len(t, inclusions, 2) {
+		assert.Equal(t, second, inclusions[0].TransactionHash)
+		assert.Equal(t, 0, inclusions[0].TransactionIndex)
+		assert.Equal(t, first, inclusions[1].TransactionHash)
+		assert.Equal(t, 1, inclusions[1].TransactionIndex)
+	}
+
+	unordered, err = repairer.GetBlocksWithUnorderedTransactions(ctx, 0, 100)
+	require.NoError(t, err)
+	assert.Empty(t, unordered)
+}
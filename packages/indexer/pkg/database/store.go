@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// DBTX is the query surface shared by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+// Repositories depend on it so the same code runs on a pool or inside a transaction.
+// Only a pool is safe for concurrent use; a single connection or transaction is not.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Store implements the UnitOfWork interface on top of a DBTX
+type Store struct {
+	db     DBTX
+	logger *zap.Logger
+}
+
+// NewStore creates a new Store.
+// Given a pool, each WithTx call runs on its own connection; given a transaction,
+// WithTx nests using savepoints.
+func NewStore(db DBTX, logger *zap.Logger) *Store {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Store{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Repos returns repositories that run each call in its own implicit transaction
+func (s *Store) Repos() interfaces.Repos {
+	return newRepos(s.db, s.logger)
+}
+
+// WithTx runs fn with repositories bound to a single database transaction.
+// The transaction commits if fn returns nil and rolls back otherwise, including on panic.
+func (s *Store) WithTx(ctx context.Context, fn func(repos interfaces.Repos) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(newRepos(tx, s.logger)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			s.logger.Error("failed to roll back transaction", zap.Error(rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error("failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// newRepos binds every repository to the same connection or transaction
+func newRepos(db DBTX, logger *zap.Logger) interfaces.Repos {
+	return interfaces.Repos{
+		Blocks:       &BlockRepository{db: db, logger: logger},
+		Transactions: &TransactionRepository{db: db, logger: logger},
+		Logs:         &LogRepository{db: db, logger: logger},
+		DAG:          &DAGRepository{db: db, logger: logger},
+		Addresses:    &AddressRepository{db: db, logger: logger},
+		Checkpoints:  &CheckpointRepository{db: db, logger: logger},
+		ReorgEvents:  &ReorgEventRepository{db: db, logger: logger},
+		Anomalies:    &DecodeAnomalyRepository{db: db, logger: logger},
+		Bulk:         &BulkWriter{db: db, logger: logger},
+		Nested:       &Store{db: db, logger: logger},
+	}
+}
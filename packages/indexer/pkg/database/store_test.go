@@ -0,0 +1,132 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/database"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+func storeTestBlock() *domain.Block {
+	return &domain.Block{
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
+		Number:       100,
+		ParentHashes: []domain.Hash{domain.Hash("0x" + strings.Repeat("b", 64))},
+		Timestamp:    time.Now().Unix(),
+		GasLimit:     21000,
+		GasUsed:      21000,
+		BlueScore:    1000,
+		IsChainBlock: true,
+		Transactions: []domain.Transaction{},
+	}
+}
+
+func TestStore_WithTx_Commit(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	store := database.NewStore(conn, zap.NewNop())
+	block := storeTestBlock()
+
+	err := store.WithTx(ctx, func(repos interfaces.Repos) error {
+		if err := repos.Blocks.SaveBlock(ctx, block); err != nil {
+			return err
+		}
+		return repos.Transactions.SaveTransaction(ctx, &domain.Transaction{
+			Hash:        domain.Hash("0x" + strings.Repeat("c", 64)),
+			BlockHash:   block.Hash,
+			BlockNumber: block.Number,
+			From:        domain.Address("0x" + strings.Repeat("d", 40)),
+			GasLimit:    21000,
+		})
+	})
+	require.NoError(t, err)
+
+	saved, err := store.Repos().Blocks.GetBlockByHash(ctx, block.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, block.Number, saved.Number)
+
+	txs, err := store.Repos().Transactions.GetTransactionsByBlockHash(ctx, block.Hash)
+	require.NoError(t, err)
+	assert.Len(t, txs, 1)
+}
+
+func TestStore_WithTx_Rollback(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	store := database.NewStore(conn, zap.NewNop())
+	block := storeTestBlock()
+	failure := errors.New("transaction save failed")
+
+	err := store.WithTx(ctx, func(repos interfaces.Repos) error {
+		if err := repos.Blocks.SaveBlock(ctx, block); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	// The block must not survive the rollback
+	_, err = store.Repos().Blocks.GetBlockByHash(ctx, block.Hash)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestStore_WithTx_NestedRollsBackToSavepoint(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	store := database.NewStore(conn, zap.NewNop())
+	block := storeTestBlock()
+
+	err := store.WithTx(ctx, func(repos interfaces.Repos) error {
+		if err := repos.Blocks.SaveBlock(ctx, block); err != nil {
+			return err
+		}
+
+		// A failed statement inside the savepoint must not poison the outer transaction
+		nestedErr := repos.Nested.WithTx(ctx, func(nested interfaces.Repos) error {
+			return nested.Transactions.SaveTransaction(ctx, &domain.Transaction{
+				Hash:        "not-a-hash",
+				BlockHash:   block.Hash,
+				BlockNumber: block.Number,
+				From:        domain.Address("0x" + strings.Repeat("d", 40)),
+				GasLimit:    21000,
+			})
+		})
+		require.Error(t, nestedErr)
+
+		gasUsed := uint64(42)
+		return repos.Blocks.UpdateBlock(ctx, block.Hash, domain.BlockUpdate{GasUsed: &gasUsed})
+	})
+	require.NoError(t, err)
+
+	saved, err := store.Repos().Blocks.GetBlockByHash(ctx, block.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), saved.GasUsed)
+
+	txs, err := store.Repos().Transactions.GetTransactionsByBlockHash(ctx, block.Hash)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// inclusionColumns are the columns read by GetInclusions*, in scan order
+const inclusionColumns = `
+		transaction_hash, block_hash, block_number, transaction_index,
+		accepting_block_hash, status, created_at, updated_at`
+
+// saveInclusion records that tx's block includes it. Inclusions start pending and
+// are settled by RecomputeTransactionInclusions once the chain accepts the block.
+func (r *TransactionRepository) saveInclusion(ctx context.Context, tx *domain.Transaction) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO transaction_inclusions (transaction_hash, block_hash, block_number, transaction_index)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (transaction_hash, block_hash) DO UPDATE SET
+			block_number = EXCLUDED.block_number,
+			transaction_index = EXCLUDED.transaction_index
+	`, tx.Hash, tx.BlockHash, tx.BlockNumber, tx.TransactionIndex)
+
+	return err
+}
+
+// GetInclusionsByTransactionHash retrieves every block that included a transaction,
+// the accepted inclusion first
+func (r *TransactionRepository) GetInclusionsByTransactionHash(ctx context.Context, hash domain.Hash) ([]*domain.TransactionInclusion, error) {
+	query := `SELECT ` + inclusionColumns + `
+		FROM transaction_inclusions
+		WHERE transaction_hash = $1
+		ORDER BY status <> 'accepted', block_number, block_hash
+	`
+
+	inclusions, err := r.queryInclusions(ctx, query, hash)
+	if err != nil {
+		r.logger.Error("failed to get inclusions by transaction hash",
+			zap.String("hash", hash.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get inclusions by transaction hash: %w", err)
+	}
+
+	return inclusions, nil
+}
+
+// GetInclusionsByBlockHash retrieves the inclusions of every transaction a block includes
+func (r *TransactionRepository) GetInclusionsByBlockHash(ctx context.Context, blockHash domain.Hash) ([]*domain.TransactionInclusion, error) {
+	query := `SELECT ` + inclusionColumns + `
+		FROM transaction_inclusions
+		WHERE block_hash = $1
+		ORDER BY transaction_index ASC
+	`
+
+	inclusions, err := r.queryInclusions(ctx, query, blockHash)
+	if err != nil {
+		r.logger.Error("failed to get inclusions by block hash",
+			zap.String("blockHash", blockHash.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get inclusions by block hash: %w", err)
+	}
+
+	return inclusions, nil
+}
+
+// queryInclusions runs a query selecting inclusionColumns
+func (r *TransactionRepository) queryInclusions(ctx context.Context, query string, args ...any) ([]*domain.TransactionInclusion, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var inclusions []*domain.TransactionInclusion
+	for rows.Next() {
+		var inclusion domain.TransactionInclusion
+		if err := rows.Scan(
+			&inclusion.TransactionHash,
+			&inclusion.BlockHash,
+			&inclusion.BlockNumber,
+			&inclusion.TransactionIndex,
+			&inclusion.AcceptingBlockHash,
+			&inclusion.Status,
+			&inclusion.CreatedAt,
+			&inclusion.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan inclusion: %w", err)
+		}
+		inclusions = append(inclusions, &inclusion)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate inclusions: %w", err)
+	}
+
+	return inclusions, nil
+}
+
+// RecomputeTransactionInclusions re-settles the inclusions touched by the given blocks
+// and by the blocks they merge, and returns how many inclusions changed.
+//
+// An inclusion's accepting block is the block itself if it is a chain block, otherwise
+// the earliest chain block that merges it as blue. Among the accepted inclusions of a
+// transaction the one with the earliest accepting block wins and the rest are duplicates.
+// When two transactions from one sender share a nonce, only the earlier one is accepted
+// and every inclusion of the other is a conflict. Inclusions without an accepting block
+// stay pending. Transactions sharing a sender and nonce are re-settled together.
+func (r *TransactionRepository) RecomputeTransactionInclusions(ctx context.Context, blockHashes []domain.Hash) (int64, error) {
+	if len(blockHashes) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		WITH touched_blocks AS (
+			SELECT hash FROM unnest($1::TEXT[]) AS hash
+			UNION
+			SELECT merged.hash
+			FROM ghostdag_data g
+			CROSS JOIN LATERAL unnest(g.merge_set_blues || g.merge_set_reds) AS merged(hash)
+			WHERE g.block_hash = ANY($1)
+		),
+		senders AS (
+			SELECT DISTINCT t.from_address, t.nonce
+			FROM transaction_inclusions i
+			JOIN transactions t ON t.hash = i.transaction_hash
+			WHERE i.block_hash IN (SELECT hash FROM touched_blocks)
+		),
+		candidates AS (
+			SELECT
+				i.transaction_hash, i.block_hash, t.from_address, t.nonce, b.blue_score,
+				a.hash AS accepting_block_hash, a.blue_score AS accepting_blue_score
+			FROM senders s
+			JOIN transactions t ON t.from_address = s.from_address AND t.nonce = s.nonce
+			JOIN transaction_inclusions i ON i.transaction_hash = t.hash
+			JOIN blocks b ON b.hash = i.block_hash
+			LEFT JOIN LATERAL (
+				SELECT c.hash, c.blue_score
+				FROM blocks c
+				LEFT JOIN ghostdag_data g ON g.block_hash = c.hash
+				WHERE c.is_chain_block = true
+				  AND (c.hash = b.hash OR b.hash = ANY(g.merge_set_blues))
+				ORDER BY c.blue_score, c.hash
+				LIMIT 1
+			) a ON true
+		),
+		winners AS (
+			SELECT DISTINCT ON (transaction_hash)
+				transaction_hash, block_hash, from_address, nonce, blue_score, accepting_blue_score
+			FROM candidates
+			WHERE accepting_block_hash IS NOT NULL
+			ORDER BY transaction_hash, accepting_blue_score, blue_score, block_hash
+		),
+		ranked AS (
+			SELECT transaction_hash, block_hash,
+				row_number() OVER (
+					PARTITION BY from_address, nonce
+					ORDER BY accepting_blue_score, blue_score, block_hash, transaction_hash
+				) AS sender_rank
+			FROM winners
+		),
+		settled AS (
+			SELECT c.transaction_hash, c.block_hash, c.accepting_block_hash,
+				CASE
+					WHEN c.accepting_block_hash IS NULL THEN 'pending'
+					WHEN w.sender_rank > 1 THEN 'conflict'
+					WHEN w.block_hash = c.block_hash THEN 'accepted'
+					ELSE 'duplicate'
+				END AS status
+			FROM candidates c
+			LEFT JOIN ranked w ON w.transaction_hash = c.transaction_hash
+		)
+		UPDATE transaction_inclusions i
+		SET accepting_block_hash = s.accepting_block_hash,
+			status = s.status,
+			updated_at = NOW()
+		FROM settled s
+		WHERE i.transaction_hash = s.transaction_hash
+		  AND i.block_hash = s.block_hash
+		  AND (i.status <> s.status OR i.accepting_block_hash IS DISTINCT FROM s.accepting_block_hash)
+	`
+
+	result, err := r.db.Exec(ctx, query, blockHashes)
+	if err != nil {
+		r.logger.Error("failed to recompute transaction inclusions",
+			zap.Int("blockCount", len(blockHashes)),
+			zap.Error(err))
+		return 0, fmt.Errorf("recompute transaction inclusions: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
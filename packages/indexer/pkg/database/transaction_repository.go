@@ -3,50 +3,239 @@ package database
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
 )
 
 // TransactionRepository implements TransactionReader and TransactionWriter interfaces
 type TransactionRepository struct {
-	conn   *pgx.Conn
+	db     DBTX
 	logger *zap.Logger
 }
 
 // NewTransactionRepository creates a new TransactionRepository
-func NewTransactionRepository(conn *pgx.Conn, logger *zap.Logger) *TransactionRepository {
+func NewTransactionRepository(db DBTX, logger *zap.Logger) *TransactionRepository {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 	return &TransactionRepository{
-		conn:   conn,
+		db:     db,
 		logger: logger,
 	}
 }
 
-// SaveTransaction saves a transaction to the database
+// transactionColumns are the columns read by scanTransaction, in scan order
+const transactionColumns = `
+		hash, block_hash, block_number, transaction_index,
+		from_address, to_address, value, input_data, nonce,
+		gas_limit, gas_price, gas_used, status,
+		creates_contract, contract_address, timestamp,
+		transaction_type, chain_id, max_fee_per_gas, max_priority_fee_per_gas,
+		effective_gas_price, max_fee_per_blob_gas, blob_versioned_hashes,
+		v, r, s, cumulative_gas_used, logs_bloom`
+
+// blockAccepted is the condition under which block b's transactions are accepted:
+// b is a chain block or a chain block merges it as blue
+const blockAccepted = `
+		b.is_chain_block OR EXISTS (
+			SELECT 1
+			FROM ghostdag_data g
+			JOIN blocks c ON c.hash = g.block_hash
+			WHERE c.is_chain_block = true
+			  AND b.hash = ANY(g.merge_set_blues)
+		)`
+
+// SaveTransaction saves a transaction, its access list and its inclusion in tx.BlockHash.
+// The transaction row keeps the block, index and timestamp it was first stored with;
+// every later block that includes it only adds an inclusion. A new row is accepted
+// if its block already is; RecomputeTransactionAcceptance keeps it current afterwards.
 func (r *TransactionRepository) SaveTransaction(ctx context.Context, tx *domain.Transaction) error {
 	query := `
-		INSERT INTO transactions (
-			hash, block_hash, block_number, transaction_index,
-			from_address, to_address, value, input_data, nonce,
-			gas_limit, gas_price, gas_used, status,
-			creates_contract, contract_address, timestamp
+		INSERT INTO transactions (` + transactionColumns + `,
+			is_accepted
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28,
+			EXISTS (SELECT 1 FROM blocks b WHERE b.hash = $2 AND (` + blockAccepted + `))
 		)
 		ON CONFLICT (hash) DO UPDATE SET
-			gas_used = EXCLUDED.gas_used,
-			status = EXCLUDED.status
+			value = EXCLUDED.value,
+			gas_price = EXCLUDED.gas_price,
+			gas_used = COALESCE(EXCLUDED.gas_used, transactions.gas_used),
+			status = COALESCE(EXCLUDED.status, transactions.status),
+			transaction_type = EXCLUDED.transaction_type,
+			chain_id = EXCLUDED.chain_id,
+			max_fee_per_gas = EXCLUDED.max_fee_per_gas,
+			max_priority_fee_per_gas = EXCLUDED.max_priority_fee_per_gas,
+			effective_gas_price = COALESCE(EXCLUDED.effective_gas_price, transactions.effective_gas_price),
+			max_fee_per_blob_gas = EXCLUDED.max_fee_per_blob_gas,
+			blob_versioned_hashes = EXCLUDED.blob_versioned_hashes,
+			v = EXCLUDED.v,
+			r = EXCLUDED.r,
+			s = EXCLUDED.s
 	`
 
-	var toAddress *string = tx.To
-	var gasPrice *string
-	if tx.GasPrice != nil {
-		gasPriceStr := fmt.Sprintf("%d", *tx.GasPrice)
-		gasPrice = &gasPriceStr
+	_, err := r.db.Exec(ctx, query, transactionArgs(tx)...)
+
+	if err != nil {
+		r.logger.Error("failed to save transaction",
+			zap.String("hash", tx.Hash.String()),
+			zap.Error(err))
+		return fmt.Errorf("save transaction: %w", err)
+	}
+
+	if err := r.saveAccessList(ctx, tx); err != nil {
+		r.logger.Error("failed to save access list",
+			zap.String("hash", tx.Hash.String()),
+			zap.Error(err))
+		return fmt.Errorf("save access list: %w", err)
+	}
+
+	if err := r.saveInclusion(ctx, tx); err != nil {
+		r.logger.Error("failed to save transaction inclusion",
+			zap.String("hash", tx.Hash.String()),
+			zap.String("blockHash", tx.BlockHash.String()),
+			zap.Error(err))
+		return fmt.Errorf("save transaction inclusion: %w", err)
+	}
+
+	return nil
+}
+
+// saveAccessList replaces the stored access list of a transaction in a single statement.
+// Storage keys are passed comma-joined because Postgres arrays cannot be ragged.
+func (r *TransactionRepository) saveAccessList(ctx context.Context, tx *domain.Transaction) error {
+	addresses, storageKeys := accessListArrays(tx.AccessList)
+
+	_, err := r.db.Exec(ctx, `
+		WITH stale AS (
+			DELETE FROM transaction_access_lists
+			WHERE transaction_hash = $1 AND entry_index >= cardinality($2::TEXT[])
+		)
+		INSERT INTO transaction_access_lists (transaction_hash, entry_index, address, storage_keys)
+		SELECT $1, e.position - 1, e.address, string_to_array(e.storage_keys, ',')
+		FROM unnest($2::TEXT[], $3::TEXT[]) WITH ORDINALITY AS e(address, storage_keys, position)
+		ON CONFLICT (transaction_hash, entry_index) DO UPDATE SET
+			address = EXCLUDED.address,
+			storage_keys = EXCLUDED.storage_keys
+	`, tx.Hash, addresses, storageKeys)
+
+	return err
+}
+
+// GetTransactionByHash retrieves a transaction by its hash
+func (r *TransactionRepository) GetTransactionByHash(ctx context.Context, hash domain.Hash) (*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE hash = $1
+	`
+
+	tx, err := scanTransaction(r.db.QueryRow(ctx, query, hash))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("transaction %w: %s", domain.ErrNotFound, hash)
+	}
+	if err != nil {
+		r.logger.Error("failed to get transaction by hash",
+			zap.String("hash", hash.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get transaction by hash: %w", err)
+	}
+
+	if err := r.loadAccessLists(ctx, []*domain.Transaction{tx}); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// GetTransactionsByBlockHash retrieves all transactions for a block
+func (r *TransactionRepository) GetTransactionsByBlockHash(ctx context.Context, blockHash domain.Hash) ([]*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE block_hash = $1
+		ORDER BY transaction_index ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, blockHash)
+	if err != nil {
+		r.logger.Error("failed to get transactions by block hash",
+			zap.String("blockHash", blockHash.String()),
+			zap.Error(err))
+		return nil, fmt.Errorf("get transactions by block hash: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan transaction: %w", err)
+		}
+		transactions = append(transactions, tx)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	if err := r.loadAccessLists(ctx, transactions); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// loadAccessLists fills in the access lists of the given transactions with one query
+func (r *TransactionRepository) loadAccessLists(ctx context.Context, txs []*domain.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	byHash := make(map[domain.Hash]*domain.Transaction, len(txs))
+	hashes := make([]domain.Hash, 0, len(txs))
+	for _, tx := range txs {
+		byHash[tx.Hash] = tx
+		hashes = append(hashes, tx.Hash)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT transaction_hash, address, storage_keys
+		FROM transaction_access_lists
+		WHERE transaction_hash = ANY($1)
+		ORDER BY transaction_hash, entry_index
+	`, hashes)
+	if err != nil {
+		return fmt.Errorf("get access lists: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash domain.Hash
+		var tuple domain.AccessTuple
+		if err := rows.Scan(&hash, &tuple.Address, &tuple.StorageKeys); err != nil {
+			return fmt.Errorf("scan access list entry: %w", err)
+		}
+		tx := byHash[hash]
+		tx.AccessList = append(tx.AccessList, tuple)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+
+	return nil
+}
+
+// transactionArgs encodes a transaction in transactionColumns order
+func transactionArgs(tx *domain.Transaction) []any {
+	value := tx.Value
+	if value == nil {
+		value = new(big.Int)
 	}
 
 	var gasUsed *int64
@@ -61,69 +250,61 @@ func (r *TransactionRepository) SaveTransaction(ctx context.Context, tx *domain.
 		status = &statusVal
 	}
 
-	var contractAddress *string = tx.ContractAddress
-
-	// Get timestamp from block (we'll need to query it)
-	// For now, use block number as placeholder - in production, fetch from block
-	timestamp := tx.BlockNumber
+	var cumulativeGasUsed *int64
+	if tx.CumulativeGasUsed != nil {
+		cumulativeGasUsedVal := int64(*tx.CumulativeGasUsed)
+		cumulativeGasUsed = &cumulativeGasUsedVal
+	}
 
-	_, err := r.conn.Exec(ctx, query,
+	return []any{
 		tx.Hash,
 		tx.BlockHash,
 		tx.BlockNumber,
-		tx.TransactionIndex,
+		int32(tx.TransactionIndex),
 		tx.From,
-		toAddress,
-		fmt.Sprintf("%d", tx.Value),
-		string(tx.Input),
-		tx.Nonce,
-		tx.GasLimit,
-		gasPrice,
+		tx.To,
+		numericFromBig(value),
+		hexFromBytes(tx.Input),
+		int64(tx.Nonce),
+		int64(tx.GasLimit),
+		numericFromBig(tx.GasPrice),
 		gasUsed,
 		status,
 		tx.CreatesContract,
-		contractAddress,
-		timestamp,
-	)
-
-	if err != nil {
-		r.logger.Error("failed to save transaction",
-			zap.String("hash", tx.Hash),
-			zap.Error(err))
-		return fmt.Errorf("save transaction: %w", err)
+		tx.ContractAddress,
+		tx.Timestamp,
+		int16(tx.Type),
+		numericFromBig(tx.ChainID),
+		numericFromBig(tx.MaxFeePerGas),
+		numericFromBig(tx.MaxPriorityFeePerGas),
+		numericFromBig(tx.EffectiveGasPrice),
+		numericFromBig(tx.MaxFeePerBlobGas),
+		tx.BlobVersionedHashes,
+		numericFromBig(tx.V),
+		nullString(tx.R),
+		nullString(tx.S),
+		cumulativeGasUsed,
+		nullString(tx.LogsBloom),
 	}
-
-	return nil
 }
 
-// GetTransactionByHash retrieves a transaction by its hash
-func (r *TransactionRepository) GetTransactionByHash(ctx context.Context, hash string) (*domain.Transaction, error) {
-	query := `
-		SELECT hash, block_hash, block_number, transaction_index,
-		       from_address, to_address, value, input_data, nonce,
-		       gas_limit, gas_price, gas_used, status,
-		       creates_contract, contract_address, timestamp
-		FROM transactions
-		WHERE hash = $1
-	`
-
+// scanTransaction reads a row selected with transactionColumns
+func scanTransaction(row pgx.Row) (*domain.Transaction, error) {
 	var tx domain.Transaction
-	var toAddress *string
-	var gasPrice *string
-	var gasUsed *int64
+	var value, gasPrice, chainID, maxFee, maxPriorityFee, effectiveGasPrice, maxBlobFee, v pgtype.Numeric
+	var gasUsed, cumulativeGasUsed *int64
 	var status *int16
-	var contractAddress *string
-	var inputData string
-	var timestamp int64
+	var txType *int16
+	var r, s, logsBloom, inputData *string
 
-	err := r.conn.QueryRow(ctx, query, hash).Scan(
+	err := row.Scan(
 		&tx.Hash,
 		&tx.BlockHash,
 		&tx.BlockNumber,
 		&tx.TransactionIndex,
 		&tx.From,
-		&toAddress,
-		&tx.Value,
+		&tx.To,
+		&value,
 		&inputData,
 		&tx.Nonce,
 		&tx.GasLimit,
@@ -131,28 +312,47 @@ func (r *TransactionRepository) GetTransactionByHash(ctx context.Context, hash s
 		&gasUsed,
 		&status,
 		&tx.CreatesContract,
-		&contractAddress,
-		&timestamp,
+		&tx.ContractAddress,
+		&tx.Timestamp,
+		&txType,
+		&chainID,
+		&maxFee,
+		&maxPriorityFee,
+		&effectiveGasPrice,
+		&maxBlobFee,
+		&tx.BlobVersionedHashes,
+		&v,
+		&r,
+		&s,
+		&cumulativeGasUsed,
+		&logsBloom,
 	)
-
-	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("transaction not found: %s", hash)
-	}
 	if err != nil {
-		r.logger.Error("failed to get transaction by hash",
-			zap.String("hash", hash),
-			zap.Error(err))
-		return nil, fmt.Errorf("get transaction by hash: %w", err)
+		return nil, err
 	}
 
 	// Parse optional fields
-	tx.To = toAddress
-	tx.Input = []byte(inputData)
+	if tx.Input, err = bytesFromHex(inputData); err != nil {
+		return nil, fmt.Errorf("parse input data: %w", err)
+	}
 
-	if gasPrice != nil {
-		var val uint64
-		fmt.Sscanf(*gasPrice, "%d", &val)
-		tx.GasPrice = &val
+	for _, amount := range []struct {
+		name string
+		src  pgtype.Numeric
+		dst  **big.Int
+	}{
+		{"value", value, &tx.Value},
+		{"gas price", gasPrice, &tx.GasPrice},
+		{"chain id", chainID, &tx.ChainID},
+		{"max fee per gas", maxFee, &tx.MaxFeePerGas},
+		{"max priority fee per gas", maxPriorityFee, &tx.MaxPriorityFeePerGas},
+		{"effective gas price", effectiveGasPrice, &tx.EffectiveGasPrice},
+		{"max fee per blob gas", maxBlobFee, &tx.MaxFeePerBlobGas},
+		{"v", v, &tx.V},
+	} {
+		if *amount.dst, err = bigFromNumeric(amount.src); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", amount.name, err)
+		}
 	}
 
 	if gasUsed != nil {
@@ -165,116 +365,157 @@ func (r *TransactionRepository) GetTransactionByHash(ctx context.Context, hash s
 		tx.Status = &val
 	}
 
-	tx.ContractAddress = contractAddress
-
-	return &tx, nil
-}
-
-// GetTransactionsByBlockHash retrieves all transactions for a block
-func (r *TransactionRepository) GetTransactionsByBlockHash(ctx context.Context, blockHash string) ([]*domain.Transaction, error) {
-	query := `
-		SELECT hash, block_hash, block_number, transaction_index,
-		       from_address, to_address, value, input_data, nonce,
-		       gas_limit, gas_price, gas_used, status,
-		       creates_contract, contract_address, timestamp
-		FROM transactions
-		WHERE block_hash = $1
-		ORDER BY transaction_index ASC
-	`
-
-	rows, err := r.conn.Query(ctx, query, blockHash)
-	if err != nil {
-		r.logger.Error("failed to get transactions by block hash",
-			zap.String("blockHash", blockHash),
-			zap.Error(err))
-		return nil, fmt.Errorf("get transactions by block hash: %w", err)
+	if txType != nil {
+		tx.Type = uint8(*txType)
 	}
-	defer rows.Close()
 
-	var transactions []*domain.Transaction
-	for rows.Next() {
-		var tx domain.Transaction
-		var toAddress *string
-		var gasPrice *string
-		var gasUsed *int64
-		var status *int16
-		var contractAddress *string
-		var inputData string
-		var timestamp int64
-
-		err := rows.Scan(
-			&tx.Hash,
-			&tx.BlockHash,
-			&tx.BlockNumber,
-			&tx.TransactionIndex,
-			&tx.From,
-			&toAddress,
-			&tx.Value,
-			&inputData,
-			&tx.Nonce,
-			&tx.GasLimit,
-			&gasPrice,
-			&gasUsed,
-			&status,
-			&tx.CreatesContract,
-			&contractAddress,
-			&timestamp,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("scan transaction: %w", err)
-		}
-
-		// Parse optional fields
-		tx.To = toAddress
-		tx.Input = []byte(inputData)
+	if cumulativeGasUsed != nil {
+		val := uint64(*cumulativeGasUsed)
+		tx.CumulativeGasUsed = &val
+	}
 
-		if gasPrice != nil {
-			var val uint64
-			fmt.Sscanf(*gasPrice, "%d", &val)
-			tx.GasPrice = &val
-		}
+	if r != nil {
+		tx.R = *r
+	}
+	if s != nil {
+		tx.S = *s
+	}
+	if logsBloom != nil {
+		tx.LogsBloom = *logsBloom
+	}
 
-		if gasUsed != nil {
-			val := uint64(*gasUsed)
-			tx.GasUsed = &val
-		}
+	return &tx, nil
+}
 
-		if status != nil {
-			val := int(*status)
-			tx.Status = &val
-		}
+// accessListArrays flattens an access list into parallel address and comma-joined storage key arrays
+func accessListArrays(accessList []domain.AccessTuple) ([]string, []string) {
+	addresses := make([]string, len(accessList))
+	storageKeys := make([]string, len(accessList))
+	for i, tuple := range accessList {
+		addresses[i] = tuple.Address.String()
+		storageKeys[i] = strings.Join(tuple.StorageKeys, ",")
+	}
+	return addresses, storageKeys
+}
 
-		tx.ContractAddress = contractAddress
+// UpdateTransaction sets the fields of a transaction that the update carries
+func (r *TransactionRepository) UpdateTransaction(ctx context.Context, hash domain.Hash, update domain.TransactionUpdate) error {
+	if update.IsEmpty() {
+		return nil
+	}
+	if err := update.Validate(); err != nil {
+		return fmt.Errorf("invalid transaction update: %w", err)
+	}
 
-		transactions = append(transactions, &tx)
+	var set assignments
+	if update.Status != nil {
+		set.set("status", int16(*update.Status))
+	}
+	if update.GasUsed != nil {
+		set.set("gas_used", int64(*update.GasUsed))
+	}
+	if update.CumulativeGasUsed != nil {
+		set.set("cumulative_gas_used", int64(*update.CumulativeGasUsed))
+	}
+	if update.EffectiveGasPrice != nil {
+		set.set("effective_gas_price", numericFromBig(update.EffectiveGasPrice))
+	}
+	if update.IsAccepted != nil {
+		set.set("is_accepted", *update.IsAccepted)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
+	query := fmt.Sprintf(`
+		UPDATE transactions
+		SET %s
+		WHERE hash = $%d
+	`, set.clause(), set.next())
+
+	_, err := r.db.Exec(ctx, query, append(set.args, hash)...)
+	if err != nil {
+		r.logger.Error("failed to update transaction",
+			zap.String("hash", hash.String()),
+			zap.Error(err))
+		return fmt.Errorf("update transaction: %w", err)
 	}
 
-	return transactions, nil
+	return nil
 }
 
 // UpdateTransactionStatus updates transaction status and gas used
-func (r *TransactionRepository) UpdateTransactionStatus(ctx context.Context, hash string, status int, gasUsed uint64) error {
+func (r *TransactionRepository) UpdateTransactionStatus(ctx context.Context, hash domain.Hash, status int, gasUsed uint64) error {
+	return r.UpdateTransaction(ctx, hash, domain.TransactionUpdate{
+		Status:  &status,
+		GasUsed: &gasUsed,
+	})
+}
+
+// SaveReceipt records a transaction's receipt fields in one statement.
+// An effective gas price missing from the receipt keeps the one computed at indexing.
+func (r *TransactionRepository) SaveReceipt(ctx context.Context, receipt *domain.Receipt) error {
 	query := `
 		UPDATE transactions
-		SET status = $1, gas_used = $2
-		WHERE hash = $3
+		SET status = $2,
+		    transaction_type = $3,
+		    gas_used = $4,
+		    cumulative_gas_used = $5,
+		    effective_gas_price = COALESCE($6, effective_gas_price),
+		    contract_address = $7,
+		    logs_bloom = $8
+		WHERE hash = $1
 	`
 
-	statusVal := int16(status)
-	gasUsedVal := int64(gasUsed)
-
-	_, err := r.conn.Exec(ctx, query, statusVal, gasUsedVal, hash)
+	_, err := r.db.Exec(ctx, query,
+		receipt.TransactionHash,
+		int16(receipt.Status),
+		int16(receipt.Type),
+		int64(receipt.GasUsed),
+		int64(receipt.CumulativeGasUsed),
+		numericFromBig(receipt.EffectiveGasPrice),
+		receipt.ContractAddress,
+		nullString(receipt.LogsBloom),
+	)
 	if err != nil {
-		r.logger.Error("failed to update transaction status",
-			zap.String("hash", hash),
+		r.logger.Error("failed to save receipt",
+			zap.String("hash", receipt.TransactionHash.String()),
 			zap.Error(err))
-		return fmt.Errorf("update transaction status: %w", err)
+		return fmt.Errorf("save receipt: %w", err)
 	}
 
 	return nil
 }
 
+// RecomputeTransactionAcceptance re-derives is_accepted for transactions included in the given
+// blocks and in the blocks they merge, whose acceptance follows the merging chain block.
+// A transaction is accepted when its block is a chain block or is merged as blue by a chain block.
+func (r *TransactionRepository) RecomputeTransactionAcceptance(ctx context.Context, blockHashes []domain.Hash) (int64, error) {
+	if len(blockHashes) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		WITH touched_blocks AS (
+			SELECT hash FROM unnest($1::TEXT[]) AS hash
+			UNION
+			SELECT merged.hash
+			FROM ghostdag_data g
+			CROSS JOIN LATERAL unnest(g.merge_set_blues || g.merge_set_reds) AS merged(hash)
+			WHERE g.block_hash = ANY($1)
+		)
+		UPDATE transactions t
+		SET is_accepted = (` + blockAccepted + `
+		)
+		FROM blocks b
+		WHERE t.block_hash = b.hash
+		  AND b.hash IN (SELECT hash FROM touched_blocks)
+	`
+
+	result, err := r.db.Exec(ctx, query, blockHashes)
+	if err != nil {
+		r.logger.Error("failed to recompute transaction acceptance",
+			zap.Int("blockCount", len(blockHashes)),
+			zap.Error(err))
+		return 0, fmt.Errorf("recompute transaction acceptance: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
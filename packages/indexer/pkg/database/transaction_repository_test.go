@@ -2,6 +2,7 @@ package database_test
 
 import (
 	"context"
+	"math/big"
 	"strings"
 	"testing"
 	"time"
@@ -25,9 +26,9 @@ func TestTransactionRepository_SaveTransaction(t *testing.T) {
 	// First, save a block (required for foreign key)
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -42,15 +43,15 @@ func TestTransactionRepository_SaveTransaction(t *testing.T) {
 	repo := database.NewTransactionRepository(conn, zap.NewNop())
 
 	tx := &domain.Transaction{
-		Hash:             "0x" + strings.Repeat("b", 64),
+		Hash:             domain.Hash("0x" + strings.Repeat("b", 64)),
 		BlockHash:        block.Hash,
 		BlockNumber:      block.Number,
 		TransactionIndex: 0,
-		From:             "0x" + strings.Repeat("c", 40),
-		To:               stringPtr("0x" + strings.Repeat("d", 40)),
-		Value:            1000000000000000000, // 1 ETH
+		From:             domain.Address("0x" + strings.Repeat("c", 40)),
+		To:               addressPtr(domain.Address("0x" + strings.Repeat("d", 40))),
+		Value:            big.NewInt(1000000000000000000), // 1 ETH
 		GasLimit:         21000,
-		GasPrice:         uintPtr(20000000000), // 20 Gwei
+		GasPrice:         big.NewInt(20000000000), // 20 Gwei
 		Nonce:            5,
 		Input:            []byte{0x01, 0x02, 0x03},
 		Status:           intPtr(1), // Success
@@ -68,6 +69,195 @@ func TestTransactionRepository_SaveTransaction(t *testing.T) {
 	assert.Equal(t, tx.From, saved.From)
 }
 
+func TestTransactionRepository_SaveTransaction_LargeAmounts(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// 10^24 wei is stored by Postgres as 1e24, which exercises exponent scaling on read
+	oneMillionCoins, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	aboveUint64, _ := new(big.Int).SetString("18446744073709551617", 10) // 2^64 + 1
+
+	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
+	block := &domain.Block{
+		Hash:          domain.Hash("0x" + strings.Repeat("a", 64)),
+		Number:        100,
+		ParentHashes:  []domain.Hash{},
+		Timestamp:     time.Now().Unix(),
+		BaseFeePerGas: aboveUint64,
+		IsChainBlock:  true,
+	}
+	require.NoError(t, blockRepo.SaveBlock(ctx, block))
+
+	savedBlock, err := blockRepo.GetBlockByHash(ctx, block.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, 0, aboveUint64.Cmp(savedBlock.BaseFeePerGas))
+
+	repo := database.NewTransactionRepository(conn, zap.NewNop())
+	tx := &domain.Transaction{
+		Hash:        domain.Hash("0x" + strings.Repeat("b", 64)),
+		BlockHash:   block.Hash,
+		BlockNumber: block.Number,
+		From:        domain.Address("0x" + strings.Repeat("c", 40)),
+		Value:       new(big.Int).SetUint64(aboveUint64.Uint64()), // Truncated, as older versions stored it
+		GasLimit:    21000,
+		GasPrice:    aboveUint64,
+	}
+	require.NoError(t, repo.SaveTransaction(ctx, tx))
+
+	// Saving again repairs the amount
+	tx.Value = oneMillionCoins
+	require.NoError(t, repo.SaveTransaction(ctx, tx))
+
+	saved, err := repo.GetTransactionByHash(ctx, tx.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, 0, oneMillionCoins.Cmp(saved.Value), "value = %s", saved.Value)
+	assert.Equal(t, 0, aboveUint64.Cmp(saved.GasPrice), "gas price = %s", saved.GasPrice)
+}
+
+func TestTransactionRepository_SaveTransaction_TypedFields(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	block := &domain.Block{
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
+		Number:       100,
+		ParentHashes: []domain.Hash{},
+		Timestamp:    time.Now().Unix(),
+	}
+	require.NoError(t, database.NewBlockRepository(conn, zap.NewNop()).SaveBlock(ctx, block))
+
+	first, second := domain.Address("0x"+strings.Repeat("1", 40)), domain.Address("0x"+strings.Repeat("2", 40))
+	slot := "0x" + strings.Repeat("0", 64)
+
+	repo := database.NewTransactionRepository(conn, zap.NewNop())
+	tx := &domain.Transaction{
+		Hash:                 domain.Hash("0x" + strings.Repeat("b", 64)),
+		BlockHash:            block.Hash,
+		BlockNumber:          block.Number,
+		From:                 domain.Address("0x" + strings.Repeat("c", 40)),
+		Value:                big.NewInt(0),
+		GasLimit:             21000,
+		Type:                 domain.TxTypeBlob,
+		ChainID:              big.NewInt(1440),
+		MaxFeePerGas:         big.NewInt(100),
+		MaxPriorityFeePerGas: big.NewInt(2),
+		EffectiveGasPrice:    big.NewInt(12),
+		MaxFeePerBlobGas:     big.NewInt(3),
+		BlobVersionedHashes:  []string{"0x01" + strings.Repeat("d", 62)},
+		AccessList: []domain.AccessTuple{
+			{Address: first, StorageKeys: []string{slot}},
+			{Address: second, StorageKeys: []string{}},
+		},
+		V: big.NewInt(2915), // EIP-155 legacy v for chain 1440
+		R: "0x" + strings.Repeat("e", 64),
+		S: "0x" + strings.Repeat("f", 64),
+	}
+	require.NoError(t, repo.SaveTransaction(ctx, tx))
+
+	saved, err := repo.GetTransactionByHash(ctx, tx.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TxTypeBlob, saved.Type)
+	assert.Equal(t, 0, tx.ChainID.Cmp(saved.ChainID))
+	assert.Equal(t, 0, tx.MaxFeePerGas.Cmp(saved.MaxFeePerGas))
+	assert.Equal(t, 0, tx.MaxPriorityFeePerGas.Cmp(saved.MaxPriorityFeePerGas))
+	assert.Equal(t, 0, tx.EffectiveGasPrice.Cmp(saved.EffectiveGasPrice))
+	assert.Equal(t, 0, tx.MaxFeePerBlobGas.Cmp(saved.MaxFeePerBlobGas))
+	assert.Equal(t, 0, tx.V.Cmp(saved.V))
+	assert.Equal(t, tx.R, saved.R)
+	assert.Equal(t, tx.S, saved.S)
+	assert.Equal(t, tx.BlobVersionedHashes, saved.BlobVersionedHashes)
+	assert.Equal(t, tx.AccessList, saved.AccessList)
+
+	// Saving a shorter access list drops the entries beyond it
+	tx.AccessList = tx.AccessList[:1]
+	require.NoError(t, repo.SaveTransaction(ctx, tx))
+
+	txs, err := repo.GetTransactionsByBlockHash(ctx, block.Hash)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, []domain.AccessTuple{{Address: first, StorageKeys: []string{slot}}}, txs[0].AccessList)
+}
+
+func TestTransactionRepository_SaveReceipt(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	block := &domain.Block{
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
+		Number:       100,
+		ParentHashes: []domain.Hash{},
+		Timestamp:    time.Now().Unix(),
+	}
+	require.NoError(t, database.NewBlockRepository(conn, zap.NewNop()).SaveBlock(ctx, block))
+
+	repo := database.NewTransactionRepository(conn, zap.NewNop())
+	tx := &domain.Transaction{
+		Hash:              domain.Hash("0x" + strings.Repeat("b", 64)),
+		BlockHash:         block.Hash,
+		BlockNumber:       block.Number,
+		From:              domain.Address("0x" + strings.Repeat("c", 40)),
+		Value:             big.NewInt(0),
+		GasLimit:          100000,
+		CreatesContract:   true,
+		EffectiveGasPrice: big.NewInt(12),
+	}
+	require.NoError(t, repo.SaveTransaction(ctx, tx))
+
+	contract := domain.Address("0x" + strings.Repeat("d", 40))
+	bloom := "0x" + strings.Repeat("0", 512)
+	require.NoError(t, repo.SaveReceipt(ctx, &domain.Receipt{
+		TransactionHash:   tx.Hash,
+		Status:            1,
+		Type:              domain.TxTypeDynamicFee,
+		GasUsed:           30000,
+		CumulativeGasUsed: 50000,
+		ContractAddress:   &contract,
+		LogsBloom:         bloom,
+	}))
+
+	saved, err := repo.GetTransactionByHash(ctx, tx.Hash)
+	require.NoError(t, err)
+	require.NotNil(t, saved.Status)
+	assert.Equal(t, 1, *saved.Status)
+	assert.Equal(t, domain.TxTypeDynamicFee, saved.Type)
+	require.NotNil(t, saved.GasUsed)
+	assert.Equal(t, uint64(30000), *saved.GasUsed)
+	require.NotNil(t, saved.CumulativeGasUsed)
+	assert.Equal(t, uint64(50000), *saved.CumulativeGasUsed)
+	require.NotNil(t, saved.ContractAddress)
+	assert.Equal(t, contract, *saved.ContractAddress)
+	assert.Equal(t, bloom, saved.LogsBloom)
+	assert.Equal(t, 0, big.NewInt(12).Cmp(saved.EffectiveGasPrice), "a receipt without a price keeps the computed one")
+
+	// Re-indexing the block without receipts keeps the receipt fields
+	require.NoError(t, repo.SaveTransaction(ctx, tx))
+	require.NoError(t, database.NewBulkWriter(conn, zap.NewNop()).WriteBatch(ctx, []*domain.Block{
+		{Hash: block.Hash, Number: block.Number, Timestamp: block.Timestamp, Transactions: []domain.Transaction{*tx}},
+	}))
+
+	saved, err = repo.GetTransactionByHash(ctx, tx.Hash)
+	require.NoError(t, err)
+	require.NotNil(t, saved.Status)
+	assert.Equal(t, 1, *saved.Status)
+	require.NotNil(t, saved.GasUsed)
+	assert.Equal(t, uint64(30000), *saved.GasUsed)
+}
+
 func TestTransactionRepository_GetTransactionByHash(t *testing.T) {
 	conn, cleanup := setupBlockRepoTestDB(t)
 	if conn == nil {
@@ -80,9 +270,9 @@ func TestTransactionRepository_GetTransactionByHash(t *testing.T) {
 	// Setup block
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -96,13 +286,13 @@ func TestTransactionRepository_GetTransactionByHash(t *testing.T) {
 	repo := database.NewTransactionRepository(conn, zap.NewNop())
 
 	tx := &domain.Transaction{
-		Hash:             "0x" + strings.Repeat("b", 64),
+		Hash:             domain.Hash("0x" + strings.Repeat("b", 64)),
 		BlockHash:        block.Hash,
 		BlockNumber:      block.Number,
 		TransactionIndex: 0,
-		From:             "0x" + strings.Repeat("c", 40),
-		To:               stringPtr("0x" + strings.Repeat("d", 40)),
-		Value:            1000000000000000000,
+		From:             domain.Address("0x" + strings.Repeat("c", 40)),
+		To:               addressPtr(domain.Address("0x" + strings.Repeat("d", 40))),
+		Value:            big.NewInt(1000000000000000000),
 		GasLimit:         21000,
 		Nonce:            5,
 		Input:            []byte{},
@@ -120,7 +310,7 @@ func TestTransactionRepository_GetTransactionByHash(t *testing.T) {
 	assert.Equal(t, tx.From, saved.From)
 
 	// Test non-existent transaction
-	_, err = repo.GetTransactionByHash(ctx, "0x"+strings.Repeat("z", 64))
+	_, err = repo.GetTransactionByHash(ctx, domain.Hash("0x"+strings.Repeat("z", 64)))
 	assert.Error(t, err)
 }
 
@@ -136,9 +326,9 @@ func TestTransactionRepository_GetTransactionsByBlockHash(t *testing.T) {
 	// Setup block
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -154,13 +344,13 @@ func TestTransactionRepository_GetTransactionsByBlockHash(t *testing.T) {
 	// Save multiple transactions
 	for i := 0; i < 3; i++ {
 		tx := &domain.Transaction{
-			Hash:             "0x" + strings.Repeat(string(rune('b'+i)), 64),
+			Hash:             domain.Hash("0x" + strings.Repeat(string(rune('b'+i)), 64)),
 			BlockHash:        block.Hash,
 			BlockNumber:      block.Number,
 			TransactionIndex: i,
-			From:             "0x" + strings.Repeat("c", 40),
-			To:               stringPtr("0x" + strings.Repeat("d", 40)),
-			Value:            1000000000000000000,
+			From:             domain.Address("0x" + strings.Repeat("c", 40)),
+			To:               addressPtr(domain.Address("0x" + strings.Repeat("d", 40))),
+			Value:            big.NewInt(1000000000000000000),
 			GasLimit:         21000,
 			Nonce:            uint64(i),
 			Input:            []byte{},
@@ -189,9 +379,9 @@ func TestTransactionRepository_UpdateTransactionStatus(t *testing.T) {
 	// Setup block
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -205,13 +395,13 @@ func TestTransactionRepository_UpdateTransactionStatus(t *testing.T) {
 	repo := database.NewTransactionRepository(conn, zap.NewNop())
 
 	tx := &domain.Transaction{
-		Hash:             "0x" + strings.Repeat("b", 64),
+		Hash:             domain.Hash("0x" + strings.Repeat("b", 64)),
 		BlockHash:        block.Hash,
 		BlockNumber:      block.Number,
 		TransactionIndex: 0,
-		From:             "0x" + strings.Repeat("c", 40),
-		To:               stringPtr("0x" + strings.Repeat("d", 40)),
-		Value:            1000000000000000000,
+		From:             domain.Address("0x" + strings.Repeat("c", 40)),
+		To:               addressPtr(domain.Address("0x" + strings.Repeat("d", 40))),
+		Value:            big.NewInt(1000000000000000000),
 		GasLimit:         21000,
 		Nonce:            5,
 		Input:            []byte{},
@@ -247,9 +437,9 @@ func TestTransactionRepository_ContractCreation(t *testing.T) {
 	// Setup block
 	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
 	block := &domain.Block{
-		Hash:         "0x" + strings.Repeat("a", 64),
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
 		Number:      100,
-		ParentHashes: []string{},
+		ParentHashes: []domain.Hash{},
 		Timestamp:   time.Now().Unix(),
 		GasLimit:    21000,
 		GasUsed:     21000,
@@ -264,19 +454,19 @@ func TestTransactionRepository_ContractCreation(t *testing.T) {
 
 	// Contract creation transaction (To is nil)
 	tx := &domain.Transaction{
-		Hash:             "0x" + strings.Repeat("b", 64),
+		Hash:             domain.Hash("0x" + strings.Repeat("b", 64)),
 		BlockHash:        block.Hash,
 		BlockNumber:      block.Number,
 		TransactionIndex: 0,
-		From:             "0x" + strings.Repeat("c", 40),
+		From:             domain.Address("0x" + strings.Repeat("c", 40)),
 		To:               nil, // Contract creation
-		Value:            0,
+		Value:            big.NewInt(0),
 		GasLimit:         500000,
 		Nonce:            5,
 		Input:            []byte{0x60, 0x60, 0x60}, // Contract bytecode
 		Status:           intPtr(1),
 		CreatesContract:  true,
-		ContractAddress:  stringPtr("0x" + strings.Repeat("e", 40)),
+		ContractAddress:  addressPtr(domain.Address("0x" + strings.Repeat("e", 40))),
 	}
 
 	err = repo.SaveTransaction(ctx, tx)
@@ -290,8 +480,213 @@ func TestTransactionRepository_ContractCreation(t *testing.T) {
 	assert.NotNil(t, saved.ContractAddress)
 }
 
-func stringPtr(s string) *string {
-	return &s
+func TestTransactionRepository_RecomputeTransactionAcceptance(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Setup a block that has left the chain
+	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
+	block := &domain.Block{
+		Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
+		Number:       100,
+		ParentHashes: []domain.Hash{},
+		Timestamp:    time.Now().Unix(),
+		BlueScore:    1000,
+		IsChainBlock: false,
+	}
+	err := blockRepo.SaveBlock(ctx, block)
+	require.NoError(t, err)
+
+	repo := database.NewTransactionRepository(conn, zap.NewNop())
+	tx := &domain.Transaction{
+		Hash:        domain.Hash("0x" + strings.Repeat("b", 64)),
+		BlockHash:   block.Hash,
+		BlockNumber: block.Number,
+		From:        domain.Address("0x" + strings.Repeat("c", 40)),
+		GasLimit:    21000,
+	}
+	err = repo.SaveTransaction(ctx, tx)
+	require.NoError(t, err)
+
+	updated, err := repo.RecomputeTransactionAcceptance(ctx, []domain.Hash{block.Hash})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	var accepted bool
+	err = conn.QueryRow(ctx, "SELECT is_accepted FROM transactions WHERE hash = $1", tx.Hash).Scan(&accepted)
+	require.NoError(t, err)
+	assert.False(t, accepted, "transactions of non-chain, unmerged blocks are not accepted")
+
+	// Nothing to do for an empty block list
+	updated, err = repo.RecomputeTransactionAcceptance(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), updated)
+}
+
+func TestTransactionRepository_RecomputeTransactionAcceptance_MergedBlocks(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Chain block C merges block A as blue; block D is merged by nothing
+	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
+	blockA := &domain.Block{Hash: domain.Hash("0x" + strings.Repeat("a", 64)), Number: 100, Timestamp: time.Now().Unix(), BlueScore: 10}
+	blockC := &domain.Block{Hash: domain.Hash("0x" + strings.Repeat("c", 64)), Number: 101, Timestamp: time.Now().Unix(), BlueScore: 11, IsChainBlock: true}
+	blockD := &domain.Block{Hash: domain.Hash("0x" + strings.Repeat("d", 64)), Number: 101, Timestamp: time.Now().Unix(), BlueScore: 11}
+	for _, block := range []*domain.Block{blockA, blockC, blockD} {
+		require.NoError(t, blockRepo.SaveBlock(ctx, block))
+	}
+
+	dagRepo := database.NewDAGRepository(conn, zap.NewNop())
+	require.NoError(t, dagRepo.SaveGHOSTDAGData(ctx, blockC.Hash, &domain.GHOSTDAGData{
+		BlockHash:          blockC.Hash,
+		BlueScore:          11,
+		BlueWork:           big.NewInt(11),
+		SelectedParent:     blockA.Hash,
+		MergeSetBlues:      []domain.Hash{blockA.Hash},
+		BluesAnticoneSizes: []int{0},
+	}))
+
+	repo := database.NewTransactionRepository(conn, zap.NewNop())
+	merged := &domain.Transaction{
+		Hash:        domain.Hash("0x" + strings.Repeat("e", 64)),
+		BlockHash:   blockA.Hash,
+		BlockNumber: blockA.Number,
+		From:        domain.Address("0x" + strings.Repeat("1", 40)),
+		GasLimit:    21000,
+	}
+	unmerged := &domain.Transaction{
+		Hash:        domain.Hash("0x" + strings.Repeat("f", 64)),
+		BlockHash:   blockD.Hash,
+		BlockNumber: blockD.Number,
+		From:        domain.Address("0x" + strings.Repeat("2", 40)),
+		GasLimit:    21000,
+	}
+	require.NoError(t, repo.SaveTransaction(ctx, merged))
+	require.NoError(t, repo.SaveTransaction(ctx, unmerged))
+
+	isAccepted := func(hash domain.Hash) bool {
+		var accepted bool
+		require.NoError(t, conn.QueryRow(ctx, "SELECT is_accepted FROM transactions WHERE hash = $1", hash).Scan(&accepted))
+		return accepted
+	}
+	assert.True(t, isAccepted(merged.Hash), "a transaction in a block merged as blue is accepted when saved")
+	assert.False(t, isAccepted(unmerged.Hash), "a transaction in an unmerged block is not accepted when saved")
+
+	// A reorg takes C off the chain; only C is an affected chain block
+	leftChain := false
+	require.NoError(t, blockRepo.UpdateBlock(ctx, blockC.Hash, domain.BlockUpdate{IsChainBlock: &leftChain}))
+
+	updated, err := repo.RecomputeTransactionAcceptance(ctx, []domain.Hash{blockC.Hash})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated, "the merged block's transaction is recomputed")
+	assert.False(t, isAccepted(merged.Hash), "C no longer accepts the blocks it merged")
+}
+
+func TestTransactionRepository_RecomputeTransactionInclusions(t *testing.T) {
+	conn, cleanup := setupBlockRepoTestDB(t)
+	if conn == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Parallel blocks A and B are both merged as blue by chain block C
+	blockRepo := database.NewBlockRepository(conn, zap.NewNop())
+	blockA := &domain.Block{Hash: domain.Hash("0x" + strings.Repeat("a", 64)), Number: 100, Timestamp: time.Now().Unix(), BlueScore: 10}
+	blockB := &domain.Block{Hash: domain.Hash("0x" + strings.Repeat("b", 64)), Number: 100, Timestamp: time.Now().Unix(), BlueScore: 10}
+	blockC := &domain.Block{Hash: domain.Hash("0x" + strings.Repeat("c", 64)), Number: 101, Timestamp: time.Now().Unix(), BlueScore: 11, IsChainBlock: true}
+	for _, block := range []*domain.Block{blockA, blockB, blockC} {
+		require.NoError(t, blockRepo.SaveBlock(ctx, block))
+	}
+
+	dagRepo := database.NewDAGRepository(conn, zap.NewNop())
+	require.NoError(t, dagRepo.SaveGHOSTDAGData(ctx, blockC.Hash, &domain.GHOSTDAGData{
+		BlockHash:          blockC.Hash,
+		BlueScore:          11,
+		BlueWork:           big.NewInt(11),
+		SelectedParent:     blockA.Hash,
+		MergeSetBlues:      []domain.Hash{blockA.Hash, blockB.Hash},
+		BluesAnticoneSizes: []int{0, 1},
+	}))
+
+	// Both blocks include the same transaction; B also includes a replacement with the same nonce
+	repo := database.NewTransactionRepository(conn, zap.NewNop())
+	sender := domain.Address("0x" + strings.Repeat("d", 40))
+	tx := domain.Transaction{
+		Hash:        domain.Hash("0x" + strings.Repeat("e", 64)),
+		BlockHash:   blockA.Hash,
+		BlockNumber: blockA.Number,
+		From:        sender,
+		Nonce:       3,
+		GasLimit:    21000,
+		Timestamp:   blockA.Timestamp,
+	}
+	require.NoError(t, repo.SaveTransaction(ctx, &tx))
+
+	again := tx
+	again.BlockHash = blockB.Hash
+	again.TransactionIndex = 4
+	again.Timestamp = tx.Timestamp + 60
+	require.NoError(t, repo.SaveTransaction(ctx, &again))
+
+	replacement := tx
+	replacement.Hash = domain.Hash("0x" + strings.Repeat("f", 64))
+	replacement.BlockHash = blockB.Hash
+	replacement.TransactionIndex = 1
+	require.NoError(t, repo.SaveTransaction(ctx, &replacement))
+
+	// Inclusions stay pending until the chain block is processed
+	pending, err := repo.GetInclusionsByBlockHash(ctx, blockB.Hash)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, domain.InclusionPending, pending[0].Status)
+	assert.Nil(t, pending[0].AcceptingBlockHash)
+
+	updated, err := repo.RecomputeTransactionInclusions(ctx, []domain.Hash{blockC.Hash})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated)
+
+	inclusions, err := repo.GetInclusionsByTransactionHash(ctx, tx.Hash)
+	require.NoError(t, err)
+	require.Len(t, inclusions, 2)
+	assert.Equal(t, blockA.Hash, inclusions[0].BlockHash)
+	assert.Equal(t, domain.InclusionAccepted, inclusions[0].Status)
+	require.NotNil(t, inclusions[0].AcceptingBlockHash)
+	assert.Equal(t, blockC.Hash, *inclusions[0].AcceptingBlockHash)
+	assert.Equal(t, blockB.Hash, inclusions[1].BlockHash)
+	assert.Equal(t, domain.InclusionDuplicate, inclusions[1].Status)
+
+	conflicting, err := repo.GetInclusionsByTransactionHash(ctx, replacement.Hash)
+	require.NoError(t, err)
+	require.Len(t, conflicting, 1)
+	assert.Equal(t, domain.InclusionConflict, conflicting[0].Status)
+
+	// The transaction row keeps the block it was first stored with
+	saved, err := repo.GetTransactionByHash(ctx, tx.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, blockA.Hash, saved.BlockHash)
+	assert.Equal(t, tx.TransactionIndex, saved.TransactionIndex)
+	assert.Equal(t, tx.Timestamp, saved.Timestamp)
+
+	// Settled inclusions are not rewritten
+	updated, err = repo.RecomputeTransactionInclusions(ctx, []domain.Hash{blockA.Hash, blockB.Hash})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), updated)
+}
+
+func addressPtr(a domain.Address) *domain.Address {
+	return &a
 }
 
 func intPtr(i int) *int {
@@ -0,0 +1,29 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// assignments builds the SET clause of a partial update. Columns are named by the
+// repositories, never by callers, and added in a fixed order so statements are stable.
+type assignments struct {
+	columns []string
+	args    []any
+}
+
+// set assigns value to column through the next placeholder
+func (a *assignments) set(column string, value any) {
+	a.args = append(a.args, value)
+	a.columns = append(a.columns, fmt.Sprintf("%s = $%d", column, len(a.args)))
+}
+
+// clause returns the SET clause, followed by assignments that take no argument
+func (a *assignments) clause(fixed ...string) string {
+	return strings.Join(append(a.columns, fixed...), ", ")
+}
+
+// next returns the placeholder that follows the assigned values, for the WHERE clause
+func (a *assignments) next() int {
+	return len(a.args) + 1
+}
@@ -1,10 +1,13 @@
 package domain
 
-import "math/big"
+import (
+	"errors"
+	"math/big"
+)
 
-// Address represents an address in the Phoenix network
-type Address struct {
-	Address         string
+// Account represents the state of an address in the Phoenix network
+type Account struct {
+	Address         Address
 	Balance         *big.Int
 	Nonce           uint64
 	IsContract      bool
@@ -13,12 +16,36 @@ type Address struct {
 }
 
 // HasBalance returns true if address has a positive balance
-func (a *Address) HasBalance() bool {
+func (a *Account) HasBalance() bool {
 	return a.Balance != nil && a.Balance.Sign() > 0
 }
 
 // IsZero returns true if address is the zero address
-func (a *Address) IsZero() bool {
-	return a.Address == "0x0000000000000000000000000000000000000000"
+func (a *Account) IsZero() bool {
+	return a.Address == ZeroAddress
 }
 
+
+// AccountUpdate is a partial update of an address's account. Nil fields are left unchanged.
+type AccountUpdate struct {
+	Balance          *big.Int
+	Nonce            *uint64
+	IsContract       *bool
+	TransactionCount *int64
+}
+
+// IsEmpty returns true if the update changes no field
+func (u AccountUpdate) IsEmpty() bool {
+	return u.Balance == nil && u.Nonce == nil && u.IsContract == nil && u.TransactionCount == nil
+}
+
+// Validate validates the fields the update sets
+func (u AccountUpdate) Validate() error {
+	if u.Balance != nil && u.Balance.Sign() < 0 {
+		return errors.New("balance cannot be negative")
+	}
+	if u.TransactionCount != nil && *u.TransactionCount < 0 {
+		return errors.New("transaction count cannot be negative")
+	}
+	return nil
+}
@@ -1,29 +1,51 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"math/big"
+)
 
 // Block represents a block in the Phoenix BlockDAG
 type Block struct {
-	Hash             string
+	Hash             Hash
 	Number           int64
-	ParentHashes     []string
+	ParentHashes     []Hash
 	Timestamp        int64
-	Miner            string
+	Miner            Address // Empty when the node does not report it
 	GasLimit         uint64
 	GasUsed          uint64
-	BaseFeePerGas    *uint64
+	BaseFeePerGas    *big.Int // Wei, nil before EIP-1559
 	BlueScore        uint64
 	IsChainBlock     bool
-	SelectedParent   string
+	SelectedParent   Hash
 	TransactionsRoot string
 	StateRoot        string
 	ReceiptsRoot     string
+	Size             uint64 // Bytes
+	Difficulty       *big.Int
+	Nonce            string // 0x-prefixed, 8 bytes
+	ExtraData        []byte
+	LogsBloom        string
+	MixHash          string
+	BlueWork         *big.Int // Accumulated blue work of the block's past
+	DAAScore         uint64   // Difficulty adjustment score
+	PruningPoint     Hash     // Hash of the pruning point the block commits to
 	Transactions     []Transaction
+	TransactionCount int // Transactions the node listed; len(Transactions) when lower
+}
+
+// CountTransactions returns the number of transactions in the block, including any
+// the indexer could not decode
+func (b *Block) CountTransactions() int {
+	if b.TransactionCount > len(b.Transactions) {
+		return b.TransactionCount
+	}
+	return len(b.Transactions)
 }
 
 // Validate validates the block structure
 func (b *Block) Validate() error {
-	if !hashRegex.MatchString(b.Hash) {
+	if !b.Hash.IsValid() {
 		return errors.New("invalid block hash format")
 	}
 
@@ -37,11 +59,27 @@ func (b *Block) Validate() error {
 
 	// Validate parent hashes
 	for _, parent := range b.ParentHashes {
-		if !hashRegex.MatchString(parent) {
+		if !parent.IsValid() {
 			return errors.New("invalid parent hash format")
 		}
 	}
 
+	if b.Miner != "" && !b.Miner.IsValid() {
+		return errors.New("invalid miner address format")
+	}
+
+	if b.SelectedParent != "" && !b.SelectedParent.IsValid() {
+		return errors.New("invalid selected parent hash format")
+	}
+
+	if b.PruningPoint != "" && !b.PruningPoint.IsValid() {
+		return errors.New("invalid pruning point hash format")
+	}
+
+	if b.LogsBloom != "" && !bloomRegex.MatchString(b.LogsBloom) {
+		return errors.New("invalid logs bloom format")
+	}
+
 	return nil
 }
 
@@ -55,3 +93,33 @@ func (b *Block) ParentCount() int {
 	return len(b.ParentHashes)
 }
 
+// BlockUpdate is a partial update of a stored block. Nil fields are left unchanged.
+type BlockUpdate struct {
+	IsChainBlock   *bool
+	SelectedParent *Hash
+	BlueScore      *uint64
+	BlueWork       *big.Int
+	DAAScore       *uint64
+	PruningPoint   *Hash
+	GasUsed        *uint64
+}
+
+// IsEmpty returns true if the update changes no field
+func (u BlockUpdate) IsEmpty() bool {
+	return u.IsChainBlock == nil && u.SelectedParent == nil && u.BlueScore == nil &&
+		u.BlueWork == nil && u.DAAScore == nil && u.PruningPoint == nil && u.GasUsed == nil
+}
+
+// Validate validates the fields the update sets
+func (u BlockUpdate) Validate() error {
+	if u.SelectedParent != nil && !u.SelectedParent.IsValid() {
+		return errors.New("invalid selected parent hash format")
+	}
+	if u.PruningPoint != nil && !u.PruningPoint.IsValid() {
+		return errors.New("invalid pruning point hash format")
+	}
+	if u.BlueWork != nil && u.BlueWork.Sign() < 0 {
+		return errors.New("blue work cannot be negative")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"math/big"
 	"strings"
 	"testing"
 
@@ -19,10 +20,10 @@ func TestBlock_Validate(t *testing.T) {
 		{
 			name: "valid block",
 			block: domain.Block{
-				Hash:      "0x" + strings.Repeat("a", 64),
+				Hash:      domain.Hash("0x" + strings.Repeat("a", 64)),
 				Number:    100,
 				Timestamp: 1706150400000,
-				ParentHashes: []string{"0x" + strings.Repeat("b", 64)},
+				ParentHashes: []domain.Hash{domain.Hash("0x" + strings.Repeat("b", 64))},
 			},
 			wantErr: false,
 		},
@@ -38,7 +39,7 @@ func TestBlock_Validate(t *testing.T) {
 		{
 			name: "invalid hash - no 0x prefix",
 			block: domain.Block{
-				Hash:   strings.Repeat("a", 64),
+				Hash:   domain.Hash(strings.Repeat("a", 64)),
 				Number: 100,
 			},
 			wantErr: true,
@@ -47,7 +48,7 @@ func TestBlock_Validate(t *testing.T) {
 		{
 			name: "invalid hash - invalid hex characters",
 			block: domain.Block{
-				Hash:   "0x" + strings.Repeat("g", 64),
+				Hash:   domain.Hash("0x" + strings.Repeat("g", 64)),
 				Number: 100,
 			},
 			wantErr: true,
@@ -56,7 +57,7 @@ func TestBlock_Validate(t *testing.T) {
 		{
 			name: "negative block number",
 			block: domain.Block{
-				Hash:   "0x" + strings.Repeat("a", 64),
+				Hash:   domain.Hash("0x" + strings.Repeat("a", 64)),
 				Number: -1,
 			},
 			wantErr: true,
@@ -65,7 +66,7 @@ func TestBlock_Validate(t *testing.T) {
 		{
 			name: "zero timestamp",
 			block: domain.Block{
-				Hash:      "0x" + strings.Repeat("a", 64),
+				Hash:      domain.Hash("0x" + strings.Repeat("a", 64)),
 				Number:    100,
 				Timestamp: 0,
 			},
@@ -75,7 +76,7 @@ func TestBlock_Validate(t *testing.T) {
 		{
 			name: "negative timestamp",
 			block: domain.Block{
-				Hash:      "0x" + strings.Repeat("a", 64),
+				Hash:      domain.Hash("0x" + strings.Repeat("a", 64)),
 				Number:    100,
 				Timestamp: -1,
 			},
@@ -85,10 +86,10 @@ func TestBlock_Validate(t *testing.T) {
 		{
 			name: "invalid parent hash format",
 			block: domain.Block{
-				Hash:      "0x" + strings.Repeat("a", 64),
+				Hash:      domain.Hash("0x" + strings.Repeat("a", 64)),
 				Number:    100,
 				Timestamp: 1706150400000,
-				ParentHashes: []string{"invalid"},
+				ParentHashes: []domain.Hash{"invalid"},
 			},
 			wantErr: true,
 			errMsg:  "invalid parent hash format",
@@ -96,13 +97,35 @@ func TestBlock_Validate(t *testing.T) {
 		{
 			name: "genesis block - zero number",
 			block: domain.Block{
-				Hash:      "0x" + strings.Repeat("a", 64),
+				Hash:      domain.Hash("0x" + strings.Repeat("a", 64)),
 				Number:    0,
 				Timestamp: 1706150400000,
-				ParentHashes: []string{},
+				ParentHashes: []domain.Hash{},
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid pruning point",
+			block: domain.Block{
+				Hash:         domain.Hash("0x" + strings.Repeat("a", 64)),
+				Number:       100,
+				Timestamp:    1706150400000,
+				PruningPoint: "0xabc",
+			},
+			wantErr: true,
+			errMsg:  "invalid pruning point hash format",
+		},
+		{
+			name: "invalid logs bloom",
+			block: domain.Block{
+				Hash:      domain.Hash("0x" + strings.Repeat("a", 64)),
+				Number:    100,
+				Timestamp: 1706150400000,
+				LogsBloom: "0x00",
+			},
+			wantErr: true,
+			errMsg:  "invalid logs bloom format",
+		},
 	}
 
 	for _, tt := range tests {
@@ -158,17 +181,17 @@ func TestBlock_ParentCount(t *testing.T) {
 	}{
 		{
 			name:  "no parents",
-			block: domain.Block{ParentHashes: []string{}},
+			block: domain.Block{ParentHashes: []domain.Hash{}},
 			want:  0,
 		},
 		{
 			name:  "single parent",
-			block: domain.Block{ParentHashes: []string{"0xabc"}},
+			block: domain.Block{ParentHashes: []domain.Hash{"0xabc"}},
 			want:  1,
 		},
 		{
 			name:  "multiple parents",
-			block: domain.Block{ParentHashes: []string{"0xabc", "0xdef", "0x123"}},
+			block: domain.Block{ParentHashes: []domain.Hash{"0xabc", "0xdef", "0x123"}},
 			want:  3,
 		},
 	}
@@ -180,3 +203,20 @@ func TestBlock_ParentCount(t *testing.T) {
 	}
 }
 
+
+func TestBlockUpdate_IsEmpty(t *testing.T) {
+	assert.True(t, domain.BlockUpdate{}.IsEmpty())
+
+	isChainBlock := false
+	assert.False(t, domain.BlockUpdate{IsChainBlock: &isChainBlock}.IsEmpty())
+}
+
+func TestBlockUpdate_Validate(t *testing.T) {
+	valid := domain.Hash("0x" + strings.Repeat("a", 64))
+	invalid := domain.Hash("0xABC")
+
+	assert.NoError(t, domain.BlockUpdate{SelectedParent: &valid, BlueWork: big.NewInt(1)}.Validate())
+	assert.Error(t, domain.BlockUpdate{SelectedParent: &invalid}.Validate())
+	assert.Error(t, domain.BlockUpdate{PruningPoint: &invalid}.Validate())
+	assert.Error(t, domain.BlockUpdate{BlueWork: big.NewInt(-1)}.Validate())
+}
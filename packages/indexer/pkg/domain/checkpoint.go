@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Checkpoint records the last block whose data has been fully committed
+type Checkpoint struct {
+	Name        string
+	BlockNumber int64
+	BlockHash   Hash
+	UpdatedAt   time.Time
+}
+
+// Validate validates the checkpoint structure
+func (c *Checkpoint) Validate() error {
+	if c.Name == "" {
+		return errors.New("checkpoint name cannot be empty")
+	}
+	if c.BlockNumber < 0 {
+		return errors.New("block number cannot be negative")
+	}
+	if !c.BlockHash.IsValid() {
+		return errors.New("invalid block hash format")
+	}
+	return nil
+}
+
+// NextBlock returns the number of the first block that still has to be indexed
+func (c *Checkpoint) NextBlock() int64 {
+	return c.BlockNumber + 1
+}
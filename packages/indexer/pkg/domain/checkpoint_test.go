@@ -0,0 +1,62 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestCheckpoint_Validate(t *testing.T) {
+	validHash := domain.Hash("0x" + strings.Repeat("a", 64))
+
+	tests := []struct {
+		name       string
+		checkpoint domain.Checkpoint
+		wantErr    bool
+	}{
+		{
+			name:       "valid checkpoint",
+			checkpoint: domain.Checkpoint{Name: "block_indexer", BlockNumber: 100, BlockHash: validHash},
+			wantErr:    false,
+		},
+		{
+			name:       "genesis checkpoint",
+			checkpoint: domain.Checkpoint{Name: "block_indexer", BlockNumber: 0, BlockHash: validHash},
+			wantErr:    false,
+		},
+		{
+			name:       "missing name",
+			checkpoint: domain.Checkpoint{BlockNumber: 100, BlockHash: validHash},
+			wantErr:    true,
+		},
+		{
+			name:       "negative block number",
+			checkpoint: domain.Checkpoint{Name: "block_indexer", BlockNumber: -1, BlockHash: validHash},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid hash",
+			checkpoint: domain.Checkpoint{Name: "block_indexer", BlockNumber: 100, BlockHash: "0xabc"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.checkpoint.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckpoint_NextBlock(t *testing.T) {
+	checkpoint := domain.Checkpoint{BlockNumber: 41}
+	assert.Equal(t, int64(42), checkpoint.NextBlock())
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "errors"
+
+// Page size limits for DAG traversals
+const (
+	DefaultDAGPageLimit = 100
+	MaxDAGPageLimit     = 1000
+)
+
+// DAGNode is a block reached by a DAG traversal
+type DAGNode struct {
+	Hash         Hash
+	BlueScore    uint64
+	IsChainBlock bool
+	Distance     int // Edges from the starting block; blue score difference for anticone nodes
+}
+
+// DAGPage selects one page of traversal results, which are ordered by distance, then hash.
+// Pass the last node of a page as After to fetch the next one.
+type DAGPage struct {
+	Limit int // DefaultDAGPageLimit when zero
+	After *DAGNode
+}
+
+// Size returns the number of nodes the page holds
+func (p DAGPage) Size() int {
+	if p.Limit == 0 {
+		return DefaultDAGPageLimit
+	}
+	return p.Limit
+}
+
+// Cursor returns the distance and hash results must sort after
+func (p DAGPage) Cursor() (int, Hash) {
+	if p.After == nil {
+		return -1, ""
+	}
+	return p.After.Distance, p.After.Hash
+}
+
+// Next returns the page that follows nodes, or nil if nodes is the last page
+func (p DAGPage) Next(nodes []*DAGNode) *DAGPage {
+	if len(nodes) < p.Size() {
+		return nil
+	}
+	return &DAGPage{Limit: p.Limit, After: nodes[len(nodes)-1]}
+}
+
+// Validate validates the page
+func (p DAGPage) Validate() error {
+	if p.Limit < 0 || p.Limit > MaxDAGPageLimit {
+		return errors.New("page limit must be between 0 and 1000")
+	}
+	if p.After != nil && !p.After.Hash.IsValid() {
+		return errors.New("invalid page cursor hash format")
+	}
+	return nil
+}
+
+// DAGBlock is an indexed block with its parents and stored GHOSTDAG data
+type DAGBlock struct {
+	Hash           Hash
+	Number         int64
+	BlueScore      uint64
+	IsChainBlock   bool
+	IsBlue         *bool         // Colour given by the merging chain block; nil until one merges it. Chain blocks are blue
+	Parents        []Hash        // From dag_relationships, selected parent first
+	SelectedParent Hash          // Empty when no parent is flagged as selected
+	GHOSTDAG       *GHOSTDAGData // nil when no GHOSTDAG data is stored
+}
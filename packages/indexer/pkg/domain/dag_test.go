@@ -0,0 +1,63 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestDAGPage_Next(t *testing.T) {
+	nodes := []*domain.DAGNode{
+		{Hash: domain.Hash("0x" + strings.Repeat("a", 64)), Distance: 1},
+		{Hash: domain.Hash("0x" + strings.Repeat("b", 64)), Distance: 2},
+	}
+
+	page := domain.DAGPage{Limit: 2}
+	distance, hash := page.Cursor()
+	assert.Equal(t, -1, distance)
+	assert.Empty(t, hash)
+
+	next := page.Next(nodes)
+	if assert.NotNil(t, next) {
+		assert.Equal(t, 2, next.Limit)
+		distance, hash = next.Cursor()
+		assert.Equal(t, 2, distance)
+		assert.Equal(t, nodes[1].Hash, hash)
+	}
+
+	// A short page is the last one
+	assert.Nil(t, domain.DAGPage{Limit: 3}.Next(nodes))
+	assert.Nil(t, domain.DAGPage{}.Next(nodes))
+}
+
+func TestDAGPage_Validate(t *testing.T) {
+	validHash := domain.Hash("0x" + strings.Repeat("a", 64))
+
+	tests := []struct {
+		name    string
+		page    domain.DAGPage
+		wantErr bool
+	}{
+		{name: "default page", page: domain.DAGPage{}, wantErr: false},
+		{name: "next page", page: domain.DAGPage{Limit: 50, After: &domain.DAGNode{Hash: validHash, Distance: 3}}, wantErr: false},
+		{name: "negative limit", page: domain.DAGPage{Limit: -1}, wantErr: true},
+		{name: "limit too large", page: domain.DAGPage{Limit: domain.MaxDAGPageLimit + 1}, wantErr: true},
+		{name: "invalid cursor", page: domain.DAGPage{After: &domain.DAGNode{Hash: "0xabc"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.page.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Equal(t, domain.DefaultDAGPageLimit, domain.DAGPage{}.Size())
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Kinds of items a decode anomaly can refer to
+const (
+	AnomalyKindTransaction = "transaction"
+	AnomalyKindLog         = "log"
+)
+
+// DecodeAnomaly records a transaction or log that was dropped from an RPC response
+// because it could not be decoded
+type DecodeAnomaly struct {
+	ID              int64
+	BlockHash       Hash
+	TransactionHash *Hash // Set for logs dropped from a receipt
+	Kind            string
+	ItemIndex       int    // Position in the list the item was dropped from
+	RawItem         string // The item's JSON as received
+	Reason          string
+	DetectedAt      time.Time
+}
+
+// Validate validates the anomaly structure
+func (a *DecodeAnomaly) Validate() error {
+	if !a.BlockHash.IsValid() {
+		return errors.New("invalid block hash format")
+	}
+	if a.TransactionHash != nil && !a.TransactionHash.IsValid() {
+		return errors.New("invalid transaction hash format")
+	}
+	if a.Kind != AnomalyKindTransaction && a.Kind != AnomalyKindLog {
+		return errors.New("unknown anomaly kind")
+	}
+	if a.ItemIndex < 0 {
+		return errors.New("item index cannot be negative")
+	}
+	if a.Reason == "" {
+		return errors.New("anomaly reason cannot be empty")
+	}
+	return nil
+}
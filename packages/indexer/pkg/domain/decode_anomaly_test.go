@@ -0,0 +1,69 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestDecodeAnomaly_Validate(t *testing.T) {
+	validHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	txHash := domain.Hash("0x" + strings.Repeat("b", 64))
+	badHash := domain.Hash("0xabc")
+
+	tests := []struct {
+		name    string
+		anomaly domain.DecodeAnomaly
+		wantErr bool
+	}{
+		{
+			name:    "dropped transaction",
+			anomaly: domain.DecodeAnomaly{BlockHash: validHash, Kind: domain.AnomalyKindTransaction, ItemIndex: 2, Reason: "missing gas"},
+			wantErr: false,
+		},
+		{
+			name:    "dropped receipt log",
+			anomaly: domain.DecodeAnomaly{BlockHash: validHash, TransactionHash: &txHash, Kind: domain.AnomalyKindLog, Reason: "invalid hex string"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid block hash",
+			anomaly: domain.DecodeAnomaly{BlockHash: badHash, Kind: domain.AnomalyKindTransaction, Reason: "missing gas"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid transaction hash",
+			anomaly: domain.DecodeAnomaly{BlockHash: validHash, TransactionHash: &badHash, Kind: domain.AnomalyKindLog, Reason: "missing address"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kind",
+			anomaly: domain.DecodeAnomaly{BlockHash: validHash, Kind: "withdrawal", Reason: "missing gas"},
+			wantErr: true,
+		},
+		{
+			name:    "negative index",
+			anomaly: domain.DecodeAnomaly{BlockHash: validHash, Kind: domain.AnomalyKindLog, ItemIndex: -1, Reason: "missing address"},
+			wantErr: true,
+		},
+		{
+			name:    "missing reason",
+			anomaly: domain.DecodeAnomaly{BlockHash: validHash, Kind: domain.AnomalyKindLog},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.anomaly.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,15 +1,19 @@
 package domain
 
-import "math/big"
+import (
+	"errors"
+	"math/big"
+)
 
 // GHOSTDAGData represents GHOSTDAG consensus data for a block
 type GHOSTDAGData struct {
-	BlockHash      string
-	BlueScore      uint64
-	BlueWork       *big.Int
-	SelectedParent string
-	MergeSetBlues  []string
-	MergeSetReds   []string
+	BlockHash          Hash
+	BlueScore          uint64
+	BlueWork           *big.Int
+	SelectedParent     Hash
+	MergeSetBlues      []Hash
+	MergeSetReds       []Hash
+	BluesAnticoneSizes []int // Anticone size of each merge set blue, in MergeSetBlues order
 }
 
 // IsBlue returns true if the block is in the blue set
@@ -22,3 +26,48 @@ func (g *GHOSTDAGData) MergeSetSize() int {
 	return len(g.MergeSetBlues) + len(g.MergeSetReds)
 }
 
+// BlockUpdate returns the update that copies the GHOSTDAG scores onto the block
+func (g *GHOSTDAGData) BlockUpdate() BlockUpdate {
+	update := BlockUpdate{
+		BlueScore: &g.BlueScore,
+		BlueWork:  g.BlueWork,
+	}
+	if g.SelectedParent != "" {
+		update.SelectedParent = &g.SelectedParent
+	}
+	return update
+}
+
+// MergeSet returns the block's mergeset in order, blues first
+func (g *GHOSTDAGData) MergeSet() []MergeSetMember {
+	members := make([]MergeSetMember, 0, g.MergeSetSize())
+	for _, hash := range g.MergeSetBlues {
+		members = append(members, MergeSetMember{MergingBlockHash: g.BlockHash, BlockHash: hash, IsBlue: true, Position: len(members)})
+	}
+	for _, hash := range g.MergeSetReds {
+		members = append(members, MergeSetMember{MergingBlockHash: g.BlockHash, BlockHash: hash, IsBlue: false, Position: len(members)})
+	}
+	return members
+}
+
+// MergeSetMember is one block merged by another block
+type MergeSetMember struct {
+	MergingBlockHash Hash
+	BlockHash        Hash // The merged block
+	IsBlue           bool
+	Position         int // Position in the merging block's mergeset, blues first
+}
+
+// Validate validates the mergeset member structure
+func (m *MergeSetMember) Validate() error {
+	if !m.MergingBlockHash.IsValid() {
+		return errors.New("invalid merging block hash format")
+	}
+	if !m.BlockHash.IsValid() {
+		return errors.New("invalid merged block hash format")
+	}
+	if m.Position < 0 {
+		return errors.New("mergeset position cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestGHOSTDAGData_MergeSet(t *testing.T) {
+	block := domain.Hash("0x" + strings.Repeat("a", 64))
+	blue1 := domain.Hash("0x" + strings.Repeat("b", 64))
+	blue2 := domain.Hash("0x" + strings.Repeat("c", 64))
+	red := domain.Hash("0x" + strings.Repeat("d", 64))
+
+	data := &domain.GHOSTDAGData{
+		BlockHash:     block,
+		MergeSetBlues: []domain.Hash{blue1, blue2},
+		MergeSetReds:  []domain.Hash{red},
+	}
+
+	assert.Equal(t, []domain.MergeSetMember{
+		{MergingBlockHash: block, BlockHash: blue1, IsBlue: true, Position: 0},
+		{MergingBlockHash: block, BlockHash: blue2, IsBlue: true, Position: 1},
+		{MergingBlockHash: block, BlockHash: red, IsBlue: false, Position: 2},
+	}, data.MergeSet())
+
+	assert.Empty(t, (&domain.GHOSTDAGData{BlockHash: block}).MergeSet())
+}
+
+func TestMergeSetMember_Validate(t *testing.T) {
+	validHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	mergedHash := domain.Hash("0x" + strings.Repeat("b", 64))
+
+	tests := []struct {
+		name    string
+		member  domain.MergeSetMember
+		wantErr bool
+	}{
+		{
+			name:    "blue member",
+			member:  domain.MergeSetMember{MergingBlockHash: validHash, BlockHash: mergedHash, IsBlue: true},
+			wantErr: false,
+		},
+		{
+			name:    "invalid merging block hash",
+			member:  domain.MergeSetMember{MergingBlockHash: "0xabc", BlockHash: mergedHash},
+			wantErr: true,
+		},
+		{
+			name:    "invalid merged block hash",
+			member:  domain.MergeSetMember{MergingBlockHash: validHash, BlockHash: "0xabc"},
+			wantErr: true,
+		},
+		{
+			name:    "negative position",
+			member:  domain.MergeSetMember{MergingBlockHash: validHash, BlockHash: mergedHash, Position: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.member.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
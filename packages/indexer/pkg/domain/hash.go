@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	canonicalHashRegex    = regexp.MustCompile(`^0x[0-9a-f]{64}$`)
+	canonicalAddressRegex = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+)
+
+// ZeroAddress is the all-zero address
+const ZeroAddress Address = "0x0000000000000000000000000000000000000000"
+
+// Hash is a 32-byte block or transaction hash in canonical form: 0x-prefixed lowercase hex.
+// Values built with ParseHash are always canonical; Validate methods reject any other value.
+type Hash string
+
+// ParseHash validates a hash in any letter case and returns its canonical form
+func ParseHash(s string) (Hash, error) {
+	if !hashRegex.MatchString(s) {
+		return "", ErrInvalidHash
+	}
+	return Hash(strings.ToLower(s)), nil
+}
+
+// ParseHashes parses every hash in hashes, keeping their order
+func ParseHashes(hashes []string) ([]Hash, error) {
+	if hashes == nil {
+		return nil, nil
+	}
+
+	parsed := make([]Hash, len(hashes))
+	for i, s := range hashes {
+		h, err := ParseHash(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = h
+	}
+	return parsed, nil
+}
+
+// String returns the canonical form of the hash
+func (h Hash) String() string {
+	return string(h)
+}
+
+// IsValid returns true if the hash is in canonical form
+func (h Hash) IsValid() bool {
+	return canonicalHashRegex.MatchString(string(h))
+}
+
+// MarshalText renders the hash in canonical form
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h), nil
+}
+
+// UnmarshalText parses a hash in any letter case
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
+
+// Address is a 20-byte account address in canonical form: 0x-prefixed lowercase hex.
+// Canonical form is what is stored and compared; Checksum renders it for output.
+type Address string
+
+// ParseAddress validates an address in any letter case, checksummed or not,
+// and returns its canonical form
+func ParseAddress(s string) (Address, error) {
+	if !addressRegex.MatchString(s) {
+		return "", ErrInvalidAddress
+	}
+	return Address(strings.ToLower(s)), nil
+}
+
+// ParseOptionalAddress parses s unless it is nil, as for the recipient of a contract creation
+func ParseOptionalAddress(s *string) (*Address, error) {
+	if s == nil {
+		return nil, nil
+	}
+	a, err := ParseAddress(*s)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// String returns the canonical form of the address
+func (a Address) String() string {
+	return string(a)
+}
+
+// Checksum returns the address with EIP-55 mixed-case checksum encoding
+func (a Address) Checksum() string {
+	return common.HexToAddress(string(a)).Hex()
+}
+
+// IsValid returns true if the address is in canonical form
+func (a Address) IsValid() bool {
+	return canonicalAddressRegex.MatchString(string(a))
+}
+
+// MarshalText renders the address with its EIP-55 checksum
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.Checksum()), nil
+}
+
+// UnmarshalText parses an address in any letter case
+func (a *Address) UnmarshalText(text []byte) error {
+	parsed, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
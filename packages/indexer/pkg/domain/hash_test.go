@@ -0,0 +1,92 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestParseHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    domain.Hash
+		wantErr bool
+	}{
+		{name: "lowercase", input: "0x" + strings.Repeat("ab", 32), want: domain.Hash("0x" + strings.Repeat("ab", 32))},
+		{name: "uppercase is lowered", input: "0x" + strings.Repeat("AB", 32), want: domain.Hash("0x" + strings.Repeat("ab", 32))},
+		{name: "uppercase prefix", input: "0X" + strings.Repeat("a", 64), wantErr: true},
+		{name: "too short", input: "0xabc", wantErr: true},
+		{name: "not hex", input: "0x" + strings.Repeat("g", 64), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.ParseHash(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidHash)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, got.IsValid())
+		})
+	}
+}
+
+func TestHash_IsValid_RejectsMixedCase(t *testing.T) {
+	assert.False(t, domain.Hash("0x"+strings.Repeat("A", 64)).IsValid())
+	assert.False(t, domain.Hash("").IsValid())
+}
+
+func TestParseAddress(t *testing.T) {
+	// EIP-55 test vector
+	checksummed := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+	addr, err := domain.ParseAddress(checksummed)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Address(strings.ToLower(checksummed)), addr)
+	assert.True(t, addr.IsValid())
+	assert.Equal(t, checksummed, addr.Checksum())
+
+	upper, err := domain.ParseAddress("0x" + strings.ToUpper(checksummed[2:]))
+	require.NoError(t, err)
+	assert.Equal(t, addr, upper)
+
+	_, err = domain.ParseAddress("0x123")
+	assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+}
+
+func TestParseOptionalAddress(t *testing.T) {
+	addr, err := domain.ParseOptionalAddress(nil)
+	require.NoError(t, err)
+	assert.Nil(t, addr)
+
+	s := "0x" + strings.Repeat("B", 40)
+	addr, err = domain.ParseOptionalAddress(&s)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Address("0x"+strings.Repeat("b", 40)), *addr)
+}
+
+func TestAddress_JSON(t *testing.T) {
+	type payload struct {
+		From domain.Address `json:"from"`
+		Hash domain.Hash    `json:"hash"`
+	}
+
+	var p payload
+	assert.Error(t, json.Unmarshal([]byte(`{"from":"0x123"}`), &p))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"from":"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED","hash":"0x`+strings.Repeat("C", 64)+`"}`), &p))
+	assert.Equal(t, domain.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"), p.From)
+	assert.Equal(t, domain.Hash("0x"+strings.Repeat("c", 64)), p.Hash)
+
+	out, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"from":"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed","hash":"0x`+strings.Repeat("c", 64)+`"}`, string(out))
+}
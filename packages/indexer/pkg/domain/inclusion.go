@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// InclusionStatus describes how the chain treated one inclusion of a transaction
+type InclusionStatus string
+
+// Statuses of a transaction inclusion
+const (
+	InclusionPending   InclusionStatus = "pending"   // No chain block has accepted the including block yet
+	InclusionAccepted  InclusionStatus = "accepted"  // The inclusion whose execution the chain kept
+	InclusionDuplicate InclusionStatus = "duplicate" // The transaction was already accepted through an earlier inclusion
+	InclusionConflict  InclusionStatus = "conflict"  // Another transaction with the same sender and nonce was accepted first
+)
+
+// IsValid returns true if s is a known inclusion status
+func (s InclusionStatus) IsValid() bool {
+	switch s {
+	case InclusionPending, InclusionAccepted, InclusionDuplicate, InclusionConflict:
+		return true
+	}
+	return false
+}
+
+// TransactionInclusion records one block that included a transaction. In a BlockDAG
+// parallel blocks can include the same transaction; only one inclusion is accepted.
+type TransactionInclusion struct {
+	TransactionHash    Hash
+	BlockHash          Hash
+	BlockNumber        int64
+	TransactionIndex   int
+	AcceptingBlockHash *Hash // Chain block whose mergeset accepted the including block
+	Status             InclusionStatus
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// IsAccepted returns true if the chain kept this inclusion
+func (i *TransactionInclusion) IsAccepted() bool {
+	return i.Status == InclusionAccepted
+}
+
+// Validate validates the inclusion structure
+func (i *TransactionInclusion) Validate() error {
+	if !i.TransactionHash.IsValid() {
+		return errors.New("invalid transaction hash format")
+	}
+	if !i.BlockHash.IsValid() {
+		return errors.New("invalid block hash format")
+	}
+	if i.AcceptingBlockHash != nil && !i.AcceptingBlockHash.IsValid() {
+		return errors.New("invalid accepting block hash format")
+	}
+	if i.TransactionIndex < 0 {
+		return errors.New("transaction index cannot be negative")
+	}
+	if !i.Status.IsValid() {
+		return errors.New("unknown inclusion status")
+	}
+	if (i.Status == InclusionPending) != (i.AcceptingBlockHash == nil) {
+		return errors.New("only pending inclusions lack an accepting block")
+	}
+	return nil
+}
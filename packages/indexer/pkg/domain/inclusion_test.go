@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestTransactionInclusion_Validate(t *testing.T) {
+	txHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	blockHash := domain.Hash("0x" + strings.Repeat("b", 64))
+	chainHash := domain.Hash("0x" + strings.Repeat("c", 64))
+	badHash := domain.Hash("0xabc")
+
+	tests := []struct {
+		name      string
+		inclusion domain.TransactionInclusion
+		wantErr   bool
+	}{
+		{
+			name:      "pending inclusion",
+			inclusion: domain.TransactionInclusion{TransactionHash: txHash, BlockHash: blockHash, Status: domain.InclusionPending},
+			wantErr:   false,
+		},
+		{
+			name:      "accepted inclusion",
+			inclusion: domain.TransactionInclusion{TransactionHash: txHash, BlockHash: blockHash, AcceptingBlockHash: &chainHash, Status: domain.InclusionAccepted},
+			wantErr:   false,
+		},
+		{
+			name:      "duplicate inclusion",
+			inclusion: domain.TransactionInclusion{TransactionHash: txHash, BlockHash: blockHash, AcceptingBlockHash: &chainHash, Status: domain.InclusionDuplicate},
+			wantErr:   false,
+		},
+		{
+			name:      "invalid transaction hash",
+			inclusion: domain.TransactionInclusion{TransactionHash: badHash, BlockHash: blockHash, Status: domain.InclusionPending},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid accepting block hash",
+			inclusion: domain.TransactionInclusion{TransactionHash: txHash, BlockHash: blockHash, AcceptingBlockHash: &badHash, Status: domain.InclusionAccepted},
+			wantErr:   true,
+		},
+		{
+			name:      "negative index",
+			inclusion: domain.TransactionInclusion{TransactionHash: txHash, BlockHash: blockHash, TransactionIndex: -1, Status: domain.InclusionPending},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown status",
+			inclusion: domain.TransactionInclusion{TransactionHash: txHash, BlockHash: blockHash, Status: "orphaned"},
+			wantErr:   true,
+		},
+		{
+			name:      "accepted without accepting block",
+			inclusion: domain.TransactionInclusion{TransactionHash: txHash, BlockHash: blockHash, Status: domain.InclusionAccepted},
+			wantErr:   true,
+		},
+		{
+			name:      "pending with accepting block",
+			inclusion: domain.TransactionInclusion{TransactionHash: txHash, BlockHash: blockHash, AcceptingBlockHash: &chainHash, Status: domain.InclusionPending},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.inclusion.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,22 +2,23 @@ package domain
 
 // Log represents an event log from a transaction
 type Log struct {
-	TransactionHash string
+	TransactionHash Hash
 	LogIndex        uint64
-	Address         string
+	Address         Address
 	Topics          []string
 	Data            []byte
 	BlockNumber     int64
-	BlockHash       string
+	BlockHash       Hash
+	Timestamp       int64 // Block timestamp
 }
 
 // Validate validates the log structure
 func (l *Log) Validate() error {
-	if l.TransactionHash == "" {
+	if !l.TransactionHash.IsValid() {
 		return ErrInvalidHash
 	}
 
-	if l.Address == "" {
+	if !l.Address.IsValid() {
 		return ErrInvalidAddress
 	}
 
@@ -17,8 +17,8 @@ func TestLog_Validate(t *testing.T) {
 		{
 			name: "valid log",
 			log: &domain.Log{
-				TransactionHash: "0x" + strings.Repeat("a", 64),
-				Address:        "0x" + strings.Repeat("b", 40),
+				TransactionHash: domain.Hash("0x" + strings.Repeat("a", 64)),
+				Address:        domain.Address("0x" + strings.Repeat("b", 40)),
 				Topics:         []string{"0xtopic1"},
 			},
 			wantErr: false,
@@ -26,14 +26,14 @@ func TestLog_Validate(t *testing.T) {
 		{
 			name: "missing transaction hash",
 			log: &domain.Log{
-				Address: "0x" + strings.Repeat("b", 40),
+				Address: domain.Address("0x" + strings.Repeat("b", 40)),
 			},
 			wantErr: true,
 		},
 		{
 			name: "missing address",
 			log: &domain.Log{
-				TransactionHash: "0x" + strings.Repeat("a", 64),
+				TransactionHash: domain.Hash("0x" + strings.Repeat("a", 64)),
 			},
 			wantErr: true,
 		},
@@ -53,8 +53,8 @@ func TestLog_Validate(t *testing.T) {
 
 func TestLog_HasTopic(t *testing.T) {
 	log := &domain.Log{
-		TransactionHash: "0x" + strings.Repeat("a", 64),
-		Address:        "0x" + strings.Repeat("b", 40),
+		TransactionHash: domain.Hash("0x" + strings.Repeat("a", 64)),
+		Address:        domain.Address("0x" + strings.Repeat("b", 40)),
 		Topics:         []string{"0xtopic1", "0xtopic2", "0xtopic3"},
 	}
 
@@ -73,8 +73,8 @@ func TestLog_TopicCount(t *testing.T) {
 		{
 			name: "no topics",
 			log: &domain.Log{
-				TransactionHash: "0x" + strings.Repeat("a", 64),
-				Address:        "0x" + strings.Repeat("b", 40),
+				TransactionHash: domain.Hash("0x" + strings.Repeat("a", 64)),
+				Address:        domain.Address("0x" + strings.Repeat("b", 40)),
 				Topics:         []string{},
 			},
 			count: 0,
@@ -82,8 +82,8 @@ func TestLog_TopicCount(t *testing.T) {
 		{
 			name: "three topics",
 			log: &domain.Log{
-				TransactionHash: "0x" + strings.Repeat("a", 64),
-				Address:        "0x" + strings.Repeat("b", 40),
+				TransactionHash: domain.Hash("0x" + strings.Repeat("a", 64)),
+				Address:        domain.Address("0x" + strings.Repeat("b", 40)),
 				Topics:         []string{"0xtopic1", "0xtopic2", "0xtopic3"},
 			},
 			count: 3,
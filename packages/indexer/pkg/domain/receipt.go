@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+)
+
+// Receipt holds the execution results of a transaction, as reported by its receipt
+type Receipt struct {
+	TransactionHash   Hash
+	Status            int // 0 = failed, 1 = success
+	Type              uint8
+	GasUsed           uint64
+	CumulativeGasUsed uint64   // Gas used in the block up to and including this transaction
+	EffectiveGasPrice *big.Int // Wei, nil when the node does not report it
+	ContractAddress   *Address // Set for contract deployments
+	LogsBloom         string
+}
+
+var bloomRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{512}$`)
+
+// Validate validates the receipt structure
+func (r *Receipt) Validate() error {
+	if !r.TransactionHash.IsValid() {
+		return errors.New("invalid transaction hash format")
+	}
+
+	if r.Status != 0 && r.Status != 1 {
+		return errors.New("status must be 0 or 1")
+	}
+
+	if r.GasUsed > r.CumulativeGasUsed && r.CumulativeGasUsed != 0 {
+		return errors.New("gas used cannot exceed cumulative gas used")
+	}
+
+	if r.ContractAddress != nil && !r.ContractAddress.IsValid() {
+		return errors.New("invalid contract address format")
+	}
+
+	if r.LogsBloom != "" && !bloomRegex.MatchString(r.LogsBloom) {
+		return errors.New("invalid logs bloom format")
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestReceipt_Validate(t *testing.T) {
+	validHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	validAddress := domain.Address("0x" + strings.Repeat("b", 40))
+	invalidAddress := domain.Address("0x123")
+
+	tests := []struct {
+		name    string
+		receipt domain.Receipt
+		wantErr bool
+	}{
+		{
+			name: "valid deployment receipt",
+			receipt: domain.Receipt{
+				TransactionHash:   validHash,
+				Status:            1,
+				GasUsed:           21000,
+				CumulativeGasUsed: 42000,
+				ContractAddress:   &validAddress,
+				LogsBloom:         "0x" + strings.Repeat("0", 512),
+			},
+		},
+		{
+			name:    "invalid transaction hash",
+			receipt: domain.Receipt{TransactionHash: "0x123"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid status",
+			receipt: domain.Receipt{TransactionHash: validHash, Status: 2},
+			wantErr: true,
+		},
+		{
+			name:    "gas used above cumulative",
+			receipt: domain.Receipt{TransactionHash: validHash, GasUsed: 50000, CumulativeGasUsed: 21000},
+			wantErr: true,
+		},
+		{
+			name:    "invalid contract address",
+			receipt: domain.Receipt{TransactionHash: validHash, ContractAddress: &invalidAddress},
+			wantErr: true,
+		},
+		{
+			name:    "short logs bloom",
+			receipt: domain.Receipt{TransactionHash: validHash, LogsBloom: "0x00"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.receipt.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// ReorgEvent describes a change of the virtual selected-parent chain.
+// Removed blocks left the chain, Added blocks joined it; both are above ForkPoint.
+type ReorgEvent struct {
+	ID                 int64
+	ForkPoint          Hash
+	ForkPointBlueScore uint64
+	Removed            []Hash
+	Added              []Hash
+	DetectedAt         time.Time
+}
+
+// IsEmpty returns true if the event does not change the chain
+func (e *ReorgEvent) IsEmpty() bool {
+	return len(e.Removed) == 0 && len(e.Added) == 0
+}
+
+// Depth returns the number of chain blocks that were reverted
+func (e *ReorgEvent) Depth() int {
+	return len(e.Removed)
+}
+
+// AffectedBlocks returns all blocks whose chain membership changed
+func (e *ReorgEvent) AffectedBlocks() []Hash {
+	affected := make([]Hash, 0, len(e.Removed)+len(e.Added))
+	affected = append(affected, e.Removed...)
+	affected = append(affected, e.Added...)
+	return affected
+}
@@ -0,0 +1,25 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+func TestReorgEvent_IsEmpty(t *testing.T) {
+	assert.True(t, (&domain.ReorgEvent{}).IsEmpty())
+	assert.False(t, (&domain.ReorgEvent{Removed: []domain.Hash{"0xa"}}).IsEmpty())
+	assert.False(t, (&domain.ReorgEvent{Added: []domain.Hash{"0xb"}}).IsEmpty())
+}
+
+func TestReorgEvent_AffectedBlocks(t *testing.T) {
+	event := &domain.ReorgEvent{
+		Removed: []domain.Hash{"0xa", "0xb"},
+		Added:   []domain.Hash{"0xc"},
+	}
+
+	assert.Equal(t, 2, event.Depth())
+	assert.Equal(t, []domain.Hash{"0xa", "0xb", "0xc"}, event.AffectedBlocks())
+}
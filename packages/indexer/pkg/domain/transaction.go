@@ -1,41 +1,78 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"math/big"
+)
 
 // Transaction represents a transaction in the Phoenix network
 type Transaction struct {
-	Hash             string
-	BlockHash        string
+	Hash             Hash
+	BlockHash        Hash
 	BlockNumber      int64
 	TransactionIndex int
-	From             string
-	To               *string // nil for contract creation
-	Value            uint64
+	Timestamp        int64 // Block timestamp
+	From             Address
+	To               *Address // nil for contract creation
+	Value            *big.Int // Wei
 	GasLimit         uint64
-	GasPrice         *uint64
+	GasPrice         *big.Int // Wei
 	GasUsed          *uint64
 	Nonce            uint64
 	Input            []byte
 	Status           *int // 0 = failed, 1 = success, nil = pending
 	CreatesContract  bool
-	ContractAddress  *string
+	ContractAddress  *Address
+
+	// EIP-2718 typed transaction fields
+	Type                 uint8
+	ChainID              *big.Int // nil for pre-EIP-155 legacy transactions
+	MaxFeePerGas         *big.Int // Wei, EIP-1559 and later
+	MaxPriorityFeePerGas *big.Int // Wei, EIP-1559 and later
+	EffectiveGasPrice    *big.Int // Wei actually paid per gas
+	AccessList           []AccessTuple
+	MaxFeePerBlobGas     *big.Int // Wei, blob transactions only
+	BlobVersionedHashes  []string
+
+	// Signature
+	V *big.Int
+	R string
+	S string
+
+	// Receipt fields, filled once the receipt is indexed
+	CumulativeGasUsed *uint64
+	LogsBloom         string
+}
+
+// Transaction types (EIP-2718)
+const (
+	TxTypeLegacy     uint8 = 0
+	TxTypeAccessList uint8 = 1 // EIP-2930
+	TxTypeDynamicFee uint8 = 2 // EIP-1559
+	TxTypeBlob       uint8 = 3 // EIP-4844
+)
+
+// AccessTuple is an EIP-2930 access list entry: an account and the storage slots it declares
+type AccessTuple struct {
+	Address     Address
+	StorageKeys []string
 }
 
 // Validate validates the transaction structure
 func (tx *Transaction) Validate() error {
-	if !hashRegex.MatchString(tx.Hash) {
+	if !tx.Hash.IsValid() {
 		return errors.New("invalid transaction hash format")
 	}
 
-	if !hashRegex.MatchString(tx.BlockHash) {
+	if !tx.BlockHash.IsValid() {
 		return errors.New("invalid block hash format")
 	}
 
-	if !addressRegex.MatchString(tx.From) {
+	if !tx.From.IsValid() {
 		return errors.New("invalid from address format")
 	}
 
-	if tx.To != nil && !addressRegex.MatchString(*tx.To) {
+	if tx.To != nil && !tx.To.IsValid() {
 		return errors.New("invalid to address format")
 	}
 
@@ -47,6 +84,23 @@ func (tx *Transaction) Validate() error {
 		return errors.New("nonce cannot be negative")
 	}
 
+	for _, tuple := range tx.AccessList {
+		if !tuple.Address.IsValid() {
+			return errors.New("invalid access list address format")
+		}
+		for _, key := range tuple.StorageKeys {
+			if !hashRegex.MatchString(key) {
+				return errors.New("invalid access list storage key format")
+			}
+		}
+	}
+
+	for _, hash := range tx.BlobVersionedHashes {
+		if !hashRegex.MatchString(hash) {
+			return errors.New("invalid blob versioned hash format")
+		}
+	}
+
 	return nil
 }
 
@@ -55,3 +109,51 @@ func (tx *Transaction) IsContractCreation() bool {
 	return tx.To == nil
 }
 
+// HasDynamicFee returns true if the transaction pays through max fee and priority fee (EIP-1559)
+func (tx *Transaction) HasDynamicFee() bool {
+	return tx.Type >= TxTypeDynamicFee
+}
+
+// ComputeEffectiveGasPrice returns the price per gas the transaction pays in a block with the given base fee.
+// Dynamic fee transactions pay min(maxFeePerGas, baseFee + maxPriorityFeePerGas). Other transactions, and
+// dynamic fee ones missing the base fee or their fee caps, pay their gas price; nil if that is unknown too.
+func (tx *Transaction) ComputeEffectiveGasPrice(baseFee *big.Int) *big.Int {
+	if tx.HasDynamicFee() && baseFee != nil && tx.MaxFeePerGas != nil && tx.MaxPriorityFeePerGas != nil {
+		price := new(big.Int).Add(baseFee, tx.MaxPriorityFeePerGas)
+		if price.Cmp(tx.MaxFeePerGas) > 0 {
+			price.Set(tx.MaxFeePerGas)
+		}
+		return price
+	}
+
+	if tx.GasPrice == nil {
+		return nil
+	}
+	return new(big.Int).Set(tx.GasPrice)
+}
+
+// TransactionUpdate is a partial update of a stored transaction. Nil fields are left unchanged.
+type TransactionUpdate struct {
+	Status            *int
+	GasUsed           *uint64
+	CumulativeGasUsed *uint64
+	EffectiveGasPrice *big.Int
+	IsAccepted        *bool
+}
+
+// IsEmpty returns true if the update changes no field
+func (u TransactionUpdate) IsEmpty() bool {
+	return u.Status == nil && u.GasUsed == nil && u.CumulativeGasUsed == nil &&
+		u.EffectiveGasPrice == nil && u.IsAccepted == nil
+}
+
+// Validate validates the fields the update sets
+func (u TransactionUpdate) Validate() error {
+	if u.Status != nil && *u.Status != 0 && *u.Status != 1 {
+		return errors.New("status must be 0 or 1")
+	}
+	if u.EffectiveGasPrice != nil && u.EffectiveGasPrice.Sign() < 0 {
+		return errors.New("effective gas price cannot be negative")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"math/big"
 	"strings"
 	"testing"
 
@@ -10,8 +11,8 @@ import (
 )
 
 func TestTransaction_Validate(t *testing.T) {
-	validHash := "0x" + strings.Repeat("a", 64)
-	validAddress := "0x" + strings.Repeat("b", 40)
+	validHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	validAddress := domain.Address("0x" + strings.Repeat("b", 40))
 
 	tests := []struct {
 		name    string
@@ -27,7 +28,7 @@ func TestTransaction_Validate(t *testing.T) {
 				BlockNumber: 100,
 				From:        validAddress,
 				To:          &validAddress,
-				Value:       1000000000000000000,
+				Value:       big.NewInt(1000000000000000000),
 				GasLimit:    21000,
 				Nonce:       5,
 			},
@@ -41,7 +42,7 @@ func TestTransaction_Validate(t *testing.T) {
 				BlockNumber: 100,
 				From:        validAddress,
 				To:          nil,
-				Value:       0,
+				Value:       big.NewInt(0),
 				GasLimit:    100000,
 				Nonce:       5,
 			},
@@ -76,7 +77,7 @@ func TestTransaction_Validate(t *testing.T) {
 				BlockHash:   validHash,
 				BlockNumber: 100,
 				From:        validAddress,
-				To:          stringPtr("invalid"),
+				To:          addressPtr("invalid"),
 			},
 			wantErr: true,
 			errMsg:  "invalid to address format",
@@ -121,7 +122,7 @@ func TestTransaction_Validate(t *testing.T) {
 }
 
 func TestTransaction_IsContractCreation(t *testing.T) {
-	validAddress := "0x" + strings.Repeat("b", 40)
+	validAddress := domain.Address("0x" + strings.Repeat("b", 40))
 
 	tests := []struct {
 		name string
@@ -151,7 +152,133 @@ func TestTransaction_IsContractCreation(t *testing.T) {
 	}
 }
 
-func stringPtr(s string) *string {
+func TestTransaction_Validate_TypedFields(t *testing.T) {
+	validHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	validAddress := domain.Address("0x" + strings.Repeat("b", 40))
+
+	tests := []struct {
+		name   string
+		modify func(tx *domain.Transaction)
+		errMsg string
+	}{
+		{
+			name: "valid access list and blob hashes",
+			modify: func(tx *domain.Transaction) {
+				tx.AccessList = []domain.AccessTuple{{Address: validAddress, StorageKeys: []string{validHash.String()}}}
+				tx.BlobVersionedHashes = []string{validHash.String()}
+			},
+		},
+		{
+			name: "invalid access list address",
+			modify: func(tx *domain.Transaction) {
+				tx.AccessList = []domain.AccessTuple{{Address: "0x123"}}
+			},
+			errMsg: "invalid access list address format",
+		},
+		{
+			name: "invalid storage key",
+			modify: func(tx *domain.Transaction) {
+				tx.AccessList = []domain.AccessTuple{{Address: validAddress, StorageKeys: []string{"0x01"}}}
+			},
+			errMsg: "invalid access list storage key format",
+		},
+		{
+			name: "invalid blob versioned hash",
+			modify: func(tx *domain.Transaction) {
+				tx.BlobVersionedHashes = []string{"0x01"}
+			},
+			errMsg: "invalid blob versioned hash format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := domain.Transaction{
+				Hash:      validHash,
+				BlockHash: validHash,
+				From:      validAddress,
+				Type:      domain.TxTypeBlob,
+			}
+			tt.modify(&tx)
+
+			err := tx.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestTransaction_ComputeEffectiveGasPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		tx      domain.Transaction
+		baseFee *big.Int
+		want    *big.Int
+	}{
+		{
+			name:    "legacy pays gas price",
+			tx:      domain.Transaction{Type: domain.TxTypeLegacy, GasPrice: big.NewInt(30)},
+			baseFee: big.NewInt(10),
+			want:    big.NewInt(30),
+		},
+		{
+			name:    "access list pays gas price",
+			tx:      domain.Transaction{Type: domain.TxTypeAccessList, GasPrice: big.NewInt(25)},
+			baseFee: big.NewInt(10),
+			want:    big.NewInt(25),
+		},
+		{
+			name: "dynamic fee pays base fee plus tip",
+			tx: domain.Transaction{
+				Type:                 domain.TxTypeDynamicFee,
+				MaxFeePerGas:         big.NewInt(100),
+				MaxPriorityFeePerGas: big.NewInt(2),
+			},
+			baseFee: big.NewInt(10),
+			want:    big.NewInt(12),
+		},
+		{
+			name: "dynamic fee capped at max fee",
+			tx: domain.Transaction{
+				Type:                 domain.TxTypeBlob,
+				MaxFeePerGas:         big.NewInt(11),
+				MaxPriorityFeePerGas: big.NewInt(5),
+			},
+			baseFee: big.NewInt(10),
+			want:    big.NewInt(11),
+		},
+		{
+			name:    "dynamic fee without base fee pays gas price",
+			tx:      domain.Transaction{Type: domain.TxTypeDynamicFee, GasPrice: big.NewInt(7)},
+			baseFee: nil,
+			want:    big.NewInt(7),
+		},
+		{
+			name:    "dynamic fee missing fee caps pays gas price",
+			tx:      domain.Transaction{Type: domain.TxTypeDynamicFee, GasPrice: big.NewInt(7)},
+			baseFee: big.NewInt(10),
+			want:    big.NewInt(7),
+		},
+		{
+			name:    "no gas price",
+			tx:      domain.Transaction{Type: domain.TxTypeLegacy},
+			baseFee: big.NewInt(10),
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tx.ComputeEffectiveGasPrice(tt.baseFee))
+		})
+	}
+}
+
+func addressPtr(s domain.Address) *domain.Address {
 	return &s
 }
 
@@ -13,5 +13,6 @@ var (
 var (
 	ErrInvalidHash    = errors.New("invalid hash format")
 	ErrInvalidAddress = errors.New("invalid address format")
+	ErrNotFound       = errors.New("not found")
 )
 
@@ -0,0 +1,327 @@
+// Package ghostdag re-runs the GHOSTDAG colouring over indexed blocks so the node's
+// reported blue scores, selected parents and mergesets can be checked independently.
+//
+// The colouring follows the Kaspa implementation of GHOSTDAG: the selected parent is
+// the parent with the most blue work (ties broken by the higher hash), the mergeset
+// is walked in ascending blue work order, and a candidate is blue while the blue set
+// stays a k-cluster. Blue work is taken from the stored data of known blocks and not
+// recomputed, because it depends on the node's difficulty encoding; blocks without it
+// are ordered by blue score instead.
+package ghostdag
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+)
+
+// DefaultK is the anticone size bound used when none is configured
+const DefaultK = 18
+
+// ErrMissingContext is returned when colouring a block needs GHOSTDAG data of a block
+// that was neither computed nor provided as context
+var ErrMissingContext = errors.New("missing GHOSTDAG context")
+
+// blockData is the GHOSTDAG state of one block
+type blockData struct {
+	blueScore          uint64
+	blueWork           *big.Int // nil when not known
+	selectedParent     domain.Hash
+	mergeSetBlues      []domain.Hash
+	mergeSetReds       []domain.Hash
+	bluesAnticoneSizes map[domain.Hash]int
+}
+
+// Colouring computes GHOSTDAG data block by block. Blocks must be added parents first.
+// Blocks outside the range being checked are added with AddKnown so the colouring of
+// later blocks can read their data; parents that were never added are treated as
+// part of the selected parent's past.
+type Colouring struct {
+	k       int
+	parents map[domain.Hash][]domain.Hash
+	heights map[domain.Hash]int // Longest parent path from the first added blocks
+	data    map[domain.Hash]*blockData
+}
+
+// New creates a Colouring with anticone bound k
+func New(k int) *Colouring {
+	return &Colouring{
+		k:       k,
+		parents: make(map[domain.Hash][]domain.Hash),
+		heights: make(map[domain.Hash]int),
+		data:    make(map[domain.Hash]*blockData),
+	}
+}
+
+// AddKnown adds a block whose GHOSTDAG data is taken as given
+func (c *Colouring) AddKnown(hash domain.Hash, parents []domain.Hash, data *domain.GHOSTDAGData) error {
+	if len(data.BluesAnticoneSizes) != len(data.MergeSetBlues) {
+		return fmt.Errorf("block %s: %d anticone sizes for %d mergeset blues",
+			hash, len(data.BluesAnticoneSizes), len(data.MergeSetBlues))
+	}
+
+	sizes := make(map[domain.Hash]int, len(data.MergeSetBlues))
+	for i, blue := range data.MergeSetBlues {
+		sizes[blue] = data.BluesAnticoneSizes[i]
+	}
+
+	c.link(hash, parents)
+	c.data[hash] = &blockData{
+		blueScore:          data.BlueScore,
+		blueWork:           data.BlueWork,
+		selectedParent:     data.SelectedParent,
+		mergeSetBlues:      data.MergeSetBlues,
+		mergeSetReds:       data.MergeSetReds,
+		bluesAnticoneSizes: sizes,
+	}
+	return nil
+}
+
+// Add colours a block from its parents and returns its GHOSTDAG data
+func (c *Colouring) Add(hash domain.Hash, parents []domain.Hash) (*domain.GHOSTDAGData, error) {
+	c.link(hash, parents)
+
+	known := make([]domain.Hash, 0, len(parents))
+	for _, parent := range parents {
+		if c.data[parent] != nil {
+			known = append(known, parent)
+		}
+	}
+
+	data := &blockData{bluesAnticoneSizes: make(map[domain.Hash]int)}
+	if len(known) == 0 {
+		if len(parents) > 0 {
+			return nil, fmt.Errorf("block %s: %w for any parent", hash, ErrMissingContext)
+		}
+		// Genesis
+		c.data[hash] = data
+		return c.export(hash, data), nil
+	}
+
+	selectedParent := known[0]
+	for _, parent := range known[1:] {
+		if c.less(selectedParent, parent) {
+			selectedParent = parent
+		}
+	}
+
+	data.selectedParent = selectedParent
+	data.mergeSetBlues = []domain.Hash{selectedParent}
+	data.bluesAnticoneSizes[selectedParent] = 0
+
+	for _, candidate := range c.mergeSetWithoutSelectedParent(selectedParent, known) {
+		isBlue, anticoneSize, anticoneSizes, err := c.checkBlueCandidate(data, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("block %s: %w", hash, err)
+		}
+
+		if !isBlue {
+			data.mergeSetReds = append(data.mergeSetReds, candidate)
+			continue
+		}
+
+		data.mergeSetBlues = append(data.mergeSetBlues, candidate)
+		data.bluesAnticoneSizes[candidate] = anticoneSize
+		for blue, size := range anticoneSizes {
+			data.bluesAnticoneSizes[blue] = size + 1
+		}
+	}
+
+	data.blueScore = c.data[selectedParent].blueScore + uint64(len(data.mergeSetBlues))
+	c.data[hash] = data
+
+	return c.export(hash, data), nil
+}
+
+// link records a block's parents and its height above them
+func (c *Colouring) link(hash domain.Hash, parents []domain.Hash) {
+	height := 0
+	for _, parent := range parents {
+		if h, ok := c.heights[parent]; ok && h+1 > height {
+			height = h + 1
+		}
+	}
+	c.parents[hash] = parents
+	c.heights[hash] = height
+}
+
+// less orders blocks by blue work, then hash. Blue score stands in for blue work
+// when either block's blue work is not known.
+func (c *Colouring) less(a, b domain.Hash) bool {
+	dataA, dataB := c.data[a], c.data[b]
+	if dataA.blueWork != nil && dataB.blueWork != nil {
+		if cmp := dataA.blueWork.Cmp(dataB.blueWork); cmp != 0 {
+			return cmp < 0
+		}
+		return a < b
+	}
+
+	if dataA.blueScore != dataB.blueScore {
+		return dataA.blueScore < dataB.blueScore
+	}
+	return a < b
+}
+
+// isAncestorOf reports whether a is in the past of b, walking b's parents down to a's height
+func (c *Colouring) isAncestorOf(a, b domain.Hash) bool {
+	floor, ok := c.heights[a]
+	if !ok {
+		return false
+	}
+
+	visited := map[domain.Hash]bool{b: true}
+	queue := []domain.Hash{b}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, parent := range c.parents[current] {
+			if parent == a {
+				return true
+			}
+			if visited[parent] || c.heights[parent] <= floor {
+				continue
+			}
+			visited[parent] = true
+			queue = append(queue, parent)
+		}
+	}
+	return false
+}
+
+// mergeSetWithoutSelectedParent returns the blocks in the past of the new block's parents
+// but not in the past of its selected parent, in ascending blue work order
+func (c *Colouring) mergeSetWithoutSelectedParent(selectedParent domain.Hash, parents []domain.Hash) []domain.Hash {
+	inMergeSet := make(map[domain.Hash]bool)
+	inSelectedPast := make(map[domain.Hash]bool)
+
+	var mergeSet, queue []domain.Hash
+	for _, parent := range parents {
+		if parent != selectedParent {
+			inMergeSet[parent] = true
+			mergeSet = append(mergeSet, parent)
+			queue = append(queue, parent)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, parent := range c.parents[current] {
+			if inMergeSet[parent] || inSelectedPast[parent] {
+				continue
+			}
+			// Blocks without data lie below the context and so in the selected parent's past
+			if c.data[parent] == nil || parent == selectedParent || c.isAncestorOf(parent, selectedParent) {
+				inSelectedPast[parent] = true
+				continue
+			}
+			inMergeSet[parent] = true
+			mergeSet = append(mergeSet, parent)
+			queue = append(queue, parent)
+		}
+	}
+
+	sortHashes(mergeSet, c.less)
+	return mergeSet
+}
+
+// checkBlueCandidate decides whether candidate can join the new block's blues without
+// breaking the k-cluster property. It returns the candidate's blue anticone size and the
+// anticone sizes of the blues in its anticone.
+func (c *Colouring) checkBlueCandidate(data *blockData, candidate domain.Hash) (bool, int, map[domain.Hash]int, error) {
+	// The new block's blues, including itself, must form a k-cluster
+	if len(data.mergeSetBlues) == c.k+1 {
+		return false, 0, nil, nil
+	}
+
+	anticoneSizes := make(map[domain.Hash]int, c.k)
+	anticoneSize := 0
+
+	// Walk the chain from the new block; the first chain block in the candidate's
+	// past has every remaining blue in the candidate's past too
+	var chainBlock domain.Hash
+	chainData := data
+	for {
+		if chainBlock != "" && c.isAncestorOf(chainBlock, candidate) {
+			return true, anticoneSize, anticoneSizes, nil
+		}
+
+		for _, blue := range chainData.mergeSetBlues {
+			if c.isAncestorOf(blue, candidate) {
+				continue
+			}
+
+			size, err := c.blueAnticoneSize(blue, data)
+			if err != nil {
+				return false, 0, nil, err
+			}
+			anticoneSizes[blue] = size
+			anticoneSize++
+
+			if anticoneSize > c.k || size == c.k {
+				return false, 0, nil, nil
+			}
+			if size > c.k {
+				return false, 0, nil, fmt.Errorf("blue %s has anticone size %d above k=%d", blue, size, c.k)
+			}
+		}
+
+		chainBlock = chainData.selectedParent
+		if chainBlock == "" {
+			return true, anticoneSize, anticoneSizes, nil
+		}
+		chainData = c.data[chainBlock]
+		if chainData == nil {
+			return false, 0, nil, fmt.Errorf("chain block %s: %w", chainBlock, ErrMissingContext)
+		}
+	}
+}
+
+// blueAnticoneSize returns the size of a blue block's blue anticone as seen from the new
+// block, reading it from the first chain block whose blues include it
+func (c *Colouring) blueAnticoneSize(blue domain.Hash, data *blockData) (int, error) {
+	for current := data; ; {
+		if size, ok := current.bluesAnticoneSizes[blue]; ok {
+			return size, nil
+		}
+		if current.selectedParent == "" {
+			return 0, fmt.Errorf("blue %s is not in the blue set of the chain", blue)
+		}
+
+		next := c.data[current.selectedParent]
+		if next == nil {
+			return 0, fmt.Errorf("chain block %s: %w", current.selectedParent, ErrMissingContext)
+		}
+		current = next
+	}
+}
+
+// export converts a block's state to domain.GHOSTDAGData
+func (c *Colouring) export(hash domain.Hash, data *blockData) *domain.GHOSTDAGData {
+	sizes := make([]int, len(data.mergeSetBlues))
+	for i, blue := range data.mergeSetBlues {
+		sizes[i] = data.bluesAnticoneSizes[blue]
+	}
+
+	return &domain.GHOSTDAGData{
+		BlockHash:          hash,
+		BlueScore:          data.blueScore,
+		SelectedParent:     data.selectedParent,
+		MergeSetBlues:      data.mergeSetBlues,
+		MergeSetReds:       data.mergeSetReds,
+		BluesAnticoneSizes: sizes,
+	}
+}
+
+// sortHashes sorts hashes in place by less (insertion sort; mergesets are small)
+func sortHashes(hashes []domain.Hash, less func(a, b domain.Hash) bool) {
+	for i := 1; i < len(hashes); i++ {
+		for j := i; j > 0 && less(hashes[j], hashes[j-1]); j-- {
+			hashes[j], hashes[j-1] = hashes[j-1], hashes[j]
+		}
+	}
+}
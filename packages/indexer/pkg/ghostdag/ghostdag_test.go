@@ -0,0 +1,141 @@
+package ghostdag_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/ghostdag"
+)
+
+func hash(n int) domain.Hash {
+	return domain.Hash(fmt.Sprintf("0x%064x", n))
+}
+
+func TestColouring_Chain(t *testing.T) {
+	c := ghostdag.New(ghostdag.DefaultK)
+
+	data, err := c.Add(hash(1), nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), data.BlueScore)
+	assert.Empty(t, data.SelectedParent)
+	assert.Empty(t, data.MergeSetBlues)
+
+	for n := 2; n <= 4; n++ {
+		data, err = c.Add(hash(n), []domain.Hash{hash(n - 1)})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(n-1), data.BlueScore)
+		assert.Equal(t, hash(n-1), data.SelectedParent)
+		assert.Equal(t, []domain.Hash{hash(n - 1)}, data.MergeSetBlues)
+		assert.Empty(t, data.MergeSetReds)
+		assert.Equal(t, []int{0}, data.BluesAnticoneSizes)
+	}
+}
+
+// diamond colours genesis 1, its children 2 and 3, and 4 merging both
+func diamond(t *testing.T, k int) *domain.GHOSTDAGData {
+	c := ghostdag.New(k)
+	_, err := c.Add(hash(1), nil)
+	require.NoError(t, err)
+	_, err = c.Add(hash(2), []domain.Hash{hash(1)})
+	require.NoError(t, err)
+	_, err = c.Add(hash(3), []domain.Hash{hash(1)})
+	require.NoError(t, err)
+
+	data, err := c.Add(hash(4), []domain.Hash{hash(2), hash(3)})
+	require.NoError(t, err)
+	return data
+}
+
+func TestColouring_Diamond(t *testing.T) {
+	// Equal blue scores select the higher hash
+	data := diamond(t, 1)
+	assert.Equal(t, hash(3), data.SelectedParent)
+	assert.Equal(t, []domain.Hash{hash(3), hash(2)}, data.MergeSetBlues)
+	assert.Empty(t, data.MergeSetReds)
+	assert.Equal(t, []int{1, 1}, data.BluesAnticoneSizes)
+	assert.Equal(t, uint64(3), data.BlueScore)
+
+	// With k=0 no block may have a blue anticone
+	data = diamond(t, 0)
+	assert.Equal(t, []domain.Hash{hash(3)}, data.MergeSetBlues)
+	assert.Equal(t, []domain.Hash{hash(2)}, data.MergeSetReds)
+	assert.Equal(t, uint64(2), data.BlueScore)
+}
+
+func TestColouring_KClusterBound(t *testing.T) {
+	c := ghostdag.New(2)
+	_, err := c.Add(hash(1), nil)
+	require.NoError(t, err)
+
+	var tips []domain.Hash
+	for n := 2; n <= 5; n++ {
+		_, err := c.Add(hash(n), []domain.Hash{hash(1)})
+		require.NoError(t, err)
+		tips = append(tips, hash(n))
+	}
+
+	data, err := c.Add(hash(6), tips)
+	require.NoError(t, err)
+
+	// The mergeset is coloured in ascending hash order after the selected parent
+	assert.Equal(t, hash(5), data.SelectedParent)
+	assert.Equal(t, []domain.Hash{hash(5), hash(2), hash(3)}, data.MergeSetBlues)
+	assert.Equal(t, []domain.Hash{hash(4)}, data.MergeSetReds)
+	assert.Equal(t, []int{2, 2, 2}, data.BluesAnticoneSizes)
+	assert.Equal(t, uint64(4), data.BlueScore)
+}
+
+func TestColouring_Context(t *testing.T) {
+	c := ghostdag.New(1)
+
+	// Block 10's parent is below the context
+	require.NoError(t, c.AddKnown(hash(10), []domain.Hash{hash(9)}, &domain.GHOSTDAGData{
+		BlueScore:          50,
+		SelectedParent:     hash(9),
+		MergeSetBlues:      []domain.Hash{hash(9)},
+		BluesAnticoneSizes: []int{0},
+	}))
+
+	data, err := c.Add(hash(11), []domain.Hash{hash(10)})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(51), data.BlueScore)
+
+	// A block none of whose parents are known cannot be coloured
+	_, err = c.Add(hash(13), []domain.Hash{hash(12)})
+	assert.ErrorIs(t, err, ghostdag.ErrMissingContext)
+
+	err = c.AddKnown(hash(14), nil, &domain.GHOSTDAGData{MergeSetBlues: []domain.Hash{hash(9)}})
+	assert.Error(t, err)
+}
+
+func TestColouring_BlueWorkOrder(t *testing.T) {
+	c := ghostdag.New(1)
+	require.NoError(t, c.AddKnown(hash(1), nil, &domain.GHOSTDAGData{BlueWork: big.NewInt(1)}))
+
+	// Block 2 has the lower blue score but more blue work than block 3
+	require.NoError(t, c.AddKnown(hash(2), []domain.Hash{hash(1)}, &domain.GHOSTDAGData{
+		BlueScore:          5,
+		BlueWork:           big.NewInt(300),
+		SelectedParent:     hash(1),
+		MergeSetBlues:      []domain.Hash{hash(1)},
+		BluesAnticoneSizes: []int{0},
+	}))
+	require.NoError(t, c.AddKnown(hash(3), []domain.Hash{hash(1)}, &domain.GHOSTDAGData{
+		BlueScore:          6,
+		BlueWork:           big.NewInt(200),
+		SelectedParent:     hash(1),
+		MergeSetBlues:      []domain.Hash{hash(1)},
+		BluesAnticoneSizes: []int{0},
+	}))
+
+	data, err := c.Add(hash(4), []domain.Hash{hash(3), hash(2)})
+	require.NoError(t, err)
+	assert.Equal(t, hash(2), data.SelectedParent, "the parent with the most blue work is selected")
+	assert.Equal(t, []domain.Hash{hash(2), hash(3)}, data.MergeSetBlues)
+	assert.Equal(t, uint64(7), data.BlueScore)
+}
@@ -0,0 +1,97 @@
+package ghostdag_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/ghostdag"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+// diamondWindow is genesis 1 at number 0, blocks 2 and 3 at number 1 and block 4
+// merging them at number 2, with the GHOSTDAG data a node with k>=1 stores
+func diamondWindow() []*domain.DAGBlock {
+	return []*domain.DAGBlock{
+		{Hash: hash(1), Number: 0, GHOSTDAG: &domain.GHOSTDAGData{}},
+		{Hash: hash(2), Number: 1, Parents: []domain.Hash{hash(1)}, GHOSTDAG: &domain.GHOSTDAGData{
+			BlueScore: 1, SelectedParent: hash(1), MergeSetBlues: []domain.Hash{hash(1)}, BluesAnticoneSizes: []int{0},
+		}},
+		{Hash: hash(3), Number: 1, Parents: []domain.Hash{hash(1)}, GHOSTDAG: &domain.GHOSTDAGData{
+			BlueScore: 1, SelectedParent: hash(1), MergeSetBlues: []domain.Hash{hash(1)}, BluesAnticoneSizes: []int{0},
+		}},
+		{Hash: hash(4), Number: 2, Parents: []domain.Hash{hash(3), hash(2)}, GHOSTDAG: &domain.GHOSTDAGData{
+			BlueScore: 3, SelectedParent: hash(3), MergeSetBlues: []domain.Hash{hash(3), hash(2)}, BluesAnticoneSizes: []int{1, 1},
+		}},
+	}
+}
+
+func TestVerifier_VerifyRange(t *testing.T) {
+	mockDAG := new(mocks.MockDAGWindowReader)
+	ctx := context.Background()
+
+	window := diamondWindow()
+	// Block 4 was stored as if block 2 were red; block 5 has no GHOSTDAG data
+	window[3].GHOSTDAG = &domain.GHOSTDAGData{
+		BlueScore: 2, SelectedParent: hash(3), MergeSetBlues: []domain.Hash{hash(3)},
+		MergeSetReds: []domain.Hash{hash(2)}, BluesAnticoneSizes: []int{0},
+	}
+	window = append(window, &domain.DAGBlock{Hash: hash(5), Number: 3, Parents: []domain.Hash{hash(4)}})
+	// Blocks arrive out of parent order
+	window[0], window[3] = window[3], window[0]
+
+	mockDAG.On("GetDAGWindow", ctx, int64(0), int64(3)).Return(window, nil)
+
+	verifier := ghostdag.NewVerifier(ghostdag.VerifierDeps{DAG: mockDAG, K: 1, Margin: 5})
+	mismatches, err := verifier.VerifyRange(ctx, 1, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ghostdag.Mismatch{
+		{BlockNumber: 2, BlockHash: hash(4), Field: "blue_score", Stored: "2", Computed: "3"},
+		{BlockNumber: 2, BlockHash: hash(4), Field: "merge_set_blues",
+			Stored: "[" + hash(3).String() + "]", Computed: "[" + hash(2).String() + "," + hash(3).String() + "]"},
+		{BlockNumber: 2, BlockHash: hash(4), Field: "merge_set_reds", Stored: "[" + hash(2).String() + "]", Computed: "[]"},
+		{BlockNumber: 3, BlockHash: hash(5), Field: "ghostdag_data", Stored: "missing", Computed: "present"},
+	}, mismatches)
+	mockDAG.AssertExpectations(t)
+}
+
+func TestVerifier_VerifyRange_Consistent(t *testing.T) {
+	mockDAG := new(mocks.MockDAGWindowReader)
+	ctx := context.Background()
+
+	// The margin is cut off at block zero
+	mockDAG.On("GetDAGWindow", ctx, int64(0), int64(2)).Return(diamondWindow(), nil)
+
+	verifier := ghostdag.NewVerifier(ghostdag.VerifierDeps{DAG: mockDAG, K: 1})
+	mismatches, err := verifier.VerifyRange(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	// The node's default k colours the same diamond identically
+	mismatches, err = ghostdag.NewVerifier(ghostdag.VerifierDeps{DAG: mockDAG}).VerifyRange(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
+
+func TestVerifier_VerifyRange_Errors(t *testing.T) {
+	mockDAG := new(mocks.MockDAGWindowReader)
+	ctx := context.Background()
+
+	mockDAG.On("GetDAGWindow", ctx, int64(0), int64(5)).Return(nil, errors.New("connection refused"))
+
+	_, err := ghostdag.NewVerifier(ghostdag.VerifierDeps{DAG: mockDAG}).VerifyRange(ctx, 5, 5)
+	assert.Error(t, err)
+
+	// Without context below the range the first block cannot be coloured
+	mockDAG.On("GetDAGWindow", ctx, int64(5), int64(10)).Return([]*domain.DAGBlock{
+		{Hash: hash(10), Number: 10, Parents: []domain.Hash{hash(9)}},
+	}, nil)
+
+	_, err = ghostdag.NewVerifier(ghostdag.VerifierDeps{DAG: mockDAG, Margin: 5}).VerifyRange(ctx, 10, 10)
+	assert.ErrorIs(t, err, ghostdag.ErrMissingContext)
+}
@@ -0,0 +1,186 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// defaultAuditBatchSize is the number of blocks fetched per RPC batch during an audit
+const defaultAuditBatchSize = 100
+
+// maxUint64 masks an amount to the 64 bits a uint64 conversion kept
+var maxUint64 = new(big.Int).SetUint64(^uint64(0))
+
+// AmountAuditorDeps contains dependencies for AmountAuditor (ISP)
+type AmountAuditorDeps struct {
+	RPC       interfaces.BatchBlockReader
+	Blocks    interfaces.BlockReader
+	Txs       interfaces.TransactionReader
+	BatchSize int // Blocks per RPC batch; defaults to 100
+	Logger    *zap.Logger
+}
+
+// AmountMismatch is a stored amount that differs from the node's
+type AmountMismatch struct {
+	BlockNumber int64
+	BlockHash   domain.Hash
+	TxHash      domain.Hash // Empty for block fields
+	Field       string      // Column name
+	Stored      *big.Int
+	Actual      *big.Int
+}
+
+// Truncated reports whether the stored amount is the node's amount cut to 64 bits,
+// the corruption left by indexers that converted amounts to uint64
+func (m AmountMismatch) Truncated() bool {
+	actual := amountOrZero(m.Actual)
+	if actual.IsUint64() {
+		return false
+	}
+	return new(big.Int).And(actual, maxUint64).Cmp(amountOrZero(m.Stored)) == 0
+}
+
+// AmountAuditor compares stored wei amounts with the node to find corrupted rows
+type AmountAuditor struct {
+	rpc       interfaces.BatchBlockReader
+	blocks    interfaces.BlockReader
+	txs       interfaces.TransactionReader
+	batchSize int
+	logger    *zap.Logger
+}
+
+// NewAmountAuditor creates a new AmountAuditor
+func NewAmountAuditor(deps AmountAuditorDeps) *AmountAuditor {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	batchSize := deps.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultAuditBatchSize
+	}
+
+	return &AmountAuditor{
+		rpc:       deps.RPC,
+		blocks:    deps.Blocks,
+		txs:       deps.Txs,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// AuditRange compares the base fee, value and gas price stored for blocks from..to
+// with the node and returns every mismatch. Blocks that are not indexed are skipped.
+func (a *AmountAuditor) AuditRange(ctx context.Context, from, to int64) ([]AmountMismatch, error) {
+	var mismatches []AmountMismatch
+
+	for start := from; start <= to; start += int64(a.batchSize) {
+		end := start + int64(a.batchSize) - 1
+		if end > to {
+			end = to
+		}
+
+		numbers := make([]*big.Int, 0, end-start+1)
+		for n := start; n <= end; n++ {
+			numbers = append(numbers, big.NewInt(n))
+		}
+
+		blocks, err := a.rpc.GetBlocksByNumber(ctx, numbers, true)
+		if err != nil {
+			return mismatches, fmt.Errorf("fetch blocks %d-%d: %w", start, end, err)
+		}
+
+		for _, block := range blocks {
+			if block == nil {
+				continue
+			}
+
+			found, err := a.auditBlock(ctx, block)
+			if err != nil {
+				return mismatches, err
+			}
+			mismatches = append(mismatches, found...)
+		}
+
+		a.logger.Debug("amounts audited",
+			zap.Int64("from", start),
+			zap.Int64("to", end),
+			zap.Int("mismatches", len(mismatches)))
+	}
+
+	return mismatches, nil
+}
+
+// auditBlock compares one block and its transactions with the stored rows
+func (a *AmountAuditor) auditBlock(ctx context.Context, block *interfaces.Block) ([]AmountMismatch, error) {
+	blockHash, err := domain.ParseHash(block.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("block %d hash %q: %w", block.Number, block.Hash, err)
+	}
+
+	stored, err := a.blocks.GetBlockByHash(ctx, blockHash)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get block %s: %w", blockHash, err)
+	}
+
+	var mismatches []AmountMismatch
+	mismatch := func(txHash domain.Hash, field string, stored, actual *big.Int) {
+		if amountOrZero(stored).Cmp(amountOrZero(actual)) != 0 {
+			mismatches = append(mismatches, AmountMismatch{
+				BlockNumber: block.Number,
+				BlockHash:   blockHash,
+				TxHash:      txHash,
+				Field:       field,
+				Stored:      stored,
+				Actual:      actual,
+			})
+		}
+	}
+
+	mismatch("", "base_fee_per_gas", stored.BaseFeePerGas, block.BaseFeePerGas)
+
+	txs, err := a.txs.GetTransactionsByBlockHash(ctx, blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("get transactions of block %s: %w", blockHash, err)
+	}
+
+	storedTxs := make(map[domain.Hash]*domain.Transaction, len(txs))
+	for _, tx := range txs {
+		storedTxs[tx.Hash] = tx
+	}
+
+	for _, tx := range block.Transactions {
+		txHash, err := domain.ParseHash(tx.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("transaction hash %q: %w", tx.Hash, err)
+		}
+
+		storedTx, ok := storedTxs[txHash]
+		if !ok {
+			continue
+		}
+		mismatch(txHash, "value", storedTx.Value, tx.Value)
+		mismatch(txHash, "gas_price", storedTx.GasPrice, tx.GasPrice)
+	}
+
+	return mismatches, nil
+}
+
+// amountOrZero treats a missing amount as zero
+func amountOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
@@ -0,0 +1,134 @@
+package indexer_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+func bigAmount(s string) *big.Int {
+	v, _ := new(big.Int).SetString(s, 10)
+	return v
+}
+
+func TestAmountAuditor_AuditRange(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockTxs := new(mocks.MockTransactionReader)
+
+	ctx := context.Background()
+	indexed, missing := domain.Hash("0x"+strings.Repeat("a", 64)), domain.Hash("0x"+strings.Repeat("b", 64))
+	bigTx, smallTx := domain.Hash("0x"+strings.Repeat("c", 64)), domain.Hash("0x"+strings.Repeat("d", 64))
+
+	hundredCoins := bigAmount("100000000000000000000")
+
+	mockRPC.On("GetBlocksByNumber", ctx, []*big.Int{big.NewInt(10), big.NewInt(11)}, true).
+		Return([]*interfaces.Block{
+			{
+				Hash:          indexed.String(),
+				Number:        10,
+				BaseFeePerGas: big.NewInt(7),
+				Transactions: []interfaces.Transaction{
+					{Hash: bigTx.String(), Value: hundredCoins, GasPrice: big.NewInt(1)},
+					{Hash: smallTx.String(), Value: big.NewInt(5), GasPrice: big.NewInt(1)},
+				},
+			},
+			{Hash: missing.String(), Number: 11},
+		}, nil)
+
+	mockBlocks.On("GetBlockByHash", ctx, indexed).
+		Return(&domain.Block{Hash: indexed, BaseFeePerGas: big.NewInt(7)}, nil)
+	mockBlocks.On("GetBlockByHash", ctx, missing).
+		Return(nil, fmt.Errorf("block %w: %s", domain.ErrNotFound, missing))
+
+	truncated := new(big.Int).SetUint64(hundredCoins.Uint64())
+	mockTxs.On("GetTransactionsByBlockHash", ctx, indexed).
+		Return([]*domain.Transaction{
+			{Hash: bigTx, Value: truncated, GasPrice: big.NewInt(1)},
+			{Hash: smallTx, Value: big.NewInt(5), GasPrice: big.NewInt(1)},
+		}, nil)
+
+	auditor := indexer.NewAmountAuditor(indexer.AmountAuditorDeps{
+		RPC:       mockRPC,
+		Blocks:    mockBlocks,
+		Txs:       mockTxs,
+		BatchSize: 2,
+	})
+
+	mismatches, err := auditor.AuditRange(ctx, 10, 11)
+
+	require.NoError(t, err)
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, bigTx, mismatches[0].TxHash)
+	assert.Equal(t, "value", mismatches[0].Field)
+	assert.Equal(t, int64(10), mismatches[0].BlockNumber)
+	assert.True(t, mismatches[0].Truncated())
+	mockTxs.AssertNotCalled(t, "GetTransactionsByBlockHash", ctx, missing)
+}
+
+func TestAmountAuditor_AuditRange_Batches(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+
+	ctx := context.Background()
+	mockRPC.On("GetBlocksByNumber", ctx, mock.Anything, true).
+		Return([]*interfaces.Block{nil, nil}, nil)
+
+	auditor := indexer.NewAmountAuditor(indexer.AmountAuditorDeps{
+		RPC:       mockRPC,
+		Blocks:    new(mocks.MockBlockReader),
+		Txs:       new(mocks.MockTransactionReader),
+		BatchSize: 2,
+	})
+
+	_, err := auditor.AuditRange(ctx, 1, 5)
+
+	require.NoError(t, err)
+	mockRPC.AssertNumberOfCalls(t, "GetBlocksByNumber", 3)
+	assert.Len(t, mockRPC.Calls[2].Arguments.Get(1), 1, "last batch holds the remaining block")
+}
+
+func TestAmountAuditor_AuditRange_RPCFailure(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+
+	ctx := context.Background()
+	mockRPC.On("GetBlocksByNumber", ctx, mock.Anything, true).Return(nil, assert.AnError)
+
+	auditor := indexer.NewAmountAuditor(indexer.AmountAuditorDeps{RPC: mockRPC})
+
+	_, err := auditor.AuditRange(ctx, 1, 5)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestAmountMismatch_Truncated(t *testing.T) {
+	hundredCoins := bigAmount("100000000000000000000")
+
+	tests := []struct {
+		name   string
+		stored *big.Int
+		actual *big.Int
+		want   bool
+	}{
+		{name: "cut to 64 bits", stored: new(big.Int).SetUint64(hundredCoins.Uint64()), actual: hundredCoins, want: true},
+		{name: "fits in uint64", stored: big.NewInt(1), actual: big.NewInt(2), want: false},
+		{name: "unrelated value", stored: big.NewInt(1), actual: hundredCoins, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := indexer.AmountMismatch{Stored: tt.stored, Actual: tt.actual}
+			assert.Equal(t, tt.want, m.Truncated())
+		})
+	}
+}
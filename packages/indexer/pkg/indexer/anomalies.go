@@ -0,0 +1,34 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// saveDecodeAnomalies records the items the RPC layer dropped from a block or,
+// when txHash is set, from that transaction's receipt
+func saveDecodeAnomalies(
+	ctx context.Context,
+	db interfaces.DecodeAnomalyWriter,
+	blockHash domain.Hash,
+	txHash *domain.Hash,
+	anomalies []interfaces.DecodeAnomaly,
+) error {
+	for _, anomaly := range anomalies {
+		err := db.SaveDecodeAnomaly(ctx, &domain.DecodeAnomaly{
+			BlockHash:       blockHash,
+			TransactionHash: txHash,
+			Kind:            anomaly.Kind,
+			ItemIndex:       anomaly.Index,
+			RawItem:         anomaly.Raw,
+			Reason:          anomaly.Reason,
+		})
+		if err != nil {
+			return fmt.Errorf("save %s anomaly %d: %w", anomaly.Kind, anomaly.Index, err)
+		}
+	}
+	return nil
+}
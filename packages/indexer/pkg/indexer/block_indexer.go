@@ -6,25 +6,53 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
 	"go.uber.org/zap"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
 )
 
+// defaultBlockWorkers is the number of blocks IndexBlockRange indexes concurrently
+const defaultBlockWorkers = 10
+
+// defaultTxWorkers is the number of transactions of one block fetched concurrently by hash
+const defaultTxWorkers = 8
+
+// BlockHook persists additional data for a block inside the block's unit of work
+type BlockHook func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error
+
 // BlockIndexerDeps contains dependencies for BlockIndexer (ISP: only what's needed)
 type BlockIndexerDeps struct {
-	RPC    interfaces.BlockByNumberReader
-	DB     interfaces.BlockWriter
-	TxDB   interfaces.TransactionWriter
-	Logger *zap.Logger
+	RPC       interfaces.BlockByNumberReader
+	BatchRPC  interfaces.BatchBlockReader        // Optional: fetches each bulk batch in one round trip
+	TxRPC     interfaces.TransactionByHashReader // Optional: fetch blocks with transaction hashes only, then each transaction by hash
+	TxWorkers int                                // Transactions fetched concurrently per block with TxRPC; defaults to 8
+	DB        interfaces.BlockWriter
+	TxDB      interfaces.TransactionWriter
+	AnomalyDB interfaces.DecodeAnomalyWriter // Optional: records transactions dropped by lenient RPC decoding
+	Store     interfaces.UnitOfWork          // Optional: persists each block in a single transaction
+	Hooks     []BlockHook                    // Run inside the transaction after the block is saved; require Store
+	Reorgs    *ReorgHandler                  // Optional: reconciles the chain after chain blocks are saved
+	Workers   int                            // Blocks indexed concurrently by IndexBlockRange; needs as many DB connections
+	BatchSize int                            // Blocks per bulk write in IndexBlockRange; 0 indexes blocks one at a time; requires Store
+	Logger    *zap.Logger
 }
 
 // BlockIndexer indexes blocks from Phoenix Node
 type BlockIndexer struct {
-	rpc    interfaces.BlockByNumberReader
-	db     interfaces.BlockWriter
-	txDB   interfaces.TransactionWriter
-	logger *zap.Logger
+	rpc       interfaces.BlockByNumberReader
+	batchRPC  interfaces.BatchBlockReader
+	txRPC     interfaces.TransactionByHashReader
+	txWorkers int
+	db        interfaces.BlockWriter
+	txDB      interfaces.TransactionWriter
+	anomalyDB interfaces.DecodeAnomalyWriter
+	store     interfaces.UnitOfWork
+	hooks     []BlockHook
+	reorgs    *ReorgHandler
+	workers   int
+	batchSize int
+	logger    *zap.Logger
 }
 
 // NewBlockIndexer creates a new BlockIndexer
@@ -34,71 +62,253 @@ func NewBlockIndexer(deps BlockIndexerDeps) *BlockIndexer {
 		logger = zap.NewNop()
 	}
 
+	if deps.Store == nil && len(deps.Hooks) > 0 {
+		logger.Warn("block hooks configured without a store, they will not run")
+	}
+
+	workers := deps.Workers
+	if workers <= 0 {
+		workers = defaultBlockWorkers
+	}
+
+	txWorkers := deps.TxWorkers
+	if txWorkers <= 0 {
+		txWorkers = defaultTxWorkers
+	}
+
 	return &BlockIndexer{
-		rpc:    deps.RPC,
-		db:     deps.DB,
-		txDB:   deps.TxDB,
-		logger: logger,
+		rpc:       deps.RPC,
+		batchRPC:  deps.BatchRPC,
+		txRPC:     deps.TxRPC,
+		txWorkers: txWorkers,
+		db:        deps.DB,
+		txDB:      deps.TxDB,
+		anomalyDB: deps.AnomalyDB,
+		store:     deps.Store,
+		hooks:     deps.Hooks,
+		reorgs:    deps.Reorgs,
+		workers:   workers,
+		batchSize: deps.BatchSize,
+		logger:    logger,
 	}
 }
 
-// IndexBlock indexes a single block
-func (bi *BlockIndexer) IndexBlock(ctx context.Context, blockNum *big.Int) error {
+// IndexBlock indexes a single block and returns the hash of the block saved at that number
+func (bi *BlockIndexer) IndexBlock(ctx context.Context, blockNum *big.Int) (domain.Hash, error) {
 	// 1. Fetch block from RPC
-	rpcBlock, err := bi.rpc.GetBlockByNumber(ctx, blockNum, true)
+	rpcBlock, err := bi.fetchBlock(ctx, blockNum)
+	if err != nil {
+		return "", err
+	}
+
+	// 2. Persist block and its data
+	block, err := bi.indexFetchedBlock(ctx, rpcBlock)
+	if err != nil {
+		return "", err
+	}
+	return block.Hash, nil
+}
+
+// IndexFetchedBlock persists a block that was already fetched from RPC,
+// atomically when a store is configured
+func (bi *BlockIndexer) IndexFetchedBlock(ctx context.Context, rpcBlock *interfaces.Block) error {
+	_, err := bi.indexFetchedBlock(ctx, rpcBlock)
+	return err
+}
+
+// indexFetchedBlock persists a fetched block and returns it as saved
+func (bi *BlockIndexer) indexFetchedBlock(ctx context.Context, rpcBlock *interfaces.Block) (*domain.Block, error) {
+	if err := bi.fetchTransactions(ctx, rpcBlock); err != nil {
+		return nil, err
+	}
+
+	var block *domain.Block
+	var err error
+	if bi.store == nil {
+		block, err = bi.persist(ctx, rpcBlock)
+	} else {
+		err = bi.store.WithTx(ctx, func(repos interfaces.Repos) error {
+			var txErr error
+			block, txErr = bi.withRepos(repos).persist(ctx, rpcBlock)
+			if txErr != nil {
+				return txErr
+			}
+
+			for _, hook := range bi.hooks {
+				if err := hook(ctx, repos, rpcBlock); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		bi.logger.Error("failed to persist block",
+			zap.Int64("blockNumber", rpcBlock.Number),
+			zap.String("hash", rpcBlock.Hash),
+			zap.Error(err))
+		return nil, err
+	}
+
+	bi.logger.Info("block indexed",
+		zap.Int64("number", block.Number),
+		zap.String("hash", block.Hash.String()),
+		zap.Int("txCount", len(block.Transactions)))
+
+	return block, nil
+}
+
+// fetchBlock fetches a block from RPC, with full transactions unless they are fetched by hash
+func (bi *BlockIndexer) fetchBlock(ctx context.Context, blockNum *big.Int) (*interfaces.Block, error) {
+	rpcBlock, err := bi.rpc.GetBlockByNumber(ctx, blockNum, bi.fullTx())
 	if err != nil {
 		bi.logger.Error("failed to fetch block",
 			zap.String("blockNumber", blockNum.String()),
 			zap.Error(err))
-		return fmt.Errorf("fetch block %s: %w", blockNum, err)
+		return nil, fmt.Errorf("fetch block %s: %w", blockNum, err)
 	}
 
 	if rpcBlock == nil {
-		return fmt.Errorf("block %s not found", blockNum)
+		return nil, fmt.Errorf("block %s not found", blockNum)
+	}
+
+	return rpcBlock, nil
+}
+
+// fullTx reports whether blocks are fetched with full transaction objects
+func (bi *BlockIndexer) fullTx() bool {
+	return bi.txRPC == nil
+}
+
+// fetchTransactions fills in the transactions of a block that lists them by hash only,
+// fetching up to txWorkers of them concurrently
+func (bi *BlockIndexer) fetchTransactions(ctx context.Context, rpcBlock *interfaces.Block) error {
+	if !rpcBlock.IsHashOnly() {
+		return nil
+	}
+	if bi.txRPC == nil {
+		return fmt.Errorf("block %s lists transaction hashes only and no transaction reader is configured", rpcBlock.Hash)
 	}
 
-	// 2. Convert RPC block to domain block
-	block := bi.convertRPCBlockToDomain(rpcBlock)
+	hashes := rpcBlock.TransactionHashes
+	txs, err := fetchAll(len(hashes), bi.txWorkers, func(i int) (interfaces.Transaction, error) {
+		tx, err := bi.txRPC.GetTransactionByHash(ctx, common.HexToHash(hashes[i]))
+		if err != nil {
+			return interfaces.Transaction{}, fmt.Errorf("fetch transaction %s: %w", hashes[i], err)
+		}
+		if tx == nil {
+			return interfaces.Transaction{}, fmt.Errorf("transaction %s not found", hashes[i])
+		}
+		return *tx, nil
+	})
+	if err != nil {
+		bi.logger.Error("failed to fetch block transactions",
+			zap.String("hash", rpcBlock.Hash),
+			zap.Int("txCount", len(hashes)),
+			zap.Error(err))
+		return fmt.Errorf("block %s: %w", rpcBlock.Hash, err)
+	}
 
-	// 3. Validate block
+	rpcBlock.Transactions = txs
+	return nil
+}
+
+// persist validates and saves a block with its transactions and reconciles the chain
+func (bi *BlockIndexer) persist(ctx context.Context, rpcBlock *interfaces.Block) (*domain.Block, error) {
+	// 1. Convert RPC block to domain block
+	block, err := bi.convertRPCBlockToDomain(rpcBlock)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block: %w", err)
+	}
+
+	// 2. Validate block
 	if err := block.Validate(); err != nil {
-		return fmt.Errorf("invalid block: %w", err)
+		return nil, fmt.Errorf("invalid block: %w", err)
 	}
 
-	// 4. Save block
+	// 3. Save block
 	if err := bi.db.SaveBlock(ctx, block); err != nil {
-		return fmt.Errorf("save block: %w", err)
+		return nil, fmt.Errorf("save block: %w", err)
 	}
 
-	// 5. Save transactions
-	for _, rpcTx := range rpcBlock.Transactions {
-		tx := bi.convertRPCTransactionToDomain(rpcTx, block.Hash, block.Number)
+	// 4. Save transactions; a missing transaction leaves the block incomplete
+	for i := range block.Transactions {
+		tx := &block.Transactions[i]
 		if err := bi.txDB.SaveTransaction(ctx, tx); err != nil {
-			bi.logger.Warn("failed to save transaction",
-				zap.String("txHash", tx.Hash),
-				zap.Error(err))
-			// Continue processing other transactions
+			return nil, fmt.Errorf("save transaction %s: %w", tx.Hash, err)
 		}
 	}
 
-	bi.logger.Info("block indexed",
-		zap.Int64("number", block.Number),
-		zap.String("hash", block.Hash),
-		zap.Int("txCount", len(block.Transactions)))
+	// 5. Record transactions the RPC layer could not decode
+	if err := bi.saveAnomalies(ctx, bi.anomalyDB, block, rpcBlock); err != nil {
+		return nil, err
+	}
 
+	// 6. Reconcile the selected-parent chain
+	if bi.reorgs != nil && block.IsChainBlock {
+		if _, err := bi.reorgs.HandleChainBlock(ctx, block); err != nil {
+			return nil, fmt.Errorf("handle chain change: %w", err)
+		}
+	}
+
+	return block, nil
+}
+
+// saveAnomalies records the transactions dropped from a block; without a writer they are only logged
+func (bi *BlockIndexer) saveAnomalies(
+	ctx context.Context,
+	db interfaces.DecodeAnomalyWriter,
+	block *domain.Block,
+	rpcBlock *interfaces.Block,
+) error {
+	if len(rpcBlock.Anomalies) == 0 {
+		return nil
+	}
+
+	if db == nil {
+		bi.logger.Warn("dropped transactions not recorded, no anomaly writer configured",
+			zap.String("hash", rpcBlock.Hash),
+			zap.Int("dropped", len(rpcBlock.Anomalies)))
+		return nil
+	}
+
+	if err := saveDecodeAnomalies(ctx, db, block.Hash, nil, rpcBlock.Anomalies); err != nil {
+		return fmt.Errorf("block %s: %w", block.Hash, err)
+	}
 	return nil
 }
 
-// IndexBlockRange indexes a range of blocks
-func (bi *BlockIndexer) IndexBlockRange(ctx context.Context, from, to *big.Int) error {
+// withRepos returns a copy of the indexer that writes through the unit of work's repositories
+func (bi *BlockIndexer) withRepos(repos interfaces.Repos) *BlockIndexer {
+	bound := *bi
+	bound.db = repos.Blocks
+	bound.txDB = repos.Transactions
+	if bi.anomalyDB != nil {
+		bound.anomalyDB = repos.Anomalies
+	}
+	if bi.reorgs != nil {
+		bound.reorgs = bi.reorgs.withRepos(repos)
+	}
+	return &bound
+}
+
+// IndexBlockRange indexes a range of blocks and returns the hash of the block saved at to.
+// With a store and a batch size configured, blocks are written in bulk batches
+// in ascending order; otherwise each block is indexed on its own.
+func (bi *BlockIndexer) IndexBlockRange(ctx context.Context, from, to *big.Int) (domain.Hash, error) {
+	if bi.store != nil && bi.batchSize > 0 {
+		return bi.indexBlockRangeBulk(ctx, from.Int64(), to.Int64())
+	}
+
 	blockCount := new(big.Int).Sub(to, from).Int64() + 1
 
 	// Create worker pool
-	workerCount := 10
+	workerCount := bi.workers
 	blockChan := make(chan *big.Int, blockCount)
 	errChan := make(chan error, blockCount)
 
 	var wg sync.WaitGroup
+	var lastHash domain.Hash
 
 	// Start workers
 	for i := 0; i < workerCount; i++ {
@@ -106,8 +316,14 @@ func (bi *BlockIndexer) IndexBlockRange(ctx context.Context, from, to *big.Int)
 		go func() {
 			defer wg.Done()
 			for blockNum := range blockChan {
-				if err := bi.IndexBlock(ctx, blockNum); err != nil {
+				hash, err := bi.IndexBlock(ctx, blockNum)
+				if err != nil {
 					errChan <- err
+					continue
+				}
+				// Only one worker receives to, and wg.Wait orders the write before the read
+				if blockNum.Cmp(to) == 0 {
+					lastHash = hash
 				}
 			}
 		}()
@@ -132,7 +348,7 @@ func (bi *BlockIndexer) IndexBlockRange(ctx context.Context, from, to *big.Int)
 	}
 
 	if len(errors) > 0 {
-		return fmt.Errorf("indexed %d blocks with %d errors: %v",
+		return "", fmt.Errorf("indexed %d blocks with %d errors: %v",
 			blockCount, len(errors), errors[0])
 	}
 
@@ -140,63 +356,294 @@ func (bi *BlockIndexer) IndexBlockRange(ctx context.Context, from, to *big.Int)
 		zap.String("from", from.String()),
 		zap.String("to", to.String()))
 
-	return nil
+	return lastHash, nil
 }
 
-// convertRPCBlockToDomain converts interfaces.Block to domain.Block
-func (bi *BlockIndexer) convertRPCBlockToDomain(rpcBlock *interfaces.Block) *domain.Block {
+// indexBlockRangeBulk fetches blocks concurrently and writes them batchSize at a time
+func (bi *BlockIndexer) indexBlockRangeBulk(ctx context.Context, from, to int64) (domain.Hash, error) {
+	var lastHash domain.Hash
+	for start := from; start <= to; start += int64(bi.batchSize) {
+		end := start + int64(bi.batchSize) - 1
+		if end > to {
+			end = to
+		}
+
+		rpcBlocks, err := bi.fetchBlocks(ctx, start, end)
+		if err != nil {
+			return "", err
+		}
+
+		for _, rpcBlock := range rpcBlocks {
+			if err := bi.fetchTransactions(ctx, rpcBlock); err != nil {
+				return "", err
+			}
+		}
+
+		blocks, err := bi.writeBatch(ctx, rpcBlocks)
+		if err != nil {
+			bi.logger.Error("failed to write block batch",
+				zap.Int64("from", start),
+				zap.Int64("to", end),
+				zap.Error(err))
+			return "", fmt.Errorf("write blocks %d-%d: %w", start, end, err)
+		}
+		lastHash = blocks[len(blocks)-1].Hash
+
+		bi.logger.Info("block batch indexed",
+			zap.Int64("from", start),
+			zap.Int64("to", end))
+	}
+
+	return lastHash, nil
+}
+
+// fetchBlocks fetches blocks from..to in one batch or using the worker pool, preserving order
+func (bi *BlockIndexer) fetchBlocks(ctx context.Context, from, to int64) ([]*interfaces.Block, error) {
+	if bi.batchRPC != nil {
+		return bi.fetchBlocksBatch(ctx, from, to)
+	}
+
+	return fetchAll(int(to-from+1), bi.workers, func(i int) (*interfaces.Block, error) {
+		return bi.fetchBlock(ctx, big.NewInt(from+int64(i)))
+	})
+}
+
+// fetchBlocksBatch fetches blocks from..to with a single batched RPC request
+func (bi *BlockIndexer) fetchBlocksBatch(ctx context.Context, from, to int64) ([]*interfaces.Block, error) {
+	numbers := make([]*big.Int, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		numbers = append(numbers, big.NewInt(n))
+	}
+
+	blocks, err := bi.batchRPC.GetBlocksByNumber(ctx, numbers, bi.fullTx())
+	if err != nil {
+		bi.logger.Error("failed to fetch blocks",
+			zap.Int64("from", from),
+			zap.Int64("to", to),
+			zap.Error(err))
+		return nil, fmt.Errorf("fetch blocks %d-%d: %w", from, to, err)
+	}
+
+	for i, block := range blocks {
+		if block == nil {
+			return nil, fmt.Errorf("block %s not found", numbers[i])
+		}
+	}
+
+	return blocks, nil
+}
+
+// writeBatch bulk-writes blocks and runs hooks and chain reconciliation in one unit of work,
+// returning the blocks as saved. Logs saved by hooks are bulk-written after the batch's hooks.
+func (bi *BlockIndexer) writeBatch(ctx context.Context, rpcBlocks []*interfaces.Block) ([]*domain.Block, error) {
+	blocks := make([]*domain.Block, 0, len(rpcBlocks))
+	var tip *domain.Block
+	for _, rpcBlock := range rpcBlocks {
+		block, err := bi.convertRPCBlockToDomain(rpcBlock)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block %d: %w", rpcBlock.Number, err)
+		}
+		if err := block.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid block %d: %w", block.Number, err)
+		}
+		blocks = append(blocks, block)
+
+		if block.IsChainBlock {
+			tip = block
+		}
+	}
+
+	err := bi.store.WithTx(ctx, func(repos interfaces.Repos) error {
+		if err := repos.Bulk.WriteBatch(ctx, blocks); err != nil {
+			return err
+		}
+
+		batch := &logBatch{}
+		hookRepos := batch.bind(repos)
+
+		for i, rpcBlock := range rpcBlocks {
+			anomalyDB := bi.anomalyDB
+			if anomalyDB != nil {
+				anomalyDB = repos.Anomalies
+			}
+			if err := bi.saveAnomalies(ctx, anomalyDB, blocks[i], rpcBlock); err != nil {
+				return err
+			}
+
+			for _, hook := range bi.hooks {
+				if err := hook(ctx, hookRepos, rpcBlock); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := batch.flush(ctx, repos.Bulk); err != nil {
+			return err
+		}
+
+		// Blocks in the batch are already consistent with each other;
+		// reconciling the highest chain block catches up with what was indexed before
+		if bi.reorgs != nil && tip != nil {
+			if _, err := bi.reorgs.withRepos(repos).HandleChainBlock(ctx, tip); err != nil {
+				return fmt.Errorf("handle chain change: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// convertRPCBlockToDomain converts interfaces.Block to domain.Block,
+// normalizing hashes and addresses to canonical form
+func (bi *BlockIndexer) convertRPCBlockToDomain(rpcBlock *interfaces.Block) (*domain.Block, error) {
+	hash, err := domain.ParseHash(rpcBlock.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("block hash %q: %w", rpcBlock.Hash, err)
+	}
+
+	parentHashes, err := domain.ParseHashes(rpcBlock.ParentHashes)
+	if err != nil {
+		return nil, fmt.Errorf("parent hashes: %w", err)
+	}
+
+	var miner domain.Address
+	if rpcBlock.Miner != "" {
+		if miner, err = domain.ParseAddress(rpcBlock.Miner); err != nil {
+			return nil, fmt.Errorf("miner %q: %w", rpcBlock.Miner, err)
+		}
+	}
+
+	selectedParent, err := parseOptionalHash(rpcBlock.SelectedParent)
+	if err != nil {
+		return nil, fmt.Errorf("selected parent: %w", err)
+	}
+
+	pruningPoint, err := parseOptionalHash(rpcBlock.PruningPoint)
+	if err != nil {
+		return nil, fmt.Errorf("pruning point: %w", err)
+	}
+
 	transactions := make([]domain.Transaction, 0, len(rpcBlock.Transactions))
-	for _, rpcTx := range rpcBlock.Transactions {
-		tx := bi.convertRPCTransactionToDomain(rpcTx, rpcBlock.Hash, rpcBlock.Number)
+	for i, rpcTx := range rpcBlock.Transactions {
+		index := i
+		if rpcTx.Index != nil {
+			index = int(*rpcTx.Index)
+		}
+		tx, err := bi.convertRPCTransactionToDomain(rpcTx, hash, rpcBlock, index)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
 		transactions = append(transactions, *tx)
 	}
 
 	return &domain.Block{
-		Hash:           rpcBlock.Hash,
-		Number:         rpcBlock.Number,
-		ParentHashes:   rpcBlock.ParentHashes,
-		Timestamp:      rpcBlock.Timestamp,
-		Miner:          rpcBlock.Miner,
-		GasLimit:       rpcBlock.GasLimit,
-		GasUsed:        rpcBlock.GasUsed,
-		BaseFeePerGas:  rpcBlock.BaseFeePerGas,
-		BlueScore:      rpcBlock.BlueScore,
-		IsChainBlock:   rpcBlock.IsChainBlock,
-		SelectedParent: rpcBlock.SelectedParent,
-		Transactions:   transactions,
-	}
-}
-
-// convertRPCTransactionToDomain converts interfaces.Transaction to domain.Transaction
+		Hash:             hash,
+		Number:           rpcBlock.Number,
+		ParentHashes:     parentHashes,
+		Timestamp:        rpcBlock.Timestamp,
+		Miner:            miner,
+		GasLimit:         rpcBlock.GasLimit,
+		GasUsed:          rpcBlock.GasUsed,
+		BaseFeePerGas:    rpcBlock.BaseFeePerGas,
+		BlueScore:        rpcBlock.BlueScore,
+		IsChainBlock:     rpcBlock.IsChainBlock,
+		SelectedParent:   selectedParent,
+		TransactionsRoot: rpcBlock.TransactionsRoot,
+		StateRoot:        rpcBlock.StateRoot,
+		ReceiptsRoot:     rpcBlock.ReceiptsRoot,
+		Size:             rpcBlock.Size,
+		Difficulty:       rpcBlock.Difficulty,
+		Nonce:            rpcBlock.Nonce,
+		ExtraData:        rpcBlock.ExtraData,
+		LogsBloom:        rpcBlock.LogsBloom,
+		MixHash:          rpcBlock.MixHash,
+		BlueWork:         rpcBlock.BlueWork,
+		DAAScore:         rpcBlock.DAAScore,
+		PruningPoint:     pruningPoint,
+		Transactions:     transactions,
+		TransactionCount: rpcBlock.TransactionCount,
+	}, nil
+}
+
+// convertRPCTransactionToDomain converts interfaces.Transaction to domain.Transaction.
+// blockHash is the block's canonical hash and index the transaction's position in it.
 func (bi *BlockIndexer) convertRPCTransactionToDomain(
 	rpcTx interfaces.Transaction,
-	blockHash string,
-	blockNumber int64,
-) *domain.Transaction {
-	var value uint64
-	if rpcTx.Value != nil {
-		value = rpcTx.Value.Uint64()
+	blockHash domain.Hash,
+	block *interfaces.Block,
+	index int,
+) (*domain.Transaction, error) {
+	hash, err := domain.ParseHash(rpcTx.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("hash %q: %w", rpcTx.Hash, err)
+	}
+
+	from, err := domain.ParseAddress(rpcTx.From)
+	if err != nil {
+		return nil, fmt.Errorf("from %q: %w", rpcTx.From, err)
+	}
+
+	to, err := domain.ParseOptionalAddress(rpcTx.To)
+	if err != nil {
+		return nil, fmt.Errorf("to %q: %w", *rpcTx.To, err)
+	}
+
+	value := rpcTx.Value
+	if value == nil {
+		value = new(big.Int)
 	}
 
-	var gasPrice *uint64
-	if rpcTx.GasPrice != nil {
-		gp := rpcTx.GasPrice.Uint64()
-		gasPrice = &gp
+	var accessList []domain.AccessTuple
+	for _, tuple := range rpcTx.AccessList {
+		address, err := domain.ParseAddress(tuple.Address)
+		if err != nil {
+			return nil, fmt.Errorf("access list address %q: %w", tuple.Address, err)
+		}
+		accessList = append(accessList, domain.AccessTuple{
+			Address:     address,
+			StorageKeys: tuple.StorageKeys,
+		})
 	}
 
-	return &domain.Transaction{
-		Hash:             rpcTx.Hash,
-		BlockHash:        blockHash,
-		BlockNumber:      blockNumber,
-		TransactionIndex: 0, // Will be set by database
-		From:             rpcTx.From,
-		To:               rpcTx.To,
-		Value:            value,
-		GasLimit:         rpcTx.Gas,
-		GasPrice:         gasPrice,
-		Nonce:            rpcTx.Nonce,
-		Input:            rpcTx.Input,
-		CreatesContract: rpcTx.To == nil,
+	tx := &domain.Transaction{
+		Hash:                 hash,
+		BlockHash:            blockHash,
+		BlockNumber:          block.Number,
+		TransactionIndex:     index,
+		Timestamp:            block.Timestamp,
+		From:                 from,
+		To:                   to,
+		Value:                value,
+		GasLimit:             rpcTx.Gas,
+		GasPrice:             rpcTx.GasPrice,
+		Nonce:                rpcTx.Nonce,
+		Input:                rpcTx.Input,
+		CreatesContract:      rpcTx.To == nil,
+		Type:                 rpcTx.Type,
+		ChainID:              rpcTx.ChainID,
+		MaxFeePerGas:         rpcTx.MaxFeePerGas,
+		MaxPriorityFeePerGas: rpcTx.MaxPriorityFeePerGas,
+		AccessList:           accessList,
+		MaxFeePerBlobGas:     rpcTx.MaxFeePerBlobGas,
+		BlobVersionedHashes:  rpcTx.BlobVersionedHashes,
+		V:                    rpcTx.V,
+		R:                    rpcTx.R,
+		S:                    rpcTx.S,
 	}
+	tx.EffectiveGasPrice = tx.ComputeEffectiveGasPrice(block.BaseFeePerGas)
+
+	return tx, nil
 }
 
+// parseOptionalHash parses a hash the node may leave empty
+func parseOptionalHash(s string) (domain.Hash, error) {
+	if s == "" {
+		return "", nil
+	}
+	return domain.ParseHash(s)
+}
@@ -2,12 +2,17 @@ package indexer_test
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
@@ -62,17 +67,364 @@ func TestBlockIndexer_IndexBlock(t *testing.T) {
 	})
 
 	// Execute
-	err := idx.IndexBlock(ctx, blockNum)
+	hash, err := idx.IndexBlock(ctx, blockNum)
 
 	// Assert
 	if !assert.NoError(t, err) {
 		return
 	}
+	assert.Equal(t, domain.Hash(expectedRPCBlock.Hash), hash)
 	mockRPC.AssertExpectations(t)
 	mockBlockWriter.AssertExpectations(t)
 	mockTxWriter.AssertExpectations(t)
 }
 
+func TestBlockIndexer_IndexBlock_HeaderFields(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+
+	header := &interfaces.Block{
+		Hash:             "0x" + strings.Repeat("a", 64),
+		Number:           100,
+		Timestamp:        1706150400,
+		TransactionsRoot: "0x" + strings.Repeat("1", 64),
+		StateRoot:        "0x" + strings.Repeat("2", 64),
+		ReceiptsRoot:     "0x" + strings.Repeat("3", 64),
+		Size:             544,
+		Difficulty:       big.NewInt(10000000000),
+		Nonce:            "0x0000000000000042",
+		ExtraData:        []byte{0x00, 0xff},
+		LogsBloom:        "0x" + strings.Repeat("0", 512),
+		MixHash:          "0x" + strings.Repeat("4", 64),
+		BlueWork:         big.NewInt(123456789),
+		DAAScore:         500,
+		PruningPoint:     "0x" + strings.Repeat("5", 64),
+	}
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).Return(header, nil)
+
+	mockBlockWriter.On("SaveBlock", ctx, mock.MatchedBy(func(b *domain.Block) bool {
+		return b.TransactionsRoot == header.TransactionsRoot &&
+			b.StateRoot == header.StateRoot &&
+			b.ReceiptsRoot == header.ReceiptsRoot &&
+			b.Size == header.Size &&
+			b.Difficulty == header.Difficulty &&
+			b.Nonce == header.Nonce &&
+			string(b.ExtraData) == string(header.ExtraData) &&
+			b.LogsBloom == header.LogsBloom &&
+			b.MixHash == header.MixHash &&
+			b.BlueWork == header.BlueWork &&
+			b.DAAScore == header.DAAScore &&
+			b.PruningPoint.String() == header.PruningPoint
+	})).Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:  mockRPC,
+		DB:   mockBlockWriter,
+		TxDB: new(mocks.MockTransactionWriter),
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.NoError(t, err)
+	mockBlockWriter.AssertExpectations(t)
+}
+
+func TestBlockIndexer_IndexBlock_FullPrecisionAmounts(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+
+	// 100 coins does not fit in a uint64 of wei
+	value, _ := new(big.Int).SetString("100000000000000000000", 10)
+	gasPrice, _ := new(big.Int).SetString("18446744073709551617", 10)
+	baseFee, _ := new(big.Int).SetString("18446744073709551616", 10)
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).
+		Return(&interfaces.Block{
+			Hash:          "0x" + strings.Repeat("a", 64),
+			Number:        100,
+			Timestamp:     1706150400000,
+			BaseFeePerGas: baseFee,
+			Transactions: []interfaces.Transaction{
+				{
+					Hash:     "0x" + strings.Repeat("c", 64),
+					From:     "0x" + strings.Repeat("d", 40),
+					Value:    value,
+					GasPrice: gasPrice,
+				},
+			},
+		}, nil)
+
+	mockBlockWriter.On("SaveBlock", ctx, mock.MatchedBy(func(b *domain.Block) bool {
+		return b.BaseFeePerGas.Cmp(baseFee) == 0
+	})).Return(nil)
+	mockTxWriter.On("SaveTransaction", ctx, mock.MatchedBy(func(tx *domain.Transaction) bool {
+		return tx.Value.Cmp(value) == 0 && tx.GasPrice.Cmp(gasPrice) == 0
+	})).Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:  mockRPC,
+		DB:   mockBlockWriter,
+		TxDB: mockTxWriter,
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.NoError(t, err)
+	mockBlockWriter.AssertExpectations(t)
+	mockTxWriter.AssertExpectations(t)
+}
+
+func TestBlockIndexer_IndexBlock_TypedTransactions(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+	accessAddress := "0x" + strings.Repeat("e", 40)
+	storageKey := "0x" + strings.Repeat("0", 64)
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).
+		Return(&interfaces.Block{
+			Hash:          "0x" + strings.Repeat("a", 64),
+			Number:        100,
+			Timestamp:     1706150400,
+			BaseFeePerGas: big.NewInt(10),
+			Transactions: []interfaces.Transaction{
+				{
+					Hash:     "0x" + strings.Repeat("b", 64),
+					From:     "0x" + strings.Repeat("d", 40),
+					GasPrice: big.NewInt(15),
+				},
+				{
+					Hash:                 "0x" + strings.Repeat("c", 64),
+					From:                 "0x" + strings.Repeat("d", 40),
+					Type:                 domain.TxTypeDynamicFee,
+					ChainID:              big.NewInt(1440),
+					MaxFeePerGas:         big.NewInt(100),
+					MaxPriorityFeePerGas: big.NewInt(2),
+					AccessList:           []interfaces.AccessTuple{{Address: accessAddress, StorageKeys: []string{storageKey}}},
+					V:                    big.NewInt(1),
+				},
+			},
+		}, nil)
+
+	mockBlockWriter.On("SaveBlock", ctx, mock.Anything).Return(nil)
+	mockTxWriter.On("SaveTransaction", ctx, mock.MatchedBy(func(tx *domain.Transaction) bool {
+		return tx.Type == domain.TxTypeLegacy && tx.EffectiveGasPrice.Cmp(big.NewInt(15)) == 0
+	})).Return(nil)
+	mockTxWriter.On("SaveTransaction", ctx, mock.MatchedBy(func(tx *domain.Transaction) bool {
+		return tx.Type == domain.TxTypeDynamicFee &&
+			tx.ChainID.Cmp(big.NewInt(1440)) == 0 &&
+			tx.EffectiveGasPrice.Cmp(big.NewInt(12)) == 0 &&
+			len(tx.AccessList) == 1 &&
+			tx.AccessList[0].Address.String() == accessAddress &&
+			tx.V.Cmp(big.NewInt(1)) == 0
+	})).Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:  mockRPC,
+		DB:   mockBlockWriter,
+		TxDB: mockTxWriter,
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.NoError(t, err)
+	mockTxWriter.AssertNumberOfCalls(t, "SaveTransaction", 2)
+	mockTxWriter.AssertExpectations(t)
+}
+
+func TestBlockIndexer_IndexBlock_NormalizesHashesAndAddresses(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+	recipient := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" // EIP-55 checksummed
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).
+		Return(&interfaces.Block{
+			Hash:           "0x" + strings.Repeat("A", 64),
+			Number:         100,
+			Timestamp:      1706150400,
+			ParentHashes:   []string{"0x" + strings.Repeat("B", 64)},
+			SelectedParent: "0x" + strings.Repeat("B", 64),
+			Miner:          "0x" + strings.Repeat("C", 40),
+			Transactions: []interfaces.Transaction{
+				{
+					Hash:     "0x" + strings.Repeat("D", 64),
+					From:     "0x" + strings.Repeat("E", 40),
+					To:       &recipient,
+					GasPrice: big.NewInt(1),
+				},
+			},
+		}, nil)
+
+	mockBlockWriter.On("SaveBlock", ctx, mock.MatchedBy(func(b *domain.Block) bool {
+		return b.Hash == domain.Hash("0x"+strings.Repeat("a", 64)) &&
+			b.ParentHashes[0] == domain.Hash("0x"+strings.Repeat("b", 64)) &&
+			b.SelectedParent == b.ParentHashes[0] &&
+			b.Miner == domain.Address("0x"+strings.Repeat("c", 40))
+	})).Return(nil)
+	mockTxWriter.On("SaveTransaction", ctx, mock.MatchedBy(func(tx *domain.Transaction) bool {
+		return tx.Hash == domain.Hash("0x"+strings.Repeat("d", 64)) &&
+			tx.BlockHash == domain.Hash("0x"+strings.Repeat("a", 64)) &&
+			tx.From == domain.Address("0x"+strings.Repeat("e", 40)) &&
+			*tx.To == domain.Address(strings.ToLower(recipient)) &&
+			tx.To.Checksum() == recipient
+	})).Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:  mockRPC,
+		DB:   mockBlockWriter,
+		TxDB: mockTxWriter,
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.NoError(t, err)
+	mockBlockWriter.AssertExpectations(t)
+	mockTxWriter.AssertExpectations(t)
+}
+
+func TestBlockIndexer_IndexBlock_InvalidAddress(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).
+		Return(&interfaces.Block{
+			Hash:      "0x" + strings.Repeat("a", 64),
+			Number:    100,
+			Timestamp: 1706150400,
+			Transactions: []interfaces.Transaction{
+				{Hash: "0x" + strings.Repeat("b", 64), From: "0x1234"},
+			},
+		}, nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:  mockRPC,
+		DB:   mockBlockWriter,
+		TxDB: new(mocks.MockTransactionWriter),
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+	mockBlockWriter.AssertNotCalled(t, "SaveBlock", mock.Anything, mock.Anything)
+}
+
+func TestBlockIndexer_IndexBlock_TransactionPositionAndTimestamp(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).
+		Return(&interfaces.Block{
+			Hash:      "0x" + strings.Repeat("a", 64),
+			Number:    100,
+			Timestamp: 1706150400000,
+			Transactions: []interfaces.Transaction{
+				{Hash: "0x" + strings.Repeat("c", 64), From: "0x" + strings.Repeat("d", 40)},
+				{Hash: "0x" + strings.Repeat("e", 64), From: "0x" + strings.Repeat("d", 40)},
+			},
+		}, nil)
+	mockBlockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).Return(nil)
+
+	var saved []*domain.Transaction
+	mockTxWriter.On("SaveTransaction", ctx, mock.AnythingOfType("*domain.Transaction")).
+		Run(func(args mock.Arguments) {
+			saved = append(saved, args.Get(1).(*domain.Transaction))
+		}).
+		Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:  mockRPC,
+		DB:   mockBlockWriter,
+		TxDB: mockTxWriter,
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.NoError(t, err)
+	if assert.Len(t, saved, 2) {
+		for i, tx := range saved {
+			assert.Equal(t, i, tx.TransactionIndex)
+			assert.Equal(t, int64(1706150400000), tx.Timestamp)
+			assert.Equal(t, domain.Hash("0x"+strings.Repeat("a", 64)), tx.BlockHash)
+		}
+	}
+}
+
+func TestBlockIndexer_IndexBlock_DroppedTransactionKeepsPositions(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+	first, third := uint64(0), uint64(2)
+
+	// Lenient decoding dropped the transaction at position 1
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).
+		Return(&interfaces.Block{
+			Hash:      "0x" + strings.Repeat("a", 64),
+			Number:    100,
+			Timestamp: 1706150400000,
+			Transactions: []interfaces.Transaction{
+				{Hash: "0x" + strings.Repeat("c", 64), From: "0x" + strings.Repeat("d", 40), Index: &first},
+				{Hash: "0x" + strings.Repeat("e", 64), From: "0x" + strings.Repeat("d", 40), Index: &third},
+			},
+			TransactionCount: 3,
+			Anomalies:        []interfaces.DecodeAnomaly{{Kind: interfaces.DecodeKindTransaction, Index: 1}},
+		}, nil)
+
+	var block *domain.Block
+	mockBlockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).
+		Run(func(args mock.Arguments) {
+			block = args.Get(1).(*domain.Block)
+		}).
+		Return(nil)
+
+	var saved []*domain.Transaction
+	mockTxWriter.On("SaveTransaction", ctx, mock.AnythingOfType("*domain.Transaction")).
+		Run(func(args mock.Arguments) {
+			saved = append(saved, args.Get(1).(*domain.Transaction))
+		}).
+		Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:  mockRPC,
+		DB:   mockBlockWriter,
+		TxDB: mockTxWriter,
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.NoError(t, err)
+	if assert.Len(t, saved, 2) {
+		assert.Equal(t, 0, saved[0].TransactionIndex)
+		assert.Equal(t, 2, saved[1].TransactionIndex)
+	}
+	if assert.NotNil(t, block) {
+		assert.Equal(t, 3, block.CountTransactions())
+	}
+}
+
 func TestBlockIndexer_IndexBlock_RPCFailure(t *testing.T) {
 	mockRPC := new(mocks.MockPhoenixClient)
 	mockBlockWriter := new(mocks.MockBlockWriter)
@@ -92,7 +444,7 @@ func TestBlockIndexer_IndexBlock_RPCFailure(t *testing.T) {
 		Logger: nil,
 	})
 
-	err := idx.IndexBlock(ctx, blockNum)
+	_, err := idx.IndexBlock(ctx, blockNum)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "fetch block")
@@ -126,7 +478,7 @@ func TestBlockIndexer_IndexBlock_DatabaseFailure(t *testing.T) {
 		Logger: nil,
 	})
 
-	err := idx.IndexBlock(ctx, blockNum)
+	_, err := idx.IndexBlock(ctx, blockNum)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "save block")
@@ -155,7 +507,7 @@ func TestBlockIndexer_IndexBlock_InvalidBlock(t *testing.T) {
 		Logger: nil,
 	})
 
-	err := idx.IndexBlock(ctx, blockNum)
+	_, err := idx.IndexBlock(ctx, blockNum)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid block")
@@ -173,7 +525,7 @@ func TestBlockIndexer_IndexBlockRange(t *testing.T) {
 	// Setup expectations for 3 blocks
 	for i := int64(100); i <= 102; i++ {
 		rpcBlock := &interfaces.Block{
-			Hash:      "0x" + strings.Repeat("a", 64),
+			Hash:      "0x" + strings.Repeat(fmt.Sprint(i-100), 64),
 			Number:    i,
 			Timestamp: 1706150400000 + (i-100)*1000,
 		}
@@ -189,9 +541,10 @@ func TestBlockIndexer_IndexBlockRange(t *testing.T) {
 		Logger: nil,
 	})
 
-	err := idx.IndexBlockRange(ctx, fromBlock, toBlock)
+	hash, err := idx.IndexBlockRange(ctx, fromBlock, toBlock)
 
 	assert.NoError(t, err)
+	assert.Equal(t, domain.Hash("0x"+strings.Repeat("2", 64)), hash, "the hash of the last block is returned")
 	mockRPC.AssertExpectations(t)
 	mockBlockWriter.AssertExpectations(t)
 }
@@ -200,3 +553,520 @@ func stringPtr(s string) *string {
 	return &s
 }
 
+// txRepos builds unit-of-work repositories backed by the given writers.
+// Read methods are left nil; the indexer must not call them.
+func txRepos(blocks *mocks.MockBlockWriter, txs *mocks.MockTransactionWriter) interfaces.Repos {
+	return interfaces.Repos{
+		Blocks: struct {
+			interfaces.BlockReader
+			interfaces.ChainBlockReader
+			interfaces.DAGGapReader
+			*mocks.MockBlockWriter
+		}{MockBlockWriter: blocks},
+		Transactions: struct {
+			interfaces.TransactionReader
+			interfaces.TransactionAcceptanceWriter
+			interfaces.TransactionInclusionReader
+			interfaces.TransactionInclusionWriter
+			*mocks.MockTransactionWriter
+		}{MockTransactionWriter: txs},
+	}
+}
+
+func TestBlockIndexer_IndexBlock_WithStore(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+	txBlockWriter := new(mocks.MockBlockWriter)
+	txTxWriter := new(mocks.MockTransactionWriter)
+	store := &mocks.MockUnitOfWork{Repos: txRepos(txBlockWriter, txTxWriter)}
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+
+	rpcBlock := &interfaces.Block{
+		Hash:         "0x" + strings.Repeat("a", 64),
+		Number:       100,
+		ParentHashes: []string{"0x" + strings.Repeat("b", 64)},
+		Timestamp:    1706150400000,
+		Transactions: []interfaces.Transaction{
+			{Hash: "0x" + strings.Repeat("c", 64), From: "0x" + strings.Repeat("d", 40)},
+		},
+	}
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).Return(rpcBlock, nil)
+	store.On("WithTx", ctx).Return(nil)
+	txBlockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).Return(nil)
+	txTxWriter.On("SaveTransaction", ctx, mock.AnythingOfType("*domain.Transaction")).Return(nil)
+
+	var hooked *interfaces.Block
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:   mockRPC,
+		DB:    mockBlockWriter,
+		TxDB:  mockTxWriter,
+		Store: store,
+		Hooks: []indexer.BlockHook{
+			func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+				hooked = block
+				return nil
+			},
+		},
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.NoError(t, err)
+	assert.Same(t, rpcBlock, hooked)
+	txBlockWriter.AssertExpectations(t)
+	txTxWriter.AssertExpectations(t)
+	mockBlockWriter.AssertNotCalled(t, "SaveBlock", mock.Anything, mock.Anything)
+	mockTxWriter.AssertNotCalled(t, "SaveTransaction", mock.Anything, mock.Anything)
+}
+
+func TestBlockIndexer_IndexBlock_WithStore_TransactionFailure(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	txBlockWriter := new(mocks.MockBlockWriter)
+	txTxWriter := new(mocks.MockTransactionWriter)
+	store := &mocks.MockUnitOfWork{Repos: txRepos(txBlockWriter, txTxWriter)}
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).Return(&interfaces.Block{
+		Hash:         "0x" + strings.Repeat("a", 64),
+		Number:       100,
+		ParentHashes: []string{"0x" + strings.Repeat("b", 64)},
+		Timestamp:    1706150400000,
+		Transactions: []interfaces.Transaction{
+			{Hash: "0x" + strings.Repeat("c", 64), From: "0x" + strings.Repeat("d", 40)},
+		},
+	}, nil)
+	store.On("WithTx", ctx).Return(nil)
+	txBlockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).Return(nil)
+	txTxWriter.On("SaveTransaction", ctx, mock.AnythingOfType("*domain.Transaction")).
+		Return(assert.AnError)
+
+	hookCalled := false
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:   mockRPC,
+		Store: store,
+		Hooks: []indexer.BlockHook{
+			func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+				hookCalled = true
+				return nil
+			},
+		},
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, hookCalled)
+}
+
+func TestBlockIndexer_IndexBlock_WithStore_HookFailure(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	txBlockWriter := new(mocks.MockBlockWriter)
+	txTxWriter := new(mocks.MockTransactionWriter)
+	store := &mocks.MockUnitOfWork{Repos: txRepos(txBlockWriter, txTxWriter)}
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).Return(&interfaces.Block{
+		Hash:         "0x" + strings.Repeat("a", 64),
+		Number:       100,
+		ParentHashes: []string{"0x" + strings.Repeat("b", 64)},
+		Timestamp:    1706150400000,
+	}, nil)
+	store.On("WithTx", ctx).Return(nil)
+	txBlockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:   mockRPC,
+		Store: store,
+		Hooks: []indexer.BlockHook{
+			func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+				return assert.AnError
+			},
+		},
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+// trackingStore is a UnitOfWork that records how many transactions are open at once
+type trackingStore struct {
+	repos    interfaces.Repos
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (s *trackingStore) WithTx(ctx context.Context, fn func(repos interfaces.Repos) error) error {
+	s.mu.Lock()
+	s.inFlight++
+	if s.inFlight > s.peak {
+		s.peak = s.inFlight
+	}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	// Hold the transaction open long enough for workers to overlap
+	time.Sleep(5 * time.Millisecond)
+	return fn(s.repos)
+}
+
+func TestBlockIndexer_IndexBlockRange_Concurrent(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	txBlockWriter := new(mocks.MockBlockWriter)
+	txTxWriter := new(mocks.MockTransactionWriter)
+	store := &trackingStore{repos: txRepos(txBlockWriter, txTxWriter)}
+
+	ctx := context.Background()
+	workers := 4
+
+	for i := int64(1); i <= 40; i++ {
+		mockRPC.On("GetBlockByNumber", ctx, big.NewInt(i), true).
+			Return(&interfaces.Block{
+				Hash:      "0x" + strings.Repeat("a", 64),
+				Number:    i,
+				Timestamp: 1706150400000 + i*1000,
+				Transactions: []interfaces.Transaction{
+					{Hash: "0x" + strings.Repeat("c", 64), From: "0x" + strings.Repeat("d", 40)},
+				},
+			}, nil)
+	}
+	txBlockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).Return(nil)
+	txTxWriter.On("SaveTransaction", ctx, mock.AnythingOfType("*domain.Transaction")).Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:     mockRPC,
+		Store:   store,
+		Workers: workers,
+	})
+
+	_, err := idx.IndexBlockRange(ctx, big.NewInt(1), big.NewInt(40))
+
+	assert.NoError(t, err)
+	txBlockWriter.AssertNumberOfCalls(t, "SaveBlock", 40)
+	txTxWriter.AssertNumberOfCalls(t, "SaveTransaction", 40)
+	assert.Greater(t, store.peak, 1, "blocks should be indexed concurrently")
+	assert.LessOrEqual(t, store.peak, workers, "concurrency must not exceed the worker count")
+}
+
+func TestBlockIndexer_IndexBlockRange_Bulk(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBulk := new(mocks.MockBulkWriter)
+	store := &mocks.MockUnitOfWork{Repos: interfaces.Repos{Bulk: mockBulk}}
+
+	ctx := context.Background()
+
+	for i := int64(1); i <= 5; i++ {
+		mockRPC.On("GetBlockByNumber", ctx, big.NewInt(i), true).
+			Return(&interfaces.Block{
+				Hash:      "0x" + strings.Repeat(fmt.Sprint(i), 64),
+				Number:    i,
+				Timestamp: 1706150400000 + i*1000,
+			}, nil)
+	}
+	store.On("WithTx", ctx).Return(nil)
+
+	// Batches of two, each in ascending order
+	var batches [][]int64
+	mockBulk.On("WriteBatch", ctx, mock.AnythingOfType("[]*domain.Block")).
+		Run(func(args mock.Arguments) {
+			var numbers []int64
+			for _, block := range args.Get(1).([]*domain.Block) {
+				numbers = append(numbers, block.Number)
+			}
+			batches = append(batches, numbers)
+		}).
+		Return(nil)
+
+	hooked := 0
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:       mockRPC,
+		Store:     store,
+		BatchSize: 2,
+		Hooks: []indexer.BlockHook{
+			func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+				hooked++
+				return nil
+			},
+		},
+	})
+
+	hash, err := idx.IndexBlockRange(ctx, big.NewInt(1), big.NewInt(5))
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Hash("0x"+strings.Repeat("5", 64)), hash, "the hash of the last block is returned")
+	assert.Equal(t, [][]int64{{1, 2}, {3, 4}, {5}}, batches)
+	assert.Equal(t, 5, hooked)
+	store.AssertNumberOfCalls(t, "WithTx", 3)
+}
+
+func TestBlockIndexer_IndexBlockRange_BulkFetchFailure(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBulk := new(mocks.MockBulkWriter)
+	store := &mocks.MockUnitOfWork{Repos: interfaces.Repos{Bulk: mockBulk}}
+
+	ctx := context.Background()
+
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(1), true).
+		Return(&interfaces.Block{Hash: "0x" + strings.Repeat("a", 64), Number: 1, Timestamp: 1}, nil)
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(2), true).
+		Return(nil, assert.AnError)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:       mockRPC,
+		Store:     store,
+		BatchSize: 10,
+	})
+
+	_, err := idx.IndexBlockRange(ctx, big.NewInt(1), big.NewInt(2))
+
+	assert.ErrorIs(t, err, assert.AnError)
+	mockBulk.AssertNotCalled(t, "WriteBatch", mock.Anything, mock.Anything)
+}
+
+func TestBlockIndexer_IndexBlockRange_BulkLogs(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBulk := new(mocks.MockBulkWriter)
+	mockLogWriter := new(mocks.MockLogWriter)
+	logs := struct {
+		interfaces.LogReader
+		*mocks.MockLogWriter
+	}{MockLogWriter: mockLogWriter}
+	nested := &mocks.MockUnitOfWork{Repos: interfaces.Repos{Logs: logs}}
+	store := &mocks.MockUnitOfWork{Repos: interfaces.Repos{Bulk: mockBulk, Logs: logs, Nested: nested}}
+
+	ctx := context.Background()
+	kept := &domain.Log{TransactionHash: domain.Hash("0x" + strings.Repeat("c", 64)), LogIndex: 0}
+	rolledBack := &domain.Log{TransactionHash: domain.Hash("0x" + strings.Repeat("c", 64)), LogIndex: 1}
+
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(1), true).
+		Return(&interfaces.Block{Hash: "0x" + strings.Repeat("a", 64), Number: 1, Timestamp: 1}, nil)
+	store.On("WithTx", ctx).Return(nil)
+	nested.On("WithTx", ctx).Return(nil)
+	mockBulk.On("WriteBatch", ctx, mock.AnythingOfType("[]*domain.Block")).Return(nil)
+	mockBulk.On("WriteLogs", ctx, []*domain.Log{kept}).Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:       mockRPC,
+		Store:     store,
+		BatchSize: 10,
+		Hooks: []indexer.BlockHook{
+			func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+				return repos.Logs.SaveLog(ctx, kept)
+			},
+			// A skipped stage: its savepoint is rolled back, so its logs must not be written
+			func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+				_ = repos.Nested.WithTx(ctx, func(stageRepos interfaces.Repos) error {
+					if err := stageRepos.Logs.SaveLog(ctx, rolledBack); err != nil {
+						return err
+					}
+					return assert.AnError
+				})
+				return nil
+			},
+		},
+	})
+
+	_, err := idx.IndexBlockRange(ctx, big.NewInt(1), big.NewInt(1))
+
+	assert.NoError(t, err)
+	mockBulk.AssertExpectations(t)
+	mockLogWriter.AssertNotCalled(t, "SaveLog", mock.Anything, mock.Anything)
+}
+
+func TestBlockIndexer_IndexBlockRange_BulkBatchFetch(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBulk := new(mocks.MockBulkWriter)
+	store := &mocks.MockUnitOfWork{Repos: interfaces.Repos{Bulk: mockBulk}}
+
+	ctx := context.Background()
+
+	mockRPC.On("GetBlocksByNumber", ctx, []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, true).
+		Return([]*interfaces.Block{
+			{Hash: "0x" + strings.Repeat("a", 64), Number: 1, Timestamp: 1},
+			{Hash: "0x" + strings.Repeat("b", 64), Number: 2, Timestamp: 2},
+			{Hash: "0x" + strings.Repeat("c", 64), Number: 3, Timestamp: 3},
+		}, nil)
+	store.On("WithTx", ctx).Return(nil)
+	mockBulk.On("WriteBatch", ctx, mock.AnythingOfType("[]*domain.Block")).Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:       mockRPC,
+		BatchRPC:  mockRPC,
+		Store:     store,
+		BatchSize: 10,
+	})
+
+	_, err := idx.IndexBlockRange(ctx, big.NewInt(1), big.NewInt(3))
+
+	assert.NoError(t, err)
+	mockRPC.AssertNotCalled(t, "GetBlockByNumber", mock.Anything, mock.Anything, mock.Anything)
+	mockBulk.AssertNumberOfCalls(t, "WriteBatch", 1)
+}
+
+func TestBlockIndexer_IndexBlockRange_BulkBatchFetchMissingBlock(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBulk := new(mocks.MockBulkWriter)
+	store := &mocks.MockUnitOfWork{Repos: interfaces.Repos{Bulk: mockBulk}}
+
+	ctx := context.Background()
+
+	mockRPC.On("GetBlocksByNumber", ctx, []*big.Int{big.NewInt(1), big.NewInt(2)}, true).
+		Return([]*interfaces.Block{
+			{Hash: "0x" + strings.Repeat("a", 64), Number: 1, Timestamp: 1},
+			nil,
+		}, nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:       mockRPC,
+		BatchRPC:  mockRPC,
+		Store:     store,
+		BatchSize: 10,
+	})
+
+	_, err := idx.IndexBlockRange(ctx, big.NewInt(1), big.NewInt(2))
+
+	assert.Error(t, err)
+	mockBulk.AssertNotCalled(t, "WriteBatch", mock.Anything, mock.Anything)
+}
+
+func TestBlockIndexer_IndexBlock_RecordsDecodeAnomalies(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+	mockAnomalyWriter := new(mocks.MockDecodeAnomalyWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+
+	rpcBlock := &interfaces.Block{
+		Hash:      "0x" + strings.Repeat("a", 64),
+		Number:    100,
+		Timestamp: 1706150400,
+		Anomalies: []interfaces.DecodeAnomaly{
+			{Kind: interfaces.DecodeKindTransaction, Index: 1, Raw: `{"hash":"0x1"}`, Reason: "missing gas"},
+		},
+	}
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, true).Return(rpcBlock, nil)
+	mockBlockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).Return(nil)
+	mockAnomalyWriter.On("SaveDecodeAnomaly", ctx, mock.MatchedBy(func(a *domain.DecodeAnomaly) bool {
+		return a.BlockHash.String() == rpcBlock.Hash &&
+			a.TransactionHash == nil &&
+			a.Kind == domain.AnomalyKindTransaction &&
+			a.ItemIndex == 1 &&
+			a.RawItem == `{"hash":"0x1"}` &&
+			a.Reason == "missing gas"
+	})).Return(nil).Once()
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:       mockRPC,
+		DB:        mockBlockWriter,
+		TxDB:      new(mocks.MockTransactionWriter),
+		AnomalyDB: mockAnomalyWriter,
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+	assert.NoError(t, err)
+	mockAnomalyWriter.AssertExpectations(t)
+}
+
+func TestBlockIndexer_IndexBlock_HashOnlyTransactions(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+	txHashes := []string{
+		"0x" + strings.Repeat("1", 64),
+		"0x" + strings.Repeat("2", 64),
+		"0x" + strings.Repeat("3", 64),
+	}
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, false).
+		Return(&interfaces.Block{
+			Hash:              "0x" + strings.Repeat("a", 64),
+			Number:            100,
+			ParentHashes:      []string{"0x" + strings.Repeat("b", 64)},
+			Timestamp:         1706150400000,
+			GasLimit:          30000000,
+			TransactionHashes: txHashes,
+		}, nil)
+	for i, hash := range txHashes {
+		mockRPC.On("GetTransactionByHash", ctx, common.HexToHash(hash)).
+			Return(&interfaces.Transaction{
+				Hash:  hash,
+				From:  "0x" + strings.Repeat("d", 40),
+				Gas:   21000,
+				Nonce: uint64(i),
+			}, nil)
+	}
+
+	mockBlockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).
+		Return(nil)
+
+	var saved []string
+	mockTxWriter.On("SaveTransaction", ctx, mock.AnythingOfType("*domain.Transaction")).
+		Run(func(args mock.Arguments) {
+			saved = append(saved, args.Get(1).(*domain.Transaction).Hash.String())
+		}).
+		Return(nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:       mockRPC,
+		TxRPC:     mockRPC,
+		TxWorkers: 2,
+		DB:        mockBlockWriter,
+		TxDB:      mockTxWriter,
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, txHashes, saved) // Block order, whatever order the fetches finished in
+	mockRPC.AssertExpectations(t)
+}
+
+func TestBlockIndexer_IndexBlock_HashOnlyTransactionNotFound(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlockWriter := new(mocks.MockBlockWriter)
+
+	ctx := context.Background()
+	blockNum := big.NewInt(100)
+	txHash := "0x" + strings.Repeat("1", 64)
+
+	mockRPC.On("GetBlockByNumber", ctx, blockNum, false).
+		Return(&interfaces.Block{
+			Hash:              "0x" + strings.Repeat("a", 64),
+			Number:            100,
+			TransactionHashes: []string{txHash},
+		}, nil)
+	mockRPC.On("GetTransactionByHash", ctx, common.HexToHash(txHash)).
+		Return(nil, nil)
+
+	idx := indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		RPC:   mockRPC,
+		TxRPC: mockRPC,
+		DB:    mockBlockWriter,
+	})
+
+	_, err := idx.IndexBlock(ctx, blockNum)
+
+	assert.ErrorContains(t, err, "not found")
+	mockBlockWriter.AssertNotCalled(t, "SaveBlock", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,190 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// DefaultCheckpointName is the checkpoint used by the main block sync loop
+const DefaultCheckpointName = "block_indexer"
+
+// defaultMaxRewind bounds how far Resume walks back looking for a block the node agrees with
+const defaultMaxRewind = 1000
+
+// CheckpointerDeps contains dependencies for Checkpointer (ISP)
+type CheckpointerDeps struct {
+	RPC       interfaces.BlockByNumberReader
+	Blocks    interfaces.BlockReader
+	Store     interfaces.CheckpointStore
+	Name      string
+	MaxRewind int64
+	Logger    *zap.Logger
+}
+
+// Checkpointer tracks the last fully indexed block so the indexer can resume after a restart
+type Checkpointer struct {
+	rpc       interfaces.BlockByNumberReader
+	blocks    interfaces.BlockReader
+	store     interfaces.CheckpointStore
+	name      string
+	maxRewind int64
+	logger    *zap.Logger
+}
+
+// NewCheckpointer creates a new Checkpointer
+func NewCheckpointer(deps CheckpointerDeps) *Checkpointer {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	name := deps.Name
+	if name == "" {
+		name = DefaultCheckpointName
+	}
+
+	maxRewind := deps.MaxRewind
+	if maxRewind <= 0 {
+		maxRewind = defaultMaxRewind
+	}
+
+	return &Checkpointer{
+		rpc:       deps.RPC,
+		blocks:    deps.Blocks,
+		store:     deps.Store,
+		name:      name,
+		maxRewind: maxRewind,
+		logger:    logger,
+	}
+}
+
+// Resume returns the number of the next block to index.
+// The stored block hash is compared against the node; if they disagree the
+// checkpoint is walked back along the indexed selected parent chain until the
+// indexed chain and the node agree again.
+func (c *Checkpointer) Resume(ctx context.Context) (int64, error) {
+	checkpoint, err := c.store.GetCheckpoint(ctx, c.name)
+	if err != nil {
+		return 0, fmt.Errorf("load checkpoint: %w", err)
+	}
+
+	if checkpoint == nil {
+		c.logger.Info("no checkpoint found, starting from genesis",
+			zap.String("name", c.name))
+		return 0, nil
+	}
+
+	resumed := *checkpoint
+
+	for rewound := int64(0); ; rewound++ {
+		matches, err := c.matchesNode(ctx, resumed.BlockNumber, resumed.BlockHash)
+		if err != nil {
+			return 0, err
+		}
+
+		if matches {
+			if rewound > 0 {
+				if err := c.save(ctx, resumed.BlockNumber, resumed.BlockHash); err != nil {
+					return 0, err
+				}
+				c.logger.Warn("checkpoint rewound after hash mismatch",
+					zap.String("name", c.name),
+					zap.Int64("from", checkpoint.BlockNumber),
+					zap.Int64("to", resumed.BlockNumber))
+			}
+
+			c.logger.Info("resuming from checkpoint",
+				zap.String("name", c.name),
+				zap.Int64("blockNumber", resumed.BlockNumber),
+				zap.String("blockHash", resumed.BlockHash.String()))
+			return resumed.NextBlock(), nil
+		}
+
+		if rewound >= c.maxRewind {
+			return 0, fmt.Errorf("checkpoint %s diverged from node for more than %d blocks",
+				c.name, c.maxRewind)
+		}
+
+		// Walk by hash: a lookup by number could return a DAG sibling stored at the same height
+		stored, err := c.blocks.GetBlockByHash(ctx, resumed.BlockHash)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Nothing indexed under this hash, so re-index from its height
+			return resumed.BlockNumber, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("load indexed block %s: %w", resumed.BlockHash, err)
+		}
+
+		parentHash := stored.SelectedParent
+		if parentHash == "" && len(stored.ParentHashes) > 0 {
+			parentHash = stored.ParentHashes[0]
+		}
+		if parentHash == "" {
+			c.logger.Warn("no indexed block matches the node, starting from genesis",
+				zap.String("name", c.name))
+			return 0, nil
+		}
+
+		parent, err := c.blocks.GetBlockByHash(ctx, parentHash)
+		if errors.Is(err, domain.ErrNotFound) {
+			// The selected parent was never indexed, so re-index below the mismatched block
+			return stored.Number - 1, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("load indexed block %s: %w", parentHash, err)
+		}
+
+		resumed.BlockNumber = parent.Number
+		resumed.BlockHash = parent.Hash
+	}
+}
+
+// Commit moves the checkpoint to the given block once all of its data is persisted.
+// The hash is the one the caller indexed at that number: a lookup by number could
+// return a DAG sibling stored at the same height.
+func (c *Checkpointer) Commit(ctx context.Context, number int64, hash domain.Hash) error {
+	return c.save(ctx, number, hash)
+}
+
+// matchesNode returns true if the node reports the given hash at the given height
+func (c *Checkpointer) matchesNode(ctx context.Context, number int64, hash domain.Hash) (bool, error) {
+	nodeBlock, err := c.rpc.GetBlockByNumber(ctx, big.NewInt(number), false)
+	if err != nil {
+		return false, fmt.Errorf("fetch block %d: %w", number, err)
+	}
+
+	if nodeBlock == nil {
+		c.logger.Warn("checkpoint block missing on node",
+			zap.String("name", c.name),
+			zap.Int64("blockNumber", number))
+		return false, nil
+	}
+
+	nodeHash, err := domain.ParseHash(nodeBlock.Hash)
+	if err != nil {
+		return false, fmt.Errorf("block %d hash %q: %w", number, nodeBlock.Hash, err)
+	}
+
+	return nodeHash == hash, nil
+}
+
+func (c *Checkpointer) save(ctx context.Context, number int64, hash domain.Hash) error {
+	checkpoint := &domain.Checkpoint{
+		Name:        c.name,
+		BlockNumber: number,
+		BlockHash:   hash,
+	}
+
+	if err := c.store.SaveCheckpoint(ctx, checkpoint); err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package indexer_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+func TestCheckpointer_Resume_NoCheckpoint(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockStore := new(mocks.MockCheckpointStore)
+
+	ctx := context.Background()
+
+	mockStore.On("GetCheckpoint", ctx, indexer.DefaultCheckpointName).
+		Return(nil, nil)
+
+	cp := indexer.NewCheckpointer(indexer.CheckpointerDeps{
+		RPC:    mockRPC,
+		Blocks: mockBlocks,
+		Store:  mockStore,
+	})
+
+	next, err := cp.Resume(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), next)
+	mockRPC.AssertNotCalled(t, "GetBlockByNumber", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckpointer_Resume_HashMatches(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockStore := new(mocks.MockCheckpointStore)
+
+	ctx := context.Background()
+	hash := domain.Hash("0x" + strings.Repeat("a", 64))
+
+	mockStore.On("GetCheckpoint", ctx, indexer.DefaultCheckpointName).
+		Return(&domain.Checkpoint{
+			Name:        indexer.DefaultCheckpointName,
+			BlockNumber: 100,
+			BlockHash:   hash,
+		}, nil)
+
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(100), false).
+		Return(&interfaces.Block{Hash: hash.String(), Number: 100}, nil)
+
+	cp := indexer.NewCheckpointer(indexer.CheckpointerDeps{
+		RPC:    mockRPC,
+		Blocks: mockBlocks,
+		Store:  mockStore,
+	})
+
+	next, err := cp.Resume(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(101), next)
+	mockStore.AssertNotCalled(t, "SaveCheckpoint", mock.Anything, mock.Anything)
+}
+
+func TestCheckpointer_Resume_HashMismatchRewinds(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockStore := new(mocks.MockCheckpointStore)
+
+	ctx := context.Background()
+	staleHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	nodeHash := "0x" + strings.Repeat("b", 64)
+	commonHash := domain.Hash("0x" + strings.Repeat("c", 64))
+
+	mockStore.On("GetCheckpoint", ctx, indexer.DefaultCheckpointName).
+		Return(&domain.Checkpoint{
+			Name:        indexer.DefaultCheckpointName,
+			BlockNumber: 100,
+			BlockHash:   staleHash,
+		}, nil)
+
+	// Node has a different block at 100 but agrees on 99
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(100), false).
+		Return(&interfaces.Block{Hash: nodeHash, Number: 100}, nil)
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(99), false).
+		Return(&interfaces.Block{Hash: commonHash.String(), Number: 99}, nil)
+
+	mockBlocks.On("GetBlockByHash", ctx, staleHash).
+		Return(&domain.Block{Hash: staleHash, Number: 100, SelectedParent: commonHash}, nil)
+	mockBlocks.On("GetBlockByHash", ctx, commonHash).
+		Return(&domain.Block{Hash: commonHash, Number: 99}, nil)
+
+	mockStore.On("SaveCheckpoint", ctx, &domain.Checkpoint{
+		Name:        indexer.DefaultCheckpointName,
+		BlockNumber: 99,
+		BlockHash:   commonHash,
+	}).Return(nil)
+
+	cp := indexer.NewCheckpointer(indexer.CheckpointerDeps{
+		RPC:    mockRPC,
+		Blocks: mockBlocks,
+		Store:  mockStore,
+	})
+
+	next, err := cp.Resume(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), next)
+	mockStore.AssertExpectations(t)
+	// A lookup by number could return a DAG sibling at the same height
+	mockBlocks.AssertNotCalled(t, "GetBlockByNumber", mock.Anything, mock.Anything)
+}
+
+func TestCheckpointer_Resume_RewindFollowsSelectedParent(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockStore := new(mocks.MockCheckpointStore)
+
+	ctx := context.Background()
+	staleHash := domain.Hash("0x" + strings.Repeat("a", 64))
+	nodeHash := "0x" + strings.Repeat("b", 64)
+	parentHash := domain.Hash("0x" + strings.Repeat("c", 64))
+
+	mockStore.On("GetCheckpoint", ctx, indexer.DefaultCheckpointName).
+		Return(&domain.Checkpoint{
+			Name:        indexer.DefaultCheckpointName,
+			BlockNumber: 100,
+			BlockHash:   staleHash,
+		}, nil)
+
+	// The selected parent sits two heights down; height 99 only holds a sibling
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(100), false).
+		Return(&interfaces.Block{Hash: nodeHash, Number: 100}, nil)
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(98), false).
+		Return(&interfaces.Block{Hash: parentHash.String(), Number: 98}, nil)
+
+	mockBlocks.On("GetBlockByHash", ctx, staleHash).
+		Return(&domain.Block{Hash: staleHash, Number: 100, SelectedParent: parentHash}, nil)
+	mockBlocks.On("GetBlockByHash", ctx, parentHash).
+		Return(&domain.Block{Hash: parentHash, Number: 98}, nil)
+
+	mockStore.On("SaveCheckpoint", ctx, &domain.Checkpoint{
+		Name:        indexer.DefaultCheckpointName,
+		BlockNumber: 98,
+		BlockHash:   parentHash,
+	}).Return(nil)
+
+	cp := indexer.NewCheckpointer(indexer.CheckpointerDeps{
+		RPC:    mockRPC,
+		Blocks: mockBlocks,
+		Store:  mockStore,
+	})
+
+	next, err := cp.Resume(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(99), next)
+	mockStore.AssertExpectations(t)
+	mockRPC.AssertNotCalled(t, "GetBlockByNumber", ctx, big.NewInt(99), false)
+}
+
+func TestCheckpointer_Resume_RewindLimitExceeded(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockStore := new(mocks.MockCheckpointStore)
+
+	ctx := context.Background()
+	nodeHash := "0x" + strings.Repeat("b", 64)
+
+	mockStore.On("GetCheckpoint", ctx, indexer.DefaultCheckpointName).
+		Return(&domain.Checkpoint{
+			Name:        indexer.DefaultCheckpointName,
+			BlockNumber: 100,
+			BlockHash:   domain.Hash("0x" + strings.Repeat("a", 64)),
+		}, nil)
+
+	mockRPC.On("GetBlockByNumber", ctx, mock.AnythingOfType("*big.Int"), false).
+		Return(&interfaces.Block{Hash: nodeHash}, nil)
+	mockBlocks.On("GetBlockByHash", ctx, mock.AnythingOfType("domain.Hash")).
+		Return(&domain.Block{
+			Hash:           domain.Hash("0x" + strings.Repeat("d", 64)),
+			SelectedParent: domain.Hash("0x" + strings.Repeat("d", 64)),
+		}, nil)
+
+	cp := indexer.NewCheckpointer(indexer.CheckpointerDeps{
+		RPC:       mockRPC,
+		Blocks:    mockBlocks,
+		Store:     mockStore,
+		MaxRewind: 3,
+	})
+
+	_, err := cp.Resume(ctx)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "diverged")
+}
+
+func TestCheckpointer_Resume_MissingIndexedBlock(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockStore := new(mocks.MockCheckpointStore)
+
+	ctx := context.Background()
+
+	mockStore.On("GetCheckpoint", ctx, indexer.DefaultCheckpointName).
+		Return(&domain.Checkpoint{
+			Name:        indexer.DefaultCheckpointName,
+			BlockNumber: 100,
+			BlockHash:   domain.Hash("0x" + strings.Repeat("a", 64)),
+		}, nil)
+
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(100), false).
+		Return(&interfaces.Block{Hash: "0x" + strings.Repeat("b", 64)}, nil)
+	mockBlocks.On("GetBlockByHash", ctx, domain.Hash("0x"+strings.Repeat("a", 64))).
+		Return(&domain.Block{
+			Hash:           domain.Hash("0x" + strings.Repeat("a", 64)),
+			Number:         100,
+			SelectedParent: domain.Hash("0x" + strings.Repeat("c", 64)),
+		}, nil)
+	mockBlocks.On("GetBlockByHash", ctx, domain.Hash("0x"+strings.Repeat("c", 64))).
+		Return(nil, fmt.Errorf("block %w: hash 0x%s", domain.ErrNotFound, strings.Repeat("c", 64)))
+
+	cp := indexer.NewCheckpointer(indexer.CheckpointerDeps{
+		RPC:    mockRPC,
+		Blocks: mockBlocks,
+		Store:  mockStore,
+	})
+
+	next, err := cp.Resume(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(99), next)
+}
+
+func TestCheckpointer_Commit(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockStore := new(mocks.MockCheckpointStore)
+
+	ctx := context.Background()
+	hash := domain.Hash("0x" + strings.Repeat("a", 64))
+
+	mockStore.On("SaveCheckpoint", ctx, &domain.Checkpoint{
+		Name:        indexer.DefaultCheckpointName,
+		BlockNumber: 42,
+		BlockHash:   hash,
+	}).Return(nil)
+
+	cp := indexer.NewCheckpointer(indexer.CheckpointerDeps{
+		RPC:    mockRPC,
+		Blocks: mockBlocks,
+		Store:  mockStore,
+	})
+
+	err := cp.Commit(ctx, 42, hash)
+
+	assert.NoError(t, err)
+	mockStore.AssertExpectations(t)
+	// A lookup by number could return a DAG sibling at the same height
+	mockBlocks.AssertNotCalled(t, "GetBlockByNumber", mock.Anything, mock.Anything)
+}
+
+func TestCheckpointer_Commit_StoreError(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockBlocks := new(mocks.MockBlockReader)
+	mockStore := new(mocks.MockCheckpointStore)
+
+	ctx := context.Background()
+	hash := domain.Hash("0x" + strings.Repeat("a", 64))
+
+	mockStore.On("SaveCheckpoint", ctx, mock.AnythingOfType("*domain.Checkpoint")).
+		Return(assert.AnError)
+
+	cp := indexer.NewCheckpointer(indexer.CheckpointerDeps{
+		RPC:    mockRPC,
+		Blocks: mockBlocks,
+		Store:  mockStore,
+	})
+
+	err := cp.Commit(ctx, 42, hash)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
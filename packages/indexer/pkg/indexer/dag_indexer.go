@@ -3,7 +3,6 @@ package indexer
 import (
 	"context"
 	"fmt"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"go.uber.org/zap"
@@ -14,18 +13,22 @@ import (
 // DAGIndexerDeps contains dependencies for DAGIndexer (ISP)
 type DAGIndexerDeps struct {
 	ParentsRPC  interfaces.BlockParentsReader
-	DAGInfoRPC  interfaces.DAGInfoReader
-	BlueScoreRPC interfaces.BlueScoreReader
+	GHOSTDAGRPC interfaces.BlockGHOSTDAGDataReader
 	DAGDB       interfaces.DAGWriter
+	BlockDB     interfaces.BlockWriter                 // Optional: copies GHOSTDAG scores onto the block row
+	InclusionDB interfaces.TransactionInclusionWriter  // Optional: settles inclusions accepted by the block's mergeset
+	AcceptDB    interfaces.TransactionAcceptanceWriter // Optional: accepts transactions of the blocks the mergeset merges
 	Logger      *zap.Logger
 }
 
 // DAGIndexer indexes DAG relationships and GHOSTDAG data
 type DAGIndexer struct {
 	parentsRPC  interfaces.BlockParentsReader
-	dagInfoRPC  interfaces.DAGInfoReader
-	blueScoreRPC interfaces.BlueScoreReader
+	ghostdagRPC interfaces.BlockGHOSTDAGDataReader
 	dagDB       interfaces.DAGWriter
+	blockDB     interfaces.BlockWriter
+	inclusionDB interfaces.TransactionInclusionWriter
+	acceptDB    interfaces.TransactionAcceptanceWriter
 	logger      *zap.Logger
 }
 
@@ -38,9 +41,11 @@ func NewDAGIndexer(deps DAGIndexerDeps) *DAGIndexer {
 
 	return &DAGIndexer{
 		parentsRPC:  deps.ParentsRPC,
-		dagInfoRPC:  deps.DAGInfoRPC,
-		blueScoreRPC: deps.BlueScoreRPC,
+		ghostdagRPC: deps.GHOSTDAGRPC,
 		dagDB:       deps.DAGDB,
+		blockDB:     deps.BlockDB,
+		inclusionDB: deps.InclusionDB,
+		acceptDB:    deps.AcceptDB,
 		logger:      logger,
 	}
 }
@@ -50,7 +55,7 @@ func NewDAGIndexer(deps DAGIndexerDeps) *DAGIndexer {
 func (di *DAGIndexer) IndexBlockDAGRelationships(
 	ctx context.Context,
 	blockHash common.Hash,
-	selectedParentHash string,
+	selectedParentHash domain.Hash,
 ) error {
 	// 1. Fetch parent hashes from RPC
 	parents, err := di.parentsRPC.GetBlockParents(ctx, blockHash)
@@ -68,8 +73,9 @@ func (di *DAGIndexer) IndexBlockDAGRelationships(
 	}
 
 	// 2. Save DAG relationships
+	// common.Hash renders lowercase hex, which is already canonical
 	for _, parentHash := range parents {
-		isSelectedParent := selectedParentHash != "" && parentHash.Hex() == selectedParentHash
+		isSelectedParent := selectedParentHash != "" && domain.Hash(parentHash.Hex()) == selectedParentHash
 		// If selectedParentHash not provided, first parent is assumed to be selected
 		if selectedParentHash == "" && parentHash.Hex() == parents[0].Hex() {
 			isSelectedParent = true
@@ -77,8 +83,8 @@ func (di *DAGIndexer) IndexBlockDAGRelationships(
 
 		if err := di.dagDB.SaveDAGRelationship(
 			ctx,
-			blockHash.Hex(),
-			parentHash.Hex(),
+			domain.Hash(blockHash.Hex()),
+			domain.Hash(parentHash.Hex()),
 			isSelectedParent,
 		); err != nil {
 			di.logger.Error("failed to save DAG relationship",
@@ -100,48 +106,120 @@ func (di *DAGIndexer) IndexBlockDAGRelationships(
 func (di *DAGIndexer) IndexGHOSTDAGData(
 	ctx context.Context,
 	blockHash common.Hash,
-	blockNumber *big.Int,
 ) error {
-	// 1. Fetch DAG info from RPC
-	dagInfo, err := di.dagInfoRPC.GetDAGInfo(ctx)
+	// 1. Fetch the block's own GHOSTDAG data from RPC
+	data, err := di.ghostdagRPC.GetBlockGHOSTDAGData(ctx, blockHash)
 	if err != nil {
-		di.logger.Error("failed to fetch DAG info",
+		di.logger.Error("failed to fetch GHOSTDAG data",
 			zap.String("blockHash", blockHash.Hex()),
 			zap.Error(err))
-		return fmt.Errorf("fetch DAG info: %w", err)
+		return fmt.Errorf("fetch GHOSTDAG data: %w", err)
 	}
 
-	// 2. Fetch blue score for the block
-	blueScore, err := di.blueScoreRPC.GetBlueScore(ctx, blockNumber)
-	if err != nil {
-		di.logger.Error("failed to fetch blue score",
-			zap.String("blockHash", blockHash.Hex()),
-			zap.Error(err))
-		return fmt.Errorf("fetch blue score: %w", err)
+	if data == nil {
+		return fmt.Errorf("GHOSTDAG data for block %s not found", blockHash.Hex())
 	}
 
-	// 3. Convert to domain.GHOSTDAGData
-	ghostDAGData := &domain.GHOSTDAGData{
-		BlockHash:      blockHash.Hex(),
-		BlueScore:      blueScore,
-		BlueWork:       dagInfo.BlueWork,
-		MergeSetBlues:  dagInfo.MergeSetBlues,
-		MergeSetReds:   dagInfo.MergeSetReds,
-		// SelectedParent will be set when indexing relationships
+	// 2. Convert to domain.GHOSTDAGData
+	ghostDAGData, err := convertRPCGHOSTDAGDataToDomain(domain.Hash(blockHash.Hex()), data)
+	if err != nil {
+		return fmt.Errorf("invalid GHOSTDAG data for block %s: %w", blockHash.Hex(), err)
 	}
 
-	// 4. Save to database
-	if err := di.dagDB.SaveGHOSTDAGData(ctx, blockHash.Hex(), ghostDAGData); err != nil {
+	// 3. Save to database, with the mergeset normalized one row per merged block
+	if err := di.dagDB.SaveGHOSTDAGData(ctx, ghostDAGData.BlockHash, ghostDAGData); err != nil {
 		di.logger.Error("failed to save GHOSTDAG data",
 			zap.String("blockHash", blockHash.Hex()),
 			zap.Error(err))
 		return fmt.Errorf("save GHOSTDAG data: %w", err)
 	}
 
+	if err := di.dagDB.SaveMergeSet(ctx, ghostDAGData.BlockHash, ghostDAGData.MergeSet()); err != nil {
+		di.logger.Error("failed to save mergeset",
+			zap.String("blockHash", blockHash.Hex()),
+			zap.Error(err))
+		return fmt.Errorf("save mergeset: %w", err)
+	}
+
+	// 4. Keep the block row consistent with the node's GHOSTDAG view
+	if di.blockDB != nil {
+		if err := di.blockDB.UpdateBlock(ctx, ghostDAGData.BlockHash, ghostDAGData.BlockUpdate()); err != nil {
+			return fmt.Errorf("update block GHOSTDAG fields: %w", err)
+		}
+	}
+
+	// 5. Settle the inclusions of the block and of the blocks it merges
+	if di.inclusionDB != nil {
+		if _, err := di.inclusionDB.RecomputeTransactionInclusions(ctx, []domain.Hash{ghostDAGData.BlockHash}); err != nil {
+			return fmt.Errorf("recompute transaction inclusions: %w", err)
+		}
+	}
+
+	// 6. Accept the transactions of the blocks a chain block merges as blue
+	if di.acceptDB != nil {
+		if _, err := di.acceptDB.RecomputeTransactionAcceptance(ctx, []domain.Hash{ghostDAGData.BlockHash}); err != nil {
+			return fmt.Errorf("recompute transaction acceptance: %w", err)
+		}
+	}
+
 	di.logger.Debug("GHOSTDAG data indexed",
 		zap.String("blockHash", blockHash.Hex()),
-		zap.Uint64("blueScore", blueScore))
+		zap.Uint64("blueScore", data.BlueScore))
 
 	return nil
 }
 
+// IndexBlock indexes DAG relationships and GHOSTDAG data for a block
+// using the unit of work's repositories.
+// It matches the BlockHook signature so it can run inside BlockIndexer's transaction.
+func (di *DAGIndexer) IndexBlock(
+	ctx context.Context,
+	repos interfaces.Repos,
+	block *interfaces.Block,
+) error {
+	bound := *di
+	bound.dagDB = repos.DAG
+	bound.blockDB = repos.Blocks
+	bound.inclusionDB = repos.Transactions
+	bound.acceptDB = repos.Transactions
+
+	blockHash := common.HexToHash(block.Hash)
+
+	selectedParent, err := parseOptionalHash(block.SelectedParent)
+	if err != nil {
+		return fmt.Errorf("selected parent %q: %w", block.SelectedParent, err)
+	}
+
+	if err := bound.IndexBlockDAGRelationships(ctx, blockHash, selectedParent); err != nil {
+		return err
+	}
+
+	return bound.IndexGHOSTDAGData(ctx, blockHash)
+}
+
+// convertRPCGHOSTDAGDataToDomain converts RPC GHOSTDAG data to domain.GHOSTDAGData,
+// normalizing every hash it references
+func convertRPCGHOSTDAGDataToDomain(blockHash domain.Hash, data *interfaces.GHOSTDAGData) (*domain.GHOSTDAGData, error) {
+	selectedParent, err := parseOptionalHash(data.SelectedParent)
+	if err != nil {
+		return nil, fmt.Errorf("selected parent %q: %w", data.SelectedParent, err)
+	}
+	blues, err := domain.ParseHashes(data.MergeSetBlues)
+	if err != nil {
+		return nil, fmt.Errorf("mergeset blues: %w", err)
+	}
+	reds, err := domain.ParseHashes(data.MergeSetReds)
+	if err != nil {
+		return nil, fmt.Errorf("mergeset reds: %w", err)
+	}
+
+	return &domain.GHOSTDAGData{
+		BlockHash:          blockHash,
+		BlueScore:          data.BlueScore,
+		BlueWork:           data.BlueWork,
+		SelectedParent:     selectedParent,
+		MergeSetBlues:      blues,
+		MergeSetReds:       reds,
+		BluesAnticoneSizes: data.BluesAnticoneSizes,
+	}, nil
+}
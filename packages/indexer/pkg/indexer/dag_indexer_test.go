@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
@@ -31,20 +32,19 @@ func TestDAGIndexer_IndexBlockDAGRelationships(t *testing.T) {
 		Return(expectedParents, nil)
 
 	// First parent is selected parent
-	mockDAGDB.On("SaveDAGRelationship", ctx, blockHash.Hex(), parent1.Hex(), true).
+	mockDAGDB.On("SaveDAGRelationship", ctx, domain.Hash(blockHash.Hex()), domain.Hash(parent1.Hex()), true).
 		Return(nil)
-	mockDAGDB.On("SaveDAGRelationship", ctx, blockHash.Hex(), parent2.Hex(), false).
+	mockDAGDB.On("SaveDAGRelationship", ctx, domain.Hash(blockHash.Hex()), domain.Hash(parent2.Hex()), false).
 		Return(nil)
 
 	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
 		ParentsRPC:  mockRPC,
-		DAGInfoRPC:  mockRPC,
-		BlueScoreRPC: mockRPC,
+		GHOSTDAGRPC: mockRPC,
 		DAGDB:       mockDAGDB,
 		Logger:      nil,
 	})
 
-	err := idx.IndexBlockDAGRelationships(ctx, blockHash, parent1.Hex())
+	err := idx.IndexBlockDAGRelationships(ctx, blockHash, domain.Hash(parent1.Hex()))
 
 	assert.NoError(t, err)
 	mockRPC.AssertExpectations(t)
@@ -63,8 +63,7 @@ func TestDAGIndexer_IndexBlockDAGRelationships_RPCFailure(t *testing.T) {
 
 	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
 		ParentsRPC:  mockRPC,
-		DAGInfoRPC:  mockRPC,
-		BlueScoreRPC: mockRPC,
+		GHOSTDAGRPC: mockRPC,
 		DAGDB:       mockDAGDB,
 		Logger:      nil,
 	})
@@ -88,8 +87,7 @@ func TestDAGIndexer_IndexBlockDAGRelationships_NoParents(t *testing.T) {
 
 	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
 		ParentsRPC:  mockRPC,
-		DAGInfoRPC:  mockRPC,
-		BlueScoreRPC: mockRPC,
+		GHOSTDAGRPC: mockRPC,
 		DAGDB:       mockDAGDB,
 		Logger:      nil,
 	})
@@ -106,42 +104,45 @@ func TestDAGIndexer_IndexGHOSTDAGData(t *testing.T) {
 
 	ctx := context.Background()
 	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
-	blockNumber := big.NewInt(100)
-
-	// Mock DAG info
-	dagInfo := &interfaces.DAGInfo{
-		BlueScore:     1000,
-		BlueWork:      big.NewInt(5000),
-		MergeSetBlues: []string{"0x" + strings.Repeat("b", 64)},
-		MergeSetReds:  []string{"0x" + strings.Repeat("c", 64)},
-	}
 
-	mockRPC.On("GetDAGInfo", ctx).
-		Return(dagInfo, nil)
+	// Mock the block's own GHOSTDAG data
+	data := &interfaces.GHOSTDAGData{
+		BlueScore:          1000,
+		BlueWork:           big.NewInt(5000),
+		SelectedParent:     "0x" + strings.Repeat("b", 64),
+		MergeSetBlues:      []string{"0x" + strings.Repeat("b", 64)},
+		MergeSetReds:       []string{"0x" + strings.Repeat("c", 64)},
+		BluesAnticoneSizes: []int{0},
+	}
 
-	mockRPC.On("GetBlueScore", ctx, blockNumber).
-		Return(uint64(1000), nil)
+	mockRPC.On("GetBlockGHOSTDAGData", ctx, blockHash).
+		Return(data, nil)
 
 	ghostDAGData := &domain.GHOSTDAGData{
-		BlockHash:     blockHash.Hex(),
-		BlueScore:     1000,
-		BlueWork:      big.NewInt(5000),
-		MergeSetBlues: []string{"0x" + strings.Repeat("b", 64)},
-		MergeSetReds:  []string{"0x" + strings.Repeat("c", 64)},
+		BlockHash:          domain.Hash(blockHash.Hex()),
+		BlueScore:          1000,
+		BlueWork:           big.NewInt(5000),
+		SelectedParent:     domain.Hash("0x" + strings.Repeat("b", 64)),
+		MergeSetBlues:      []domain.Hash{domain.Hash("0x" + strings.Repeat("b", 64))},
+		MergeSetReds:       []domain.Hash{domain.Hash("0x" + strings.Repeat("c", 64))},
+		BluesAnticoneSizes: []int{0},
 	}
 
-	mockDAGDB.On("SaveGHOSTDAGData", ctx, blockHash.Hex(), ghostDAGData).
+	mockDAGDB.On("SaveGHOSTDAGData", ctx, ghostDAGData.BlockHash, ghostDAGData).
 		Return(nil)
+	mockDAGDB.On("SaveMergeSet", ctx, ghostDAGData.BlockHash, []domain.MergeSetMember{
+		{MergingBlockHash: ghostDAGData.BlockHash, BlockHash: ghostDAGData.MergeSetBlues[0], IsBlue: true, Position: 0},
+		{MergingBlockHash: ghostDAGData.BlockHash, BlockHash: ghostDAGData.MergeSetReds[0], IsBlue: false, Position: 1},
+	}).Return(nil)
 
 	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
 		ParentsRPC:  mockRPC,
-		DAGInfoRPC:  mockRPC,
-		BlueScoreRPC: mockRPC,
+		GHOSTDAGRPC: mockRPC,
 		DAGDB:       mockDAGDB,
 		Logger:      nil,
 	})
 
-	err := idx.IndexGHOSTDAGData(ctx, blockHash, blockNumber)
+	err := idx.IndexGHOSTDAGData(ctx, blockHash)
 
 	assert.NoError(t, err)
 	mockDAGDB.AssertExpectations(t)
@@ -153,22 +154,238 @@ func TestDAGIndexer_IndexGHOSTDAGData_RPCFailure(t *testing.T) {
 
 	ctx := context.Background()
 	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
-	blockNumber := big.NewInt(100)
 
-	mockRPC.On("GetDAGInfo", ctx).
+	mockRPC.On("GetBlockGHOSTDAGData", ctx, blockHash).
 		Return(nil, assert.AnError)
 
 	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
 		ParentsRPC:  mockRPC,
-		DAGInfoRPC:  mockRPC,
-		BlueScoreRPC: mockRPC,
+		GHOSTDAGRPC: mockRPC,
 		DAGDB:       mockDAGDB,
 		Logger:      nil,
 	})
 
-	err := idx.IndexGHOSTDAGData(ctx, blockHash, blockNumber)
+	err := idx.IndexGHOSTDAGData(ctx, blockHash)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "fetch GHOSTDAG data")
+}
+
+func TestDAGIndexer_IndexGHOSTDAGData_NotFound(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockDAGDB := new(mocks.MockDAGWriter)
+
+	ctx := context.Background()
+	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+
+	mockRPC.On("GetBlockGHOSTDAGData", ctx, blockHash).
+		Return(nil, nil)
+
+	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
+		ParentsRPC:  mockRPC,
+		GHOSTDAGRPC: mockRPC,
+		DAGDB:       mockDAGDB,
+	})
+
+	err := idx.IndexGHOSTDAGData(ctx, blockHash)
 
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "fetch DAG info")
+	assert.Contains(t, err.Error(), "not found")
+	mockDAGDB.AssertNotCalled(t, "SaveGHOSTDAGData")
+}
+
+func TestDAGIndexer_IndexBlock(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockDAGDB := new(mocks.MockDAGWriter)
+	mockBlockDB := new(mocks.MockBlockWriter)
+
+	ctx := context.Background()
+	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+	parent := common.HexToHash("0x" + strings.Repeat("b", 64))
+
+	mockRPC.On("GetBlockParents", ctx, blockHash).
+		Return([]common.Hash{parent}, nil)
+	mockRPC.On("GetBlockGHOSTDAGData", ctx, blockHash).
+		Return(&interfaces.GHOSTDAGData{
+			BlueScore:      100,
+			BlueWork:       big.NewInt(1),
+			SelectedParent: parent.Hex(),
+		}, nil)
+
+	mockDAGDB.On("SaveDAGRelationship", ctx, domain.Hash(blockHash.Hex()), domain.Hash(parent.Hex()), true).
+		Return(nil)
+	mockDAGDB.On("SaveGHOSTDAGData", ctx, domain.Hash(blockHash.Hex()), &domain.GHOSTDAGData{
+		BlockHash:      domain.Hash(blockHash.Hex()),
+		BlueScore:      100,
+		BlueWork:       big.NewInt(1),
+		SelectedParent: domain.Hash(parent.Hex()),
+	}).Return(nil)
+	mockDAGDB.On("SaveMergeSet", ctx, domain.Hash(blockHash.Hex()), []domain.MergeSetMember{}).
+		Return(nil)
+
+	// The block row takes the node's GHOSTDAG scores
+	blueScore := uint64(100)
+	selectedParent := domain.Hash(parent.Hex())
+	mockBlockDB.On("UpdateBlock", ctx, domain.Hash(blockHash.Hex()), domain.BlockUpdate{
+		BlueScore:      &blueScore,
+		BlueWork:       big.NewInt(1),
+		SelectedParent: &selectedParent,
+	}).Return(nil)
+
+	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
+		ParentsRPC:  mockRPC,
+		GHOSTDAGRPC: mockRPC,
+		DAGDB:       new(mocks.MockDAGWriter),
+	})
+
+	repos := interfaces.Repos{
+		DAG: struct {
+			interfaces.DAGReader
+			*mocks.MockDAGWriter
+		}{MockDAGWriter: mockDAGDB},
+		Blocks: struct {
+			interfaces.BlockReader
+			interfaces.ChainBlockReader
+			interfaces.DAGGapReader
+			*mocks.MockBlockWriter
+		}{MockBlockWriter: mockBlockDB},
+	}
+
+	err := idx.IndexBlock(ctx, repos, &interfaces.Block{
+		Hash:           blockHash.Hex(),
+		Number:         100,
+		SelectedParent: parent.Hex(),
+	})
+
+	assert.NoError(t, err)
+	mockRPC.AssertExpectations(t)
+	mockDAGDB.AssertExpectations(t)
+	mockBlockDB.AssertExpectations(t)
+}
+
+func TestDAGIndexer_IndexGHOSTDAGData_RecomputesInclusions(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockDAGDB := new(mocks.MockDAGWriter)
+	mockInclusionDB := new(mocks.MockTransactionInclusionWriter)
+
+	ctx := context.Background()
+	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+
+	mockRPC.On("GetBlockGHOSTDAGData", ctx, blockHash).
+		Return(&interfaces.GHOSTDAGData{BlueScore: 7, BlueWork: big.NewInt(1)}, nil)
+	mockDAGDB.On("SaveGHOSTDAGData", ctx, domain.Hash(blockHash.Hex()), mock.AnythingOfType("*domain.GHOSTDAGData")).
+		Return(nil)
+	mockDAGDB.On("SaveMergeSet", ctx, domain.Hash(blockHash.Hex()), mock.Anything).
+		Return(nil)
+
+	// The block's mergeset may have accepted transactions included elsewhere
+	mockInclusionDB.On("RecomputeTransactionInclusions", ctx, []domain.Hash{domain.Hash(blockHash.Hex())}).
+		Return(int64(2), nil)
+
+	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
+		ParentsRPC:  mockRPC,
+		GHOSTDAGRPC: mockRPC,
+		DAGDB:       mockDAGDB,
+		InclusionDB: mockInclusionDB,
+	})
+
+	err := idx.IndexGHOSTDAGData(ctx, blockHash)
+
+	assert.NoError(t, err)
+	mockInclusionDB.AssertExpectations(t)
+}
+
+func TestDAGIndexer_IndexGHOSTDAGData_RecomputesAcceptance(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockDAGDB := new(mocks.MockDAGWriter)
+	mockAcceptDB := new(mocks.MockTransactionAcceptanceWriter)
+
+	ctx := context.Background()
+	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+
+	mockRPC.On("GetBlockGHOSTDAGData", ctx, blockHash).
+		Return(&interfaces.GHOSTDAGData{BlueScore: 7, BlueWork: big.NewInt(1)}, nil)
+	mockDAGDB.On("SaveGHOSTDAGData", ctx, domain.Hash(blockHash.Hex()), mock.AnythingOfType("*domain.GHOSTDAGData")).
+		Return(nil)
+	mockDAGDB.On("SaveMergeSet", ctx, domain.Hash(blockHash.Hex()), mock.Anything).
+		Return(nil)
+
+	// Transactions of the blocks the mergeset holds become accepted with it
+	mockAcceptDB.On("RecomputeTransactionAcceptance", ctx, []domain.Hash{domain.Hash(blockHash.Hex())}).
+		Return(int64(3), nil)
+
+	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
+		ParentsRPC:  mockRPC,
+		GHOSTDAGRPC: mockRPC,
+		DAGDB:       mockDAGDB,
+		AcceptDB:    mockAcceptDB,
+	})
+
+	err := idx.IndexGHOSTDAGData(ctx, blockHash)
+
+	assert.NoError(t, err)
+	mockAcceptDB.AssertExpectations(t)
+}
+
+func TestDAGIndexer_IndexGHOSTDAGData_InclusionFailure(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockDAGDB := new(mocks.MockDAGWriter)
+	mockInclusionDB := new(mocks.MockTransactionInclusionWriter)
+
+	ctx := context.Background()
+	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+
+	mockRPC.On("GetBlockGHOSTDAGData", ctx, blockHash).
+		Return(&interfaces.GHOSTDAGData{BlueScore: 7, BlueWork: big.NewInt(1)}, nil)
+	mockDAGDB.On("SaveGHOSTDAGData", ctx, domain.Hash(blockHash.Hex()), mock.AnythingOfType("*domain.GHOSTDAGData")).
+		Return(nil)
+	mockDAGDB.On("SaveMergeSet", ctx, domain.Hash(blockHash.Hex()), mock.Anything).
+		Return(nil)
+	mockInclusionDB.On("RecomputeTransactionInclusions", ctx, mock.Anything).
+		Return(int64(0), assert.AnError)
+
+	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
+		ParentsRPC:  mockRPC,
+		GHOSTDAGRPC: mockRPC,
+		DAGDB:       mockDAGDB,
+		InclusionDB: mockInclusionDB,
+	})
+
+	err := idx.IndexGHOSTDAGData(ctx, blockHash)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Contains(t, err.Error(), "recompute transaction inclusions")
 }
 
+func TestDAGIndexer_IndexGHOSTDAGData_MergeSetFailure(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockDAGDB := new(mocks.MockDAGWriter)
+	mockBlockDB := new(mocks.MockBlockWriter)
+
+	ctx := context.Background()
+	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+
+	mockRPC.On("GetBlockGHOSTDAGData", ctx, blockHash).
+		Return(&interfaces.GHOSTDAGData{
+			BlueScore:     7,
+			BlueWork:      big.NewInt(1),
+			MergeSetBlues: []string{"0x" + strings.Repeat("b", 64)},
+		}, nil)
+	mockDAGDB.On("SaveGHOSTDAGData", ctx, domain.Hash(blockHash.Hex()), mock.AnythingOfType("*domain.GHOSTDAGData")).
+		Return(nil)
+	mockDAGDB.On("SaveMergeSet", ctx, domain.Hash(blockHash.Hex()), mock.Anything).
+		Return(assert.AnError)
+
+	idx := indexer.NewDAGIndexer(indexer.DAGIndexerDeps{
+		ParentsRPC:  mockRPC,
+		GHOSTDAGRPC: mockRPC,
+		DAGDB:       mockDAGDB,
+		BlockDB:     mockBlockDB,
+	})
+
+	err := idx.IndexGHOSTDAGData(ctx, blockHash)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Contains(t, err.Error(), "save mergeset")
+	mockBlockDB.AssertNotCalled(t, "UpdateBlock", mock.Anything, mock.Anything, mock.Anything)
+}
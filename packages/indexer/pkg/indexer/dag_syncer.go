@@ -0,0 +1,194 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// defaultMaxDAGSyncBlocks bounds how many blocks one SyncRange call fetches
+const defaultMaxDAGSyncBlocks = 1000
+
+// ErrDAGSyncIncomplete is returned when a SyncRange call reached its block limit before
+// every unknown ancestor was indexed. The next call resumes where it stopped.
+var ErrDAGSyncIncomplete = errors.New("DAG sync incomplete")
+
+// DAGSyncerDeps contains dependencies for DAGSyncer (ISP)
+type DAGSyncerDeps struct {
+	RPC       interfaces.BlockByHashReader
+	Gaps      interfaces.DAGGapReader
+	Indexer   *BlockIndexer
+	MinNumber int64 // Blocks numbered below this are not traversed, so a fresh database does not walk back to genesis
+	MaxBlocks int   // Blocks fetched per SyncRange call at most; defaults to 1000
+	Logger    *zap.Logger
+}
+
+// DAGSyncer indexes blocks that are only reachable through the DAG.
+// eth_getBlockByNumber returns one block per number, so sibling blocks, red
+// blocks and merged blues are never seen by the number-based loop. DAGSyncer
+// walks ParentHashes of indexed blocks and indexes every parent it does not know.
+type DAGSyncer struct {
+	rpc       interfaces.BlockByHashReader
+	gaps      interfaces.DAGGapReader
+	indexer   *BlockIndexer
+	minNumber int64
+	maxBlocks int
+	pending   []domain.Hash        // Ancestors left over when the last call hit maxBlocks
+	below     map[domain.Hash]bool // Parents numbered below minNumber, fetched once and never indexed
+	logger    *zap.Logger
+}
+
+// NewDAGSyncer creates a new DAGSyncer
+func NewDAGSyncer(deps DAGSyncerDeps) *DAGSyncer {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	maxBlocks := deps.MaxBlocks
+	if maxBlocks <= 0 {
+		maxBlocks = defaultMaxDAGSyncBlocks
+	}
+
+	return &DAGSyncer{
+		rpc:       deps.RPC,
+		gaps:      deps.Gaps,
+		indexer:   deps.Indexer,
+		minNumber: deps.MinNumber,
+		maxBlocks: maxBlocks,
+		below:     make(map[domain.Hash]bool),
+		logger:    logger,
+	}
+}
+
+// SyncRange indexes the unknown ancestors of the blocks numbered from..to and
+// returns how many blocks it indexed. Ancestors are indexed parents first.
+// When more than MaxBlocks ancestors are missing it indexes the ones it fetched,
+// keeps the rest for the next call and returns ErrDAGSyncIncomplete, so callers
+// do not move past the range until it is complete.
+func (s *DAGSyncer) SyncRange(ctx context.Context, from, to int64) (int, error) {
+	frontier, err := s.gaps.GetMissingParents(ctx, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("find missing parents: %w", err)
+	}
+
+	if len(s.pending) > 0 {
+		frontier, err = s.gaps.FilterMissingBlocks(ctx, append(s.pending, frontier...))
+		if err != nil {
+			return 0, fmt.Errorf("filter missing blocks: %w", err)
+		}
+	}
+
+	blocks, remaining, err := s.collect(ctx, frontier)
+	if err != nil {
+		return 0, err
+	}
+
+	// Blue score grows from parent to child, so parents are saved before their children
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].BlueScore != blocks[j].BlueScore {
+			return blocks[i].BlueScore < blocks[j].BlueScore
+		}
+		return blocks[i].Number < blocks[j].Number
+	})
+
+	for i, block := range blocks {
+		if err := s.indexer.IndexFetchedBlock(ctx, block); err != nil {
+			// Unsaved blocks may only be reachable through saved ones, so retry them too
+			for _, unsaved := range blocks[i:] {
+				if hash, err := domain.ParseHash(unsaved.Hash); err == nil {
+					remaining = append(remaining, hash)
+				}
+			}
+			s.pending = remaining
+			return i, fmt.Errorf("index DAG block %s: %w", block.Hash, err)
+		}
+	}
+	s.pending = remaining
+
+	if len(blocks) > 0 {
+		s.logger.Info("DAG blocks indexed",
+			zap.Int64("from", from),
+			zap.Int64("to", to),
+			zap.Int("count", len(blocks)))
+	}
+
+	if len(remaining) > 0 {
+		s.logger.Warn("DAG gap larger than sync limit, resuming on the next call",
+			zap.Int("limit", s.maxBlocks),
+			zap.Int("pending", len(remaining)))
+		return len(blocks), fmt.Errorf("%w: %d ancestors left after %d blocks", ErrDAGSyncIncomplete, len(remaining), len(blocks))
+	}
+
+	return len(blocks), nil
+}
+
+// collect fetches unknown blocks breadth-first from frontier through their parents.
+// When maxBlocks is reached it also returns the hashes it did not get to.
+func (s *DAGSyncer) collect(ctx context.Context, frontier []domain.Hash) ([]*interfaces.Block, []domain.Hash, error) {
+	visited := make(map[domain.Hash]bool)
+	var blocks []*interfaces.Block
+
+	for len(frontier) > 0 {
+		var parents []domain.Hash
+		for i, hash := range frontier {
+			// Blocks below minNumber stay missing, so GetMissingParents keeps returning them
+			if visited[hash] || s.below[hash] {
+				continue
+			}
+
+			if len(blocks) >= s.maxBlocks {
+				return blocks, append(parents, frontier[i:]...), nil
+			}
+			visited[hash] = true
+
+			block, err := s.rpc.GetBlockByHash(ctx, common.HexToHash(hash.String()), s.indexer.fullTx())
+			if err != nil {
+				s.logger.Error("failed to fetch DAG block",
+					zap.String("hash", hash.String()),
+					zap.Error(err))
+				return nil, nil, fmt.Errorf("fetch block %s: %w", hash, err)
+			}
+			if block == nil {
+				s.logger.Warn("parent block not found on node", zap.String("hash", hash.String()))
+				continue
+			}
+			if block.Number < s.minNumber {
+				s.below[hash] = true
+				continue
+			}
+
+			blocks = append(blocks, block)
+
+			// Parents are numbered below their child, so a block at the minimum has none to traverse
+			if block.Number <= s.minNumber {
+				continue
+			}
+
+			blockParents, err := domain.ParseHashes(block.ParentHashes)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parents of block %s: %w", hash, err)
+			}
+			for _, parent := range blockParents {
+				if !visited[parent] {
+					parents = append(parents, parent)
+				}
+			}
+		}
+
+		var err error
+		frontier, err = s.gaps.FilterMissingBlocks(ctx, parents)
+		if err != nil {
+			return nil, nil, fmt.Errorf("filter missing blocks: %w", err)
+		}
+	}
+
+	return blocks, nil, nil
+}
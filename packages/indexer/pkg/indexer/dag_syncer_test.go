@@ -0,0 +1,215 @@
+package indexer_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+func dagHash(c string) string {
+	return "0x" + strings.Repeat(c, 64)
+}
+
+func dagHashes(hashes ...string) []domain.Hash {
+	parsed, _ := domain.ParseHashes(hashes)
+	return parsed
+}
+
+// newDAGSyncerForTest returns a syncer whose indexer records saved block hashes in order
+func newDAGSyncerForTest(
+	rpc interfaces.BlockByHashReader,
+	gaps interfaces.DAGGapReader,
+	deps indexer.DAGSyncerDeps,
+) (*indexer.DAGSyncer, *[]string) {
+	blockWriter := new(mocks.MockBlockWriter)
+	saved := &[]string{}
+	blockWriter.On("SaveBlock", mock.Anything, mock.AnythingOfType("*domain.Block")).
+		Run(func(args mock.Arguments) {
+			*saved = append(*saved, args.Get(1).(*domain.Block).Hash.String())
+		}).
+		Return(nil)
+
+	deps.RPC = rpc
+	deps.Gaps = gaps
+	deps.Indexer = indexer.NewBlockIndexer(indexer.BlockIndexerDeps{
+		DB:   blockWriter,
+		TxDB: new(mocks.MockTransactionWriter),
+	})
+
+	return indexer.NewDAGSyncer(deps), saved
+}
+
+func TestDAGSyncer_SyncRange_IndexesUnknownAncestors(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockGaps := new(mocks.MockDAGGapReader)
+
+	ctx := context.Background()
+	known, sibling, red := dagHash("a"), dagHash("b"), dagHash("c")
+
+	// Indexed block 10 merges a sibling, which in turn merges a red block
+	mockGaps.On("GetMissingParents", ctx, int64(10), int64(10)).Return(dagHashes(sibling), nil)
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(sibling), true).
+		Return(&interfaces.Block{
+			Hash: sibling, Number: 10, Timestamp: 1, BlueScore: 6,
+			ParentHashes: []string{red, known},
+		}, nil)
+	mockGaps.On("FilterMissingBlocks", ctx, dagHashes(red, known)).Return(dagHashes(red), nil)
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(red), true).
+		Return(&interfaces.Block{
+			Hash: red, Number: 9, Timestamp: 1, BlueScore: 5,
+			ParentHashes: []string{known},
+		}, nil)
+	mockGaps.On("FilterMissingBlocks", ctx, dagHashes(known)).Return([]domain.Hash{}, nil)
+
+	syncer, saved := newDAGSyncerForTest(mockRPC, mockGaps, indexer.DAGSyncerDeps{})
+
+	count, err := syncer.SyncRange(ctx, 10, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{red, sibling}, *saved, "parents must be saved before children")
+	mockRPC.AssertExpectations(t)
+	mockGaps.AssertExpectations(t)
+}
+
+func TestDAGSyncer_SyncRange_StopsAtMinNumber(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockGaps := new(mocks.MockDAGGapReader)
+
+	ctx := context.Background()
+	parent, grandparent := dagHash("b"), dagHash("c")
+
+	mockGaps.On("GetMissingParents", ctx, int64(100), int64(100)).Return(dagHashes(parent), nil)
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(parent), true).
+		Return(&interfaces.Block{
+			Hash: parent, Number: 99, Timestamp: 1,
+			ParentHashes: []string{grandparent},
+		}, nil)
+	mockGaps.On("FilterMissingBlocks", ctx, []domain.Hash(nil)).Return([]domain.Hash{}, nil)
+
+	syncer, saved := newDAGSyncerForTest(mockRPC, mockGaps, indexer.DAGSyncerDeps{MinNumber: 100})
+
+	count, err := syncer.SyncRange(ctx, 100, 100)
+
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Empty(t, *saved)
+	mockRPC.AssertNotCalled(t, "GetBlockByHash", ctx, common.HexToHash(grandparent), true)
+}
+
+func TestDAGSyncer_SyncRange_FetchesBlocksBelowMinNumberOnce(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockGaps := new(mocks.MockDAGGapReader)
+
+	ctx := context.Background()
+	parent := dagHash("b")
+
+	// The parent is never indexed, so every range below it reports it missing again
+	mockGaps.On("GetMissingParents", ctx, mock.Anything, mock.Anything).Return(dagHashes(parent), nil)
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(parent), true).
+		Return(&interfaces.Block{Hash: parent, Number: 99, Timestamp: 1}, nil).
+		Once()
+	mockGaps.On("FilterMissingBlocks", ctx, []domain.Hash(nil)).Return([]domain.Hash{}, nil)
+
+	syncer, saved := newDAGSyncerForTest(mockRPC, mockGaps, indexer.DAGSyncerDeps{MinNumber: 100})
+
+	for _, number := range []int64{100, 101} {
+		count, err := syncer.SyncRange(ctx, number, number)
+
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	}
+	assert.Empty(t, *saved)
+	mockRPC.AssertNumberOfCalls(t, "GetBlockByHash", 1)
+}
+
+func TestDAGSyncer_SyncRange_DoesNotFetchParentsAtMinNumber(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockGaps := new(mocks.MockDAGGapReader)
+
+	ctx := context.Background()
+	sibling, parent := dagHash("b"), dagHash("c")
+
+	mockGaps.On("GetMissingParents", ctx, int64(100), int64(100)).Return(dagHashes(sibling), nil)
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(sibling), true).
+		Return(&interfaces.Block{
+			Hash: sibling, Number: 100, Timestamp: 1,
+			ParentHashes: []string{parent},
+		}, nil)
+	mockGaps.On("FilterMissingBlocks", ctx, []domain.Hash(nil)).Return([]domain.Hash{}, nil)
+
+	syncer, saved := newDAGSyncerForTest(mockRPC, mockGaps, indexer.DAGSyncerDeps{MinNumber: 100})
+
+	count, err := syncer.SyncRange(ctx, 100, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{sibling}, *saved)
+	mockGaps.AssertNotCalled(t, "FilterMissingBlocks", ctx, dagHashes(parent))
+	mockRPC.AssertNotCalled(t, "GetBlockByHash", ctx, common.HexToHash(parent), true)
+}
+
+func TestDAGSyncer_SyncRange_RPCFailure(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockGaps := new(mocks.MockDAGGapReader)
+
+	ctx := context.Background()
+	first, second := dagHash("b"), dagHash("c")
+
+	mockGaps.On("GetMissingParents", ctx, int64(10), int64(10)).Return(dagHashes(first, second), nil)
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(first), true).
+		Return(&interfaces.Block{Hash: first, Number: 10, Timestamp: 1}, nil)
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(second), true).
+		Return(nil, assert.AnError)
+
+	syncer, saved := newDAGSyncerForTest(mockRPC, mockGaps, indexer.DAGSyncerDeps{})
+
+	_, err := syncer.SyncRange(ctx, 10, 10)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, *saved, "nothing is indexed when the traversal fails")
+}
+
+func TestDAGSyncer_SyncRange_MaxBlocks(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockGaps := new(mocks.MockDAGGapReader)
+
+	ctx := context.Background()
+	first, second := dagHash("b"), dagHash("c")
+
+	mockGaps.On("GetMissingParents", ctx, int64(10), int64(10)).Return(dagHashes(first, second), nil).Once()
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(first), true).
+		Return(&interfaces.Block{Hash: first, Number: 10, Timestamp: 1}, nil)
+
+	syncer, saved := newDAGSyncerForTest(mockRPC, mockGaps, indexer.DAGSyncerDeps{MaxBlocks: 1})
+
+	count, err := syncer.SyncRange(ctx, 10, 10)
+
+	assert.ErrorIs(t, err, indexer.ErrDAGSyncIncomplete, "the range must not be treated as synced")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{first}, *saved)
+	mockRPC.AssertNotCalled(t, "GetBlockByHash", ctx, common.HexToHash(second), true)
+
+	// The next call picks up the ancestor left behind
+	mockGaps.On("GetMissingParents", ctx, int64(10), int64(10)).Return(dagHashes(second), nil).Once()
+	mockGaps.On("FilterMissingBlocks", ctx, dagHashes(second, second)).Return(dagHashes(second), nil)
+	mockRPC.On("GetBlockByHash", ctx, common.HexToHash(second), true).
+		Return(&interfaces.Block{Hash: second, Number: 10, Timestamp: 1}, nil)
+	mockGaps.On("FilterMissingBlocks", ctx, []domain.Hash(nil)).Return([]domain.Hash{}, nil)
+
+	count, err = syncer.SyncRange(ctx, 10, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{first, second}, *saved)
+	mockGaps.AssertExpectations(t)
+}
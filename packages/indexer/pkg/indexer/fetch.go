@@ -0,0 +1,44 @@
+package indexer
+
+import "sync"
+
+// fetchAll calls fetch for every index in 0..n-1 on at most workers goroutines and
+// returns the results in index order. If any call fails, the error of the lowest
+// failing index is returned.
+func fetchAll[T any](n, workers int, fetch func(i int) (T, error)) ([]T, error) {
+	results := make([]T, n)
+	errs := make([]error, n)
+
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	indexChan := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indexChan <- i
+	}
+	close(indexChan)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexChan {
+				results[i], errs[i] = fetch(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
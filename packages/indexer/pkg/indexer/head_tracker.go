@@ -0,0 +1,153 @@
+package indexer
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+const (
+	defaultPollInterval        = 2 * time.Second
+	defaultResubscribeInterval = 30 * time.Second
+)
+
+// HeadTrackerDeps contains dependencies for HeadTracker
+type HeadTrackerDeps struct {
+	RPC                 interfaces.BlockNumberReader
+	Subscriber          interfaces.NewBlockSubscriber // Optional: without it the tracker only polls
+	PollInterval        time.Duration                 // Defaults to 2s
+	ResubscribeInterval time.Duration                 // How long to poll before resubscribing; defaults to 30s
+	Logger              *zap.Logger
+}
+
+// HeadTracker reports the node's latest block number. It follows pushed blocks
+// while a subscription is up and polls eth_blockNumber while it is down.
+type HeadTracker struct {
+	rpc                 interfaces.BlockNumberReader
+	subscriber          interfaces.NewBlockSubscriber
+	pollInterval        time.Duration
+	resubscribeInterval time.Duration
+	logger              *zap.Logger
+}
+
+// NewHeadTracker creates a new HeadTracker
+func NewHeadTracker(deps HeadTrackerDeps) *HeadTracker {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	pollInterval := deps.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	resubscribeInterval := deps.ResubscribeInterval
+	if resubscribeInterval <= 0 {
+		resubscribeInterval = defaultResubscribeInterval
+	}
+
+	return &HeadTracker{
+		rpc:                 deps.RPC,
+		subscriber:          deps.Subscriber,
+		pollInterval:        pollInterval,
+		resubscribeInterval: resubscribeInterval,
+		logger:              logger,
+	}
+}
+
+// Heads starts tracking until ctx is done and returns a channel of head block numbers.
+// Only the latest head is kept while the reader is busy. The channel is never closed.
+func (t *HeadTracker) Heads(ctx context.Context) <-chan int64 {
+	heads := make(chan int64, 1)
+	go t.run(ctx, heads)
+	return heads
+}
+
+// run alternates between following a subscription and polling
+func (t *HeadTracker) run(ctx context.Context, heads chan int64) {
+	for ctx.Err() == nil {
+		if t.subscriber != nil {
+			t.follow(ctx, heads)
+		}
+		t.poll(ctx, heads)
+	}
+}
+
+// follow publishes pushed blocks until the subscription fails
+func (t *HeadTracker) follow(ctx context.Context, heads chan int64) {
+	headers := make(chan *interfaces.BlockHeader, 16)
+	sub, err := t.subscriber.SubscribeNewBlocks(ctx, headers)
+	if err != nil {
+		t.logger.Warn("failed to subscribe to new blocks, polling instead", zap.Error(err))
+		return
+	}
+	defer sub.Unsubscribe()
+
+	t.logger.Info("following pushed blocks")
+
+	// Blocks produced before the subscription started are only seen by polling
+	t.pollOnce(ctx, heads)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-sub.Err():
+			t.logger.Warn("block subscription dropped, polling instead", zap.Error(err))
+			return
+		case header := <-headers:
+			t.publish(heads, header.Number)
+		}
+	}
+}
+
+// poll publishes the polled head until it is time to resubscribe.
+// Without a subscriber it polls until ctx is done.
+func (t *HeadTracker) poll(ctx context.Context, heads chan int64) {
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	var resubscribe <-chan time.Time
+	if t.subscriber != nil {
+		timer := time.NewTimer(t.resubscribeInterval)
+		defer timer.Stop()
+		resubscribe = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-resubscribe:
+			return
+		case <-ticker.C:
+			t.pollOnce(ctx, heads)
+		}
+	}
+}
+
+// pollOnce publishes the current block number
+func (t *HeadTracker) pollOnce(ctx context.Context, heads chan int64) {
+	number, err := t.rpc.BlockNumber(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.logger.Error("failed to get current block number", zap.Error(err))
+		}
+		return
+	}
+
+	t.publish(heads, number.Int64())
+}
+
+// publish replaces any unread head with number; run is the only sender
+func (t *HeadTracker) publish(heads chan int64, number int64) {
+	select {
+	case <-heads:
+	default:
+	}
+	heads <- number
+}
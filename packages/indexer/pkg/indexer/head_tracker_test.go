@@ -0,0 +1,134 @@
+package indexer_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+// waitForHead reads heads until want arrives
+func waitForHead(t *testing.T, heads <-chan int64, want int64) {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case head := <-heads:
+			if head == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("head %d not published", want)
+		}
+	}
+}
+
+func TestHeadTracker_PollsWithoutSubscriber(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockRPC.On("BlockNumber", mock.Anything).Return(big.NewInt(100), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tracker := indexer.NewHeadTracker(indexer.HeadTrackerDeps{
+		RPC:          mockRPC,
+		PollInterval: 5 * time.Millisecond,
+	})
+
+	waitForHead(t, tracker.Heads(ctx), 100)
+}
+
+func TestHeadTracker_FollowsPushedBlocks(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockRPC.On("BlockNumber", mock.Anything).Return(big.NewInt(100), nil)
+
+	subscriber := &mocks.MockBlockSubscriber{Subscriptions: make(chan *mocks.MockSubscription, 1)}
+	subscriber.On("SubscribeNewBlocks", mock.Anything).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Polling would only ever see 100
+	tracker := indexer.NewHeadTracker(indexer.HeadTrackerDeps{
+		RPC:          mockRPC,
+		Subscriber:   subscriber,
+		PollInterval: time.Hour,
+	})
+	heads := tracker.Heads(ctx)
+
+	sub := <-subscriber.Subscriptions
+	waitForHead(t, heads, 100) // Catch-up poll right after subscribing
+
+	sub.Headers <- &interfaces.BlockHeader{Number: 101}
+	waitForHead(t, heads, 101)
+
+	sub.Headers <- &interfaces.BlockHeader{Number: 102}
+	waitForHead(t, heads, 102)
+
+	mockRPC.AssertNumberOfCalls(t, "BlockNumber", 1)
+}
+
+func TestHeadTracker_FallsBackToPollingWhenSubscriptionDrops(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockRPC.On("BlockNumber", mock.Anything).Return(big.NewInt(100), nil).Once()
+	mockRPC.On("BlockNumber", mock.Anything).Return(big.NewInt(200), nil)
+
+	subscriber := &mocks.MockBlockSubscriber{Subscriptions: make(chan *mocks.MockSubscription, 2)}
+	subscriber.On("SubscribeNewBlocks", mock.Anything).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tracker := indexer.NewHeadTracker(indexer.HeadTrackerDeps{
+		RPC:                 mockRPC,
+		Subscriber:          subscriber,
+		PollInterval:        5 * time.Millisecond,
+		ResubscribeInterval: 50 * time.Millisecond,
+	})
+	heads := tracker.Heads(ctx)
+
+	sub := <-subscriber.Subscriptions
+	waitForHead(t, heads, 100)
+
+	sub.ErrCh <- errors.New("connection reset")
+	waitForHead(t, heads, 200)
+	assert.Eventually(t, sub.Unsubscribed, time.Second, 5*time.Millisecond)
+
+	// Polling gives way to a new subscription
+	select {
+	case <-subscriber.Subscriptions:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "tracker did not resubscribe")
+	}
+}
+
+func TestHeadTracker_PollsWhenSubscribeFails(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockRPC.On("BlockNumber", mock.Anything).Return(big.NewInt(100), nil)
+
+	subscriber := new(mocks.MockBlockSubscriber)
+	subscriber.On("SubscribeNewBlocks", mock.Anything).Return(errors.New("dial failed"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tracker := indexer.NewHeadTracker(indexer.HeadTrackerDeps{
+		RPC:                 mockRPC,
+		Subscriber:          subscriber,
+		PollInterval:        5 * time.Millisecond,
+		ResubscribeInterval: time.Hour,
+	})
+
+	waitForHead(t, tracker.Heads(ctx), 100)
+	subscriber.AssertNumberOfCalls(t, "SubscribeNewBlocks", 1)
+}
@@ -0,0 +1,64 @@
+package indexer
+
+import (
+	"context"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// logBatch collects the logs that hooks save while a bulk batch is written, so they
+// reach the database with one BulkWriter.WriteLogs call instead of a SaveLog per row.
+// Reads pass through to the unit of work's log repository.
+type logBatch struct {
+	interfaces.LogRepository
+	logs []*domain.Log
+}
+
+// SaveLog implements interfaces.LogWriter by queueing the log
+func (b *logBatch) SaveLog(ctx context.Context, log *domain.Log) error {
+	b.logs = append(b.logs, log)
+	return nil
+}
+
+// bind returns repos whose log writes are queued on the batch, including inside
+// nested units of work
+func (b *logBatch) bind(repos interfaces.Repos) interfaces.Repos {
+	b.LogRepository = repos.Logs
+	repos.Logs = b
+	if repos.Nested != nil {
+		repos.Nested = &logBatchUnitOfWork{inner: repos.Nested, batch: b}
+	}
+	return repos
+}
+
+// flush writes the queued logs
+func (b *logBatch) flush(ctx context.Context, bulk interfaces.BulkWriter) error {
+	if len(b.logs) == 0 {
+		return nil
+	}
+	return bulk.WriteLogs(ctx, b.logs)
+}
+
+// logBatchUnitOfWork keeps a nested unit of work writing logs to the batch. A nested
+// unit that fails is rolled back, so the logs it queued are dropped as well.
+type logBatchUnitOfWork struct {
+	inner interfaces.UnitOfWork
+	batch *logBatch
+}
+
+// WithTx implements interfaces.UnitOfWork
+func (u *logBatchUnitOfWork) WithTx(ctx context.Context, fn func(repos interfaces.Repos) error) error {
+	queued := len(u.batch.logs)
+	outer := u.batch.LogRepository
+
+	err := u.inner.WithTx(ctx, func(repos interfaces.Repos) error {
+		return fn(u.batch.bind(repos))
+	})
+
+	u.batch.LogRepository = outer
+	if err != nil {
+		u.batch.logs = u.batch.logs[:queued]
+	}
+	return err
+}
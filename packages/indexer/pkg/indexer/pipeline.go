@@ -0,0 +1,180 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// ErrorPolicy decides what a failing pipeline stage does to its block
+type ErrorPolicy int
+
+const (
+	// FailBlock rolls back the whole block, so it is indexed again on the next attempt
+	FailBlock ErrorPolicy = iota
+	// SkipStage rolls back the stage's writes, logs the error and keeps the block
+	SkipStage
+)
+
+// String implements fmt.Stringer
+func (p ErrorPolicy) String() string {
+	switch p {
+	case FailBlock:
+		return "fail"
+	case SkipStage:
+		return "skip"
+	default:
+		return fmt.Sprintf("ErrorPolicy(%d)", int(p))
+	}
+}
+
+// ParseErrorPolicy parses "fail" or "skip"
+func ParseErrorPolicy(s string) (ErrorPolicy, error) {
+	switch s {
+	case "fail":
+		return FailBlock, nil
+	case "skip":
+		return SkipStage, nil
+	default:
+		return FailBlock, fmt.Errorf("unknown error policy %q", s)
+	}
+}
+
+// Stage is a step that runs for every block after the block itself is saved
+type Stage struct {
+	Name    string
+	Run     BlockHook
+	OnError ErrorPolicy
+	Retries int // Extra attempts before OnError applies
+}
+
+// ReceiptsStage indexes transaction receipts and event logs
+func ReceiptsStage(ti *TransactionIndexer, onError ErrorPolicy) Stage {
+	return Stage{Name: "receipts", Run: ti.IndexBlockReceipts, OnError: onError}
+}
+
+// DAGStage indexes DAG relationships and GHOSTDAG data
+func DAGStage(di *DAGIndexer, onError ErrorPolicy) Stage {
+	return Stage{Name: "dag", Run: di.IndexBlock, OnError: onError}
+}
+
+// PipelineDeps contains dependencies for Pipeline
+type PipelineDeps struct {
+	Blocks BlockIndexerDeps // The block stage; Store is required for the other stages to run
+	Stages []Stage          // Run in order after the block's own hooks
+	Logger *zap.Logger
+}
+
+// Pipeline indexes blocks and runs every stage for each of them in the block's unit of work:
+// block and transactions first, then e.g. receipts/logs and DAG data.
+type Pipeline struct {
+	blocks *BlockIndexer
+	stages []Stage
+	logger *zap.Logger
+}
+
+// NewPipeline creates a new Pipeline
+func NewPipeline(deps PipelineDeps) *Pipeline {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	p := &Pipeline{
+		stages: deps.Stages,
+		logger: logger,
+	}
+
+	blockDeps := deps.Blocks
+	if blockDeps.Logger == nil {
+		blockDeps.Logger = logger
+	}
+	if len(deps.Stages) > 0 {
+		blockDeps.Hooks = append(append([]BlockHook(nil), deps.Blocks.Hooks...), p.runStages)
+	}
+	p.blocks = NewBlockIndexer(blockDeps)
+
+	return p
+}
+
+// Blocks returns the block indexer that runs the pipeline's stages
+func (p *Pipeline) Blocks() *BlockIndexer {
+	return p.blocks
+}
+
+// IndexBlock runs the pipeline for a single block and returns the hash of the block saved
+func (p *Pipeline) IndexBlock(ctx context.Context, blockNum *big.Int) (domain.Hash, error) {
+	return p.blocks.IndexBlock(ctx, blockNum)
+}
+
+// IndexBlockRange runs the pipeline for a range of blocks and returns the hash of the block saved at to
+func (p *Pipeline) IndexBlockRange(ctx context.Context, from, to *big.Int) (domain.Hash, error) {
+	return p.blocks.IndexBlockRange(ctx, from, to)
+}
+
+// runStages is the BlockHook that runs every stage in order
+func (p *Pipeline) runStages(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+	for _, stage := range p.stages {
+		if err := p.runStage(ctx, repos, block, stage); err != nil {
+			return fmt.Errorf("stage %s: %w", stage.Name, err)
+		}
+	}
+	return nil
+}
+
+// runStage runs a stage with retries and applies its error policy
+func (p *Pipeline) runStage(
+	ctx context.Context,
+	repos interfaces.Repos,
+	block *interfaces.Block,
+	stage Stage,
+) error {
+	var err error
+	for attempt := 0; attempt <= stage.Retries; attempt++ {
+		if err = p.attempt(ctx, repos, block, stage); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		p.logger.Warn("pipeline stage failed",
+			zap.String("stage", stage.Name),
+			zap.String("blockHash", block.Hash),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err))
+	}
+
+	if stage.OnError == SkipStage {
+		p.logger.Error("skipping pipeline stage",
+			zap.String("stage", stage.Name),
+			zap.String("blockHash", block.Hash),
+			zap.Int64("blockNumber", block.Number),
+			zap.Error(err))
+		return nil
+	}
+
+	return err
+}
+
+// attempt runs a stage in a savepoint, so a failed attempt leaves no partial writes
+// and the block's transaction stays usable
+func (p *Pipeline) attempt(
+	ctx context.Context,
+	repos interfaces.Repos,
+	block *interfaces.Block,
+	stage Stage,
+) error {
+	if repos.Nested == nil {
+		return stage.Run(ctx, repos, block)
+	}
+
+	return repos.Nested.WithTx(ctx, func(stageRepos interfaces.Repos) error {
+		return stage.Run(ctx, stageRepos, block)
+	})
+}
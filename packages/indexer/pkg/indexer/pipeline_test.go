@@ -0,0 +1,186 @@
+package indexer_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+// pipelineTestDeps returns block indexer deps whose unit of work saves one block
+func pipelineTestDeps(ctx context.Context) (indexer.BlockIndexerDeps, *mocks.MockUnitOfWork) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockRPC.On("GetBlockByNumber", ctx, big.NewInt(100), true).
+		Return(&interfaces.Block{
+			Hash:      "0x" + strings.Repeat("a", 64),
+			Number:    100,
+			Timestamp: 1706150400000,
+		}, nil)
+
+	blockWriter := new(mocks.MockBlockWriter)
+	blockWriter.On("SaveBlock", ctx, mock.AnythingOfType("*domain.Block")).Return(nil)
+
+	store := &mocks.MockUnitOfWork{Repos: txRepos(blockWriter, new(mocks.MockTransactionWriter))}
+	store.On("WithTx", ctx).Return(nil)
+
+	return indexer.BlockIndexerDeps{RPC: mockRPC, Store: store}, store
+}
+
+// recordingStage returns a stage that appends its name to ran and fails with errs in turn
+func recordingStage(name string, ran *[]string, errs ...error) indexer.Stage {
+	return indexer.Stage{
+		Name: name,
+		Run: func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+			*ran = append(*ran, name)
+			if len(errs) == 0 {
+				return nil
+			}
+			err := errs[0]
+			errs = errs[1:]
+			return err
+		},
+	}
+}
+
+func TestPipeline_RunsStagesInOrder(t *testing.T) {
+	ctx := context.Background()
+	blockDeps, store := pipelineTestDeps(ctx)
+
+	var ran []string
+	blockDeps.Hooks = []indexer.BlockHook{
+		func(ctx context.Context, repos interfaces.Repos, block *interfaces.Block) error {
+			ran = append(ran, "hook")
+			return nil
+		},
+	}
+
+	pipeline := indexer.NewPipeline(indexer.PipelineDeps{
+		Blocks: blockDeps,
+		Stages: []indexer.Stage{
+			recordingStage("receipts", &ran),
+			recordingStage("dag", &ran),
+		},
+	})
+
+	_, err := pipeline.IndexBlock(ctx, big.NewInt(100))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hook", "receipts", "dag"}, ran)
+	store.AssertNumberOfCalls(t, "WithTx", 1)
+}
+
+func TestPipeline_FailBlockPolicy(t *testing.T) {
+	ctx := context.Background()
+	blockDeps, _ := pipelineTestDeps(ctx)
+
+	var ran []string
+	failing := recordingStage("receipts", &ran, assert.AnError)
+	failing.OnError = indexer.FailBlock
+
+	pipeline := indexer.NewPipeline(indexer.PipelineDeps{
+		Blocks: blockDeps,
+		Stages: []indexer.Stage{failing, recordingStage("dag", &ran)},
+	})
+
+	_, err := pipeline.IndexBlock(ctx, big.NewInt(100))
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Contains(t, err.Error(), "stage receipts")
+	assert.Equal(t, []string{"receipts"}, ran, "later stages must not run")
+}
+
+func TestPipeline_SkipStagePolicy(t *testing.T) {
+	ctx := context.Background()
+	blockDeps, _ := pipelineTestDeps(ctx)
+
+	var ran []string
+	failing := recordingStage("dag", &ran, assert.AnError)
+	failing.OnError = indexer.SkipStage
+
+	pipeline := indexer.NewPipeline(indexer.PipelineDeps{
+		Blocks: blockDeps,
+		Stages: []indexer.Stage{failing, recordingStage("receipts", &ran)},
+	})
+
+	_, err := pipeline.IndexBlock(ctx, big.NewInt(100))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dag", "receipts"}, ran)
+}
+
+func TestPipeline_Retries(t *testing.T) {
+	ctx := context.Background()
+	blockDeps, _ := pipelineTestDeps(ctx)
+
+	var ran []string
+	flaky := recordingStage("receipts", &ran, errors.New("timeout"), errors.New("timeout"))
+	flaky.Retries = 2
+
+	pipeline := indexer.NewPipeline(indexer.PipelineDeps{
+		Blocks: blockDeps,
+		Stages: []indexer.Stage{flaky},
+	})
+
+	_, err := pipeline.IndexBlock(ctx, big.NewInt(100))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"receipts", "receipts", "receipts"}, ran)
+}
+
+func TestPipeline_StagesRunInSavepoints(t *testing.T) {
+	ctx := context.Background()
+	blockDeps, store := pipelineTestDeps(ctx)
+
+	nested := &mocks.MockUnitOfWork{}
+	nested.On("WithTx", ctx).Return(nil)
+	store.Repos.Nested = nested
+
+	var ran []string
+	failing := recordingStage("dag", &ran, assert.AnError)
+	failing.Retries = 1
+	failing.OnError = indexer.SkipStage
+
+	pipeline := indexer.NewPipeline(indexer.PipelineDeps{
+		Blocks: blockDeps,
+		Stages: []indexer.Stage{failing, recordingStage("receipts", &ran)},
+	})
+
+	_, err := pipeline.IndexBlock(ctx, big.NewInt(100))
+
+	assert.NoError(t, err)
+	nested.AssertNumberOfCalls(t, "WithTx", 3) // Two dag attempts, one receipts run
+}
+
+func TestParseErrorPolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    indexer.ErrorPolicy
+		wantErr bool
+	}{
+		{input: "fail", want: indexer.FailBlock},
+		{input: "skip", want: indexer.SkipStage},
+		{input: "ignore", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := indexer.ParseErrorPolicy(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.input, got.String())
+		})
+	}
+}
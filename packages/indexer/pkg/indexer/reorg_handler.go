@@ -0,0 +1,221 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// defaultMaxReorgDepth bounds the selected-parent walk when looking for the fork point
+const defaultMaxReorgDepth = 1000
+
+// ReorgHandlerDeps contains dependencies for ReorgHandler (ISP)
+type ReorgHandlerDeps struct {
+	Blocks      interfaces.BlockReader
+	Chain       interfaces.ChainBlockReader
+	BlockDB     interfaces.BlockWriter
+	TxDB        interfaces.TransactionAcceptanceWriter
+	InclusionDB interfaces.TransactionInclusionWriter // Optional: re-settles inclusions of the affected blocks
+	Events      interfaces.ReorgEventPublisher
+	MaxDepth    int
+	Logger      *zap.Logger
+}
+
+// ReorgHandler keeps is_chain_block in sync with the virtual selected-parent chain
+type ReorgHandler struct {
+	blocks      interfaces.BlockReader
+	chain       interfaces.ChainBlockReader
+	blockDB     interfaces.BlockWriter
+	txDB        interfaces.TransactionAcceptanceWriter
+	inclusionDB interfaces.TransactionInclusionWriter
+	events      interfaces.ReorgEventPublisher
+	maxDepth    int
+	logger      *zap.Logger
+}
+
+// NewReorgHandler creates a new ReorgHandler
+func NewReorgHandler(deps ReorgHandlerDeps) *ReorgHandler {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	maxDepth := deps.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxReorgDepth
+	}
+
+	return &ReorgHandler{
+		blocks:      deps.Blocks,
+		chain:       deps.Chain,
+		blockDB:     deps.BlockDB,
+		txDB:        deps.TxDB,
+		inclusionDB: deps.InclusionDB,
+		events:      deps.Events,
+		maxDepth:    maxDepth,
+		logger:      logger,
+	}
+}
+
+// withRepos returns a copy of the handler that reads and writes through the unit of work's repositories
+func (rh *ReorgHandler) withRepos(repos interfaces.Repos) *ReorgHandler {
+	bound := *rh
+	bound.blocks = repos.Blocks
+	bound.chain = repos.Blocks
+	bound.blockDB = repos.Blocks
+	bound.txDB = repos.Transactions
+	bound.inclusionDB = repos.Transactions
+	bound.events = repos.ReorgEvents
+	return &bound
+}
+
+// HandleChainBlock reconciles the indexed chain with a newly indexed chain block.
+// It walks the block's selected-parent chain back to the last indexed chain block
+// (the fork point), flips is_chain_block for blocks that left or joined the chain,
+// re-derives transaction acceptance for them and publishes a reorg event.
+// Returns nil if the chain did not change.
+func (rh *ReorgHandler) HandleChainBlock(ctx context.Context, tip *domain.Block) (*domain.ReorgEvent, error) {
+	if !tip.IsChainBlock {
+		return nil, nil
+	}
+
+	forkPoint, added, err := rh.findForkPoint(ctx, tip)
+	if err != nil {
+		return nil, err
+	}
+
+	if forkPoint == nil {
+		rh.logger.Debug("selected parent chain not indexed, skipping reorg check",
+			zap.String("hash", tip.Hash.String()))
+		return nil, nil
+	}
+
+	candidates, err := rh.chain.GetChainBlocksAboveBlueScore(ctx, forkPoint.BlueScore)
+	if err != nil {
+		return nil, fmt.Errorf("load chain blocks: %w", err)
+	}
+
+	onNewChain := make(map[domain.Hash]bool, len(added))
+	for _, hash := range added {
+		onNewChain[hash] = true
+	}
+
+	var removed []domain.Hash
+	for _, candidate := range candidates {
+		if onNewChain[candidate.Hash] {
+			continue
+		}
+
+		if candidate.BlueScore > tip.BlueScore {
+			// A heavier chain block was already indexed; the tip is stale information
+			rh.logger.Debug("heavier chain block already indexed, skipping reorg",
+				zap.String("hash", tip.Hash.String()),
+				zap.String("heavier", candidate.Hash.String()))
+			return nil, nil
+		}
+
+		removed = append(removed, candidate.Hash)
+	}
+
+	// The tip itself is already stored as a chain block
+	joined := added[1:]
+
+	if len(removed) == 0 && len(joined) == 0 {
+		return nil, nil
+	}
+
+	event := &domain.ReorgEvent{
+		ForkPoint:          forkPoint.Hash,
+		ForkPointBlueScore: forkPoint.BlueScore,
+		Removed:            removed,
+		Added:              joined,
+	}
+
+	if err := rh.apply(ctx, event); err != nil {
+		return nil, err
+	}
+
+	rh.logger.Info("virtual chain changed",
+		zap.String("tip", tip.Hash.String()),
+		zap.String("forkPoint", event.ForkPoint.String()),
+		zap.Int("removed", len(event.Removed)),
+		zap.Int("added", len(event.Added)))
+
+	return event, nil
+}
+
+// findForkPoint walks selected parents from the tip until it reaches an indexed chain block.
+// Returns the fork point and the new chain segment, tip first.
+func (rh *ReorgHandler) findForkPoint(ctx context.Context, tip *domain.Block) (*domain.Block, []domain.Hash, error) {
+	segment := []domain.Hash{tip.Hash}
+	current := tip.SelectedParent
+
+	for depth := 0; current != ""; depth++ {
+		if depth >= rh.maxDepth {
+			return nil, nil, fmt.Errorf("no fork point within %d blocks of %s", rh.maxDepth, tip.Hash)
+		}
+
+		block, err := rh.blocks.GetBlockByHash(ctx, current)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, segment, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("load selected parent %s: %w", current, err)
+		}
+
+		if block.IsChainBlock {
+			return block, segment, nil
+		}
+
+		segment = append(segment, block.Hash)
+		current = block.SelectedParent
+	}
+
+	return nil, segment, nil
+}
+
+// apply persists the chain change and notifies consumers
+func (rh *ReorgHandler) apply(ctx context.Context, event *domain.ReorgEvent) error {
+	leftChain, joinedChain := false, true
+
+	for _, hash := range event.Removed {
+		if err := rh.blockDB.UpdateBlock(ctx, hash, domain.BlockUpdate{IsChainBlock: &leftChain}); err != nil {
+			return fmt.Errorf("unmark chain block %s: %w", hash, err)
+		}
+	}
+
+	for _, hash := range event.Added {
+		if err := rh.blockDB.UpdateBlock(ctx, hash, domain.BlockUpdate{IsChainBlock: &joinedChain}); err != nil {
+			return fmt.Errorf("mark chain block %s: %w", hash, err)
+		}
+	}
+
+	updated, err := rh.txDB.RecomputeTransactionAcceptance(ctx, event.AffectedBlocks())
+	if err != nil {
+		return fmt.Errorf("recompute transaction acceptance: %w", err)
+	}
+
+	rh.logger.Debug("transaction acceptance recomputed",
+		zap.Int64("transactions", updated))
+
+	if rh.inclusionDB != nil {
+		settled, err := rh.inclusionDB.RecomputeTransactionInclusions(ctx, event.AffectedBlocks())
+		if err != nil {
+			return fmt.Errorf("recompute transaction inclusions: %w", err)
+		}
+
+		rh.logger.Debug("transaction inclusions recomputed",
+			zap.Int64("inclusions", settled))
+	}
+
+	if err := rh.events.PublishReorg(ctx, event); err != nil {
+		return fmt.Errorf("publish reorg event: %w", err)
+	}
+
+	return nil
+}
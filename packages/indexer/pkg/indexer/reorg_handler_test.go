@@ -0,0 +1,180 @@
+package indexer_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
+)
+
+type reorgMocks struct {
+	blocks *mocks.MockBlockReader
+	chain  *mocks.MockChainBlockReader
+	blockW *mocks.MockBlockWriter
+	txW    *mocks.MockTransactionAcceptanceWriter
+	incW   *mocks.MockTransactionInclusionWriter
+	events *mocks.MockReorgEventPublisher
+}
+
+func newReorgHandler() (*indexer.ReorgHandler, *reorgMocks) {
+	m := &reorgMocks{
+		blocks: new(mocks.MockBlockReader),
+		chain:  new(mocks.MockChainBlockReader),
+		blockW: new(mocks.MockBlockWriter),
+		txW:    new(mocks.MockTransactionAcceptanceWriter),
+		incW:   new(mocks.MockTransactionInclusionWriter),
+		events: new(mocks.MockReorgEventPublisher),
+	}
+
+	rh := indexer.NewReorgHandler(indexer.ReorgHandlerDeps{
+		Blocks:      m.blocks,
+		Chain:       m.chain,
+		BlockDB:     m.blockW,
+		TxDB:        m.txW,
+		InclusionDB: m.incW,
+		Events:      m.events,
+	})
+
+	return rh, m
+}
+
+func hashOf(c string) domain.Hash {
+	return domain.Hash("0x" + strings.Repeat(c, 64))
+}
+
+func chainUpdate(isChainBlock bool) domain.BlockUpdate {
+	return domain.BlockUpdate{IsChainBlock: &isChainBlock}
+}
+
+func TestReorgHandler_HandleChainBlock_NotChainBlock(t *testing.T) {
+	rh, m := newReorgHandler()
+
+	event, err := rh.HandleChainBlock(context.Background(), &domain.Block{
+		Hash:         hashOf("a"),
+		IsChainBlock: false,
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, event)
+	m.blocks.AssertNotCalled(t, "GetBlockByHash", mock.Anything, mock.Anything)
+}
+
+func TestReorgHandler_HandleChainBlock_ChainExtension(t *testing.T) {
+	rh, m := newReorgHandler()
+	ctx := context.Background()
+
+	parent := &domain.Block{Hash: hashOf("a"), BlueScore: 10, IsChainBlock: true}
+	tip := &domain.Block{Hash: hashOf("b"), BlueScore: 11, IsChainBlock: true, SelectedParent: parent.Hash}
+
+	m.blocks.On("GetBlockByHash", ctx, parent.Hash).Return(parent, nil)
+	m.chain.On("GetChainBlocksAboveBlueScore", ctx, uint64(10)).
+		Return([]*domain.Block{tip}, nil)
+
+	event, err := rh.HandleChainBlock(ctx, tip)
+
+	require.NoError(t, err)
+	assert.Nil(t, event)
+	m.blockW.AssertNotCalled(t, "UpdateBlock", mock.Anything, mock.Anything, mock.Anything)
+	m.events.AssertNotCalled(t, "PublishReorg", mock.Anything, mock.Anything)
+}
+
+func TestReorgHandler_HandleChainBlock_Reorg(t *testing.T) {
+	rh, m := newReorgHandler()
+	ctx := context.Background()
+
+	// Old chain: G <- A <- B. New chain: G <- X <- C
+	g := &domain.Block{Hash: hashOf("1"), BlueScore: 10, IsChainBlock: true}
+	a := &domain.Block{Hash: hashOf("a"), BlueScore: 11, IsChainBlock: true, SelectedParent: g.Hash}
+	b := &domain.Block{Hash: hashOf("b"), BlueScore: 12, IsChainBlock: true, SelectedParent: a.Hash}
+	x := &domain.Block{Hash: hashOf("e"), BlueScore: 12, IsChainBlock: false, SelectedParent: g.Hash}
+	c := &domain.Block{Hash: hashOf("c"), BlueScore: 13, IsChainBlock: true, SelectedParent: x.Hash}
+
+	m.blocks.On("GetBlockByHash", ctx, x.Hash).Return(x, nil)
+	m.blocks.On("GetBlockByHash", ctx, g.Hash).Return(g, nil)
+	m.chain.On("GetChainBlocksAboveBlueScore", ctx, uint64(10)).
+		Return([]*domain.Block{a, b, c}, nil)
+
+	m.blockW.On("UpdateBlock", ctx, a.Hash, chainUpdate(false)).Return(nil)
+	m.blockW.On("UpdateBlock", ctx, b.Hash, chainUpdate(false)).Return(nil)
+	m.blockW.On("UpdateBlock", ctx, x.Hash, chainUpdate(true)).Return(nil)
+	m.txW.On("RecomputeTransactionAcceptance", ctx, []domain.Hash{a.Hash, b.Hash, x.Hash}).
+		Return(int64(5), nil)
+	m.incW.On("RecomputeTransactionInclusions", ctx, []domain.Hash{a.Hash, b.Hash, x.Hash}).
+		Return(int64(7), nil)
+	m.events.On("PublishReorg", ctx, mock.AnythingOfType("*domain.ReorgEvent")).Return(nil)
+
+	event, err := rh.HandleChainBlock(ctx, c)
+
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, g.Hash, event.ForkPoint)
+	assert.Equal(t, uint64(10), event.ForkPointBlueScore)
+	assert.Equal(t, []domain.Hash{a.Hash, b.Hash}, event.Removed)
+	assert.Equal(t, []domain.Hash{x.Hash}, event.Added)
+	m.blockW.AssertExpectations(t)
+	m.txW.AssertExpectations(t)
+	m.incW.AssertExpectations(t)
+	m.events.AssertExpectations(t)
+}
+
+func TestReorgHandler_HandleChainBlock_HeavierChainIndexed(t *testing.T) {
+	rh, m := newReorgHandler()
+	ctx := context.Background()
+
+	parent := &domain.Block{Hash: hashOf("a"), BlueScore: 10, IsChainBlock: true}
+	tip := &domain.Block{Hash: hashOf("b"), BlueScore: 11, IsChainBlock: true, SelectedParent: parent.Hash}
+	heavier := &domain.Block{Hash: hashOf("c"), BlueScore: 12, IsChainBlock: true}
+
+	m.blocks.On("GetBlockByHash", ctx, parent.Hash).Return(parent, nil)
+	m.chain.On("GetChainBlocksAboveBlueScore", ctx, uint64(10)).
+		Return([]*domain.Block{tip, heavier}, nil)
+
+	event, err := rh.HandleChainBlock(ctx, tip)
+
+	require.NoError(t, err)
+	assert.Nil(t, event)
+	m.blockW.AssertNotCalled(t, "UpdateBlock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReorgHandler_HandleChainBlock_SelectedParentNotIndexed(t *testing.T) {
+	rh, m := newReorgHandler()
+	ctx := context.Background()
+
+	tip := &domain.Block{Hash: hashOf("b"), BlueScore: 11, IsChainBlock: true, SelectedParent: hashOf("a")}
+
+	m.blocks.On("GetBlockByHash", ctx, hashOf("a")).
+		Return(nil, fmt.Errorf("block %w: %s", domain.ErrNotFound, hashOf("a")))
+
+	event, err := rh.HandleChainBlock(ctx, tip)
+
+	require.NoError(t, err)
+	assert.Nil(t, event)
+	m.chain.AssertNotCalled(t, "GetChainBlocksAboveBlueScore", mock.Anything, mock.Anything)
+}
+
+func TestReorgHandler_HandleChainBlock_UpdateFailure(t *testing.T) {
+	rh, m := newReorgHandler()
+	ctx := context.Background()
+
+	g := &domain.Block{Hash: hashOf("1"), BlueScore: 10, IsChainBlock: true}
+	a := &domain.Block{Hash: hashOf("a"), BlueScore: 11, IsChainBlock: true, SelectedParent: g.Hash}
+	c := &domain.Block{Hash: hashOf("c"), BlueScore: 11, IsChainBlock: true, SelectedParent: g.Hash}
+
+	m.blocks.On("GetBlockByHash", ctx, g.Hash).Return(g, nil)
+	m.chain.On("GetChainBlocksAboveBlueScore", ctx, uint64(10)).
+		Return([]*domain.Block{a, c}, nil)
+	m.blockW.On("UpdateBlock", ctx, a.Hash, mock.Anything).Return(assert.AnError)
+
+	_, err := rh.HandleChainBlock(ctx, c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unmark chain block")
+	m.events.AssertNotCalled(t, "PublishReorg", mock.Anything, mock.Anything)
+}
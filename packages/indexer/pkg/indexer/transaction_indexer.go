@@ -12,18 +12,25 @@ import (
 
 // TransactionIndexerDeps contains dependencies for TransactionIndexer (ISP)
 type TransactionIndexerDeps struct {
-	RPC    interfaces.ReceiptReader
-	TxDB   interfaces.TransactionWriter
-	LogDB  interfaces.LogWriter
-	Logger *zap.Logger
+	RPC       interfaces.ReceiptReader
+	BatchRPC  interfaces.BatchReceiptReader // Optional: fetches a block's receipts in one round trip
+	Workers   int                           // Receipts of a block fetched concurrently without BatchRPC; defaults to 1
+	TxDB      interfaces.TransactionWriter
+	LogDB     interfaces.LogWriter
+	AnomalyDB interfaces.DecodeAnomalyWriter // Optional: records logs dropped by lenient RPC decoding
+	Logger    *zap.Logger
 }
 
 // TransactionIndexer indexes transaction receipts and logs
 type TransactionIndexer struct {
-	rpc    interfaces.ReceiptReader
-	txDB   interfaces.TransactionWriter
-	logDB  interfaces.LogWriter
-	logger *zap.Logger
+	rpc       interfaces.ReceiptReader
+	batchRPC  interfaces.BatchReceiptReader
+	workers   int
+	txDB      interfaces.TransactionWriter
+	logDB     interfaces.LogWriter
+	anomalyDB interfaces.DecodeAnomalyWriter
+	strict    bool // Log failures abort the receipt instead of being skipped
+	logger    *zap.Logger
 }
 
 // NewTransactionIndexer creates a new TransactionIndexer
@@ -33,11 +40,19 @@ func NewTransactionIndexer(deps TransactionIndexerDeps) *TransactionIndexer {
 		logger = zap.NewNop()
 	}
 
+	workers := deps.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
 	return &TransactionIndexer{
-		rpc:    deps.RPC,
-		txDB:   deps.TxDB,
-		logDB:  deps.LogDB,
-		logger: logger,
+		rpc:       deps.RPC,
+		batchRPC:  deps.BatchRPC,
+		workers:   workers,
+		txDB:      deps.TxDB,
+		logDB:     deps.LogDB,
+		anomalyDB: deps.AnomalyDB,
+		logger:    logger,
 	}
 }
 
@@ -46,35 +61,77 @@ func (ti *TransactionIndexer) IndexTransactionReceipt(
 	ctx context.Context,
 	txHash common.Hash,
 ) error {
-	// 1. Fetch receipt from RPC
+	receipt, err := ti.fetchReceipt(ctx, txHash)
+	if err != nil || receipt == nil {
+		return err
+	}
+
+	// The block's timestamp is not known here; the repository takes it from the stored block
+	block := &interfaces.Block{Hash: receipt.BlockHash, Number: receipt.BlockNumber}
+
+	return ti.saveReceipt(ctx, txHash, receipt, block)
+}
+
+// fetchReceipt fetches a receipt from RPC; a missing receipt is returned as nil
+func (ti *TransactionIndexer) fetchReceipt(
+	ctx context.Context,
+	txHash common.Hash,
+) (*interfaces.Receipt, error) {
 	receipt, err := ti.rpc.GetTransactionReceipt(ctx, txHash)
 	if err != nil {
 		ti.logger.Error("failed to fetch receipt",
 			zap.String("txHash", txHash.Hex()),
 			zap.Error(err))
-		return fmt.Errorf("fetch receipt: %w", err)
+		return nil, fmt.Errorf("fetch receipt: %w", err)
 	}
 
 	if receipt == nil {
 		ti.logger.Debug("receipt not found",
 			zap.String("txHash", txHash.Hex()))
-		return nil // Not an error, just not found
+		return nil, nil // Not an error, just not found
+	}
+
+	return receipt, nil
+}
+
+// saveReceipt stores a fetched receipt's status and logs; block is the block that includes it
+func (ti *TransactionIndexer) saveReceipt(
+	ctx context.Context,
+	txHash common.Hash,
+	receipt *interfaces.Receipt,
+	block *interfaces.Block,
+) error {
+	// common.Hash renders lowercase hex, which is already canonical
+	hash := domain.Hash(txHash.Hex())
+
+	blockHash, err := parseOptionalHash(block.Hash)
+	if err != nil {
+		return fmt.Errorf("invalid block hash: %w", err)
 	}
 
-	// 2. Update transaction status
-	if err := ti.txDB.UpdateTransactionStatus(
-		ctx,
-		txHash.Hex(),
-		receipt.Status,
-		receipt.GasUsed,
-	); err != nil {
-		return fmt.Errorf("update transaction status: %w", err)
+	// 2. Record receipt fields on the transaction
+	domainReceipt, err := ti.convertRPCReceiptToDomain(receipt, hash)
+	if err != nil {
+		return fmt.Errorf("invalid receipt: %w", err)
+	}
+	if err := domainReceipt.Validate(); err != nil {
+		return fmt.Errorf("invalid receipt: %w", err)
+	}
+
+	if err := ti.txDB.SaveReceipt(ctx, domainReceipt); err != nil {
+		return fmt.Errorf("save receipt: %w", err)
 	}
 
 	// 3. Save logs (even for failed transactions, logs might exist)
 	for i, rpcLog := range receipt.Logs {
-		log := ti.convertRPCLogToDomain(rpcLog, txHash.Hex(), uint64(i))
-		if err := ti.logDB.SaveLog(ctx, log); err != nil {
+		log, err := ti.convertRPCLogToDomain(rpcLog, hash, uint64(i), blockHash, block)
+		if err == nil {
+			err = ti.logDB.SaveLog(ctx, log)
+		}
+		if err != nil {
+			if ti.strict {
+				return fmt.Errorf("save log %d: %w", i, err)
+			}
 			ti.logger.Warn("failed to save log",
 				zap.String("txHash", txHash.Hex()),
 				zap.Int("logIndex", i),
@@ -83,6 +140,19 @@ func (ti *TransactionIndexer) IndexTransactionReceipt(
 		}
 	}
 
+	// 4. Record logs the RPC layer could not decode
+	if len(receipt.Anomalies) > 0 {
+		if ti.anomalyDB == nil {
+			ti.logger.Warn("dropped logs not recorded, no anomaly writer configured",
+				zap.String("txHash", txHash.Hex()),
+				zap.Int("dropped", len(receipt.Anomalies)))
+		} else {
+			if err := saveDecodeAnomalies(ctx, ti.anomalyDB, blockHash, &hash, receipt.Anomalies); err != nil {
+				return err
+			}
+		}
+	}
+
 	ti.logger.Debug("transaction receipt indexed",
 		zap.String("txHash", txHash.Hex()),
 		zap.Int("status", receipt.Status),
@@ -91,18 +161,160 @@ func (ti *TransactionIndexer) IndexTransactionReceipt(
 	return nil
 }
 
-// convertRPCLogToDomain converts interfaces.Log to domain.Log
+// IndexBlockReceipts indexes receipts and logs for every transaction in a block
+// using the unit of work's repositories. Any failure, including a receipt the node
+// does not return, aborts the whole block.
+// It matches the BlockHook signature so it can run inside BlockIndexer's transaction.
+func (ti *TransactionIndexer) IndexBlockReceipts(
+	ctx context.Context,
+	repos interfaces.Repos,
+	block *interfaces.Block,
+) error {
+	bound := *ti
+	bound.txDB = repos.Transactions
+	bound.logDB = repos.Logs
+	if ti.anomalyDB != nil {
+		bound.anomalyDB = repos.Anomalies
+	}
+	bound.strict = true
+
+	if ti.batchRPC != nil {
+		return bound.indexBlockReceiptsBatch(ctx, block)
+	}
+
+	return bound.indexBlockReceiptsConcurrent(ctx, block)
+}
+
+// indexBlockReceiptsConcurrent fetches the receipts of a block on up to workers
+// goroutines and saves them in transaction order
+func (ti *TransactionIndexer) indexBlockReceiptsConcurrent(ctx context.Context, block *interfaces.Block) error {
+	txs := block.Transactions
+	receipts, err := fetchAll(len(txs), ti.workers, func(i int) (*interfaces.Receipt, error) {
+		receipt, err := ti.fetchReceipt(ctx, common.HexToHash(txs[i].Hash))
+		if err != nil {
+			return nil, fmt.Errorf("index receipt %s: %w", txs[i].Hash, err)
+		}
+		return receipt, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, receipt := range receipts {
+		if receipt == nil {
+			if ti.strict {
+				return fmt.Errorf("receipt %s not found", txs[i].Hash)
+			}
+			continue
+		}
+
+		if err := ti.saveReceipt(ctx, common.HexToHash(txs[i].Hash), receipt, block); err != nil {
+			return fmt.Errorf("index receipt %s: %w", txs[i].Hash, err)
+		}
+	}
+
+	return nil
+}
+
+// indexBlockReceiptsBatch fetches all receipts of a block in one batch and saves them
+func (ti *TransactionIndexer) indexBlockReceiptsBatch(ctx context.Context, block *interfaces.Block) error {
+	if len(block.Transactions) == 0 {
+		return nil
+	}
+
+	hashes := make([]common.Hash, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		hashes[i] = common.HexToHash(tx.Hash)
+	}
+
+	receipts, err := ti.batchRPC.GetTransactionReceipts(ctx, hashes)
+	if err != nil {
+		ti.logger.Error("failed to fetch receipts",
+			zap.String("blockHash", block.Hash),
+			zap.Error(err))
+		return fmt.Errorf("fetch receipts: %w", err)
+	}
+
+	for i, receipt := range receipts {
+		if receipt == nil {
+			if ti.strict {
+				return fmt.Errorf("receipt %s not found", hashes[i].Hex())
+			}
+			ti.logger.Debug("receipt not found",
+				zap.String("txHash", hashes[i].Hex()))
+			continue
+		}
+
+		if err := ti.saveReceipt(ctx, hashes[i], receipt, block); err != nil {
+			return fmt.Errorf("index receipt %s: %w", hashes[i].Hex(), err)
+		}
+	}
+
+	return nil
+}
+
+// convertRPCReceiptToDomain converts interfaces.Receipt to domain.Receipt
+func (ti *TransactionIndexer) convertRPCReceiptToDomain(
+	receipt *interfaces.Receipt,
+	txHash domain.Hash,
+) (*domain.Receipt, error) {
+	contractAddress, err := domain.ParseOptionalAddress(receipt.ContractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("contract address: %w", err)
+	}
+
+	return &domain.Receipt{
+		TransactionHash:   txHash,
+		Status:            receipt.Status,
+		Type:              receipt.Type,
+		GasUsed:           receipt.GasUsed,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		EffectiveGasPrice: receipt.EffectiveGasPrice,
+		ContractAddress:   contractAddress,
+		LogsBloom:         receipt.LogsBloom,
+	}, nil
+}
+
+// convertRPCLogToDomain converts interfaces.Log to domain.Log.
+// position is the log's place in the receipt, used when the node omits logIndex.
+// blockHash is the canonical hash of block, the block the log is stored under.
 func (ti *TransactionIndexer) convertRPCLogToDomain(
 	rpcLog interfaces.Log,
-	txHash string,
-	logIndex uint64,
-) *domain.Log {
+	txHash domain.Hash,
+	position uint64,
+	blockHash domain.Hash,
+	block *interfaces.Block,
+) (*domain.Log, error) {
+	address, err := domain.ParseAddress(rpcLog.Address)
+	if err != nil {
+		return nil, fmt.Errorf("log address %q: %w", rpcLog.Address, err)
+	}
+
+	logIndex := position
+	if rpcLog.LogIndex != nil {
+		logIndex = *rpcLog.LogIndex
+	}
+
+	// The log stays with the including block even when the node reports it under another
+	// block for a re-included transaction: that block may not be indexed yet, and the
+	// transaction row keeps its first block too
+	blockNumber, timestamp := block.Number, block.Timestamp
+	if blockHash == "" && rpcLog.BlockHash != "" {
+		logBlockHash, err := domain.ParseHash(rpcLog.BlockHash)
+		if err != nil {
+			return nil, fmt.Errorf("log block hash %q: %w", rpcLog.BlockHash, err)
+		}
+		blockHash, blockNumber = logBlockHash, rpcLog.BlockNumber
+	}
+
 	return &domain.Log{
 		TransactionHash: txHash,
 		LogIndex:        logIndex,
-		Address:         rpcLog.Address,
+		Address:         address,
 		Topics:          rpcLog.Topics,
 		Data:            rpcLog.Data,
-	}
+		BlockNumber:     blockNumber,
+		BlockHash:       blockHash,
+		Timestamp:       timestamp,
+	}, nil
 }
-
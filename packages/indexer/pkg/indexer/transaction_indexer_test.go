@@ -2,12 +2,14 @@ package indexer_test
 
 import (
 	"context"
+	"math/big"
 	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/domain"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/indexer"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/tests/mocks"
@@ -40,7 +42,7 @@ func TestTransactionIndexer_IndexTransactionReceipt(t *testing.T) {
 	mockRPC.On("GetTransactionReceipt", ctx, txHash).
 		Return(expectedReceipt, nil)
 
-	mockTxWriter.On("UpdateTransactionStatus", ctx, txHash.Hex(), 1, uint64(21000)).
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{TransactionHash: domain.Hash(txHash.Hex()), Status: 1, GasUsed: 21000}).
 		Return(nil)
 
 	mockLogWriter.On("SaveLog", ctx, mock.AnythingOfType("*domain.Log")).
@@ -64,6 +66,70 @@ func TestTransactionIndexer_IndexTransactionReceipt(t *testing.T) {
 	mockLogWriter.AssertExpectations(t)
 }
 
+func TestTransactionIndexer_IndexTransactionReceipt_ReceiptFields(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+	mockLogWriter := new(mocks.MockLogWriter)
+
+	ctx := context.Background()
+	txHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+	// The node reports mixed-case hex; the domain values must be canonical
+	blockHash := "0x" + strings.Repeat("D", 64)
+	contract := "0x" + strings.Repeat("B", 40)
+	canonicalContract := domain.Address("0x" + strings.Repeat("b", 40))
+	bloom := "0x" + strings.Repeat("0", 512)
+	logIndex := uint64(7)
+
+	mockRPC.On("GetTransactionReceipt", ctx, txHash).
+		Return(&interfaces.Receipt{
+			TransactionHash:   txHash.Hex(),
+			BlockHash:         blockHash,
+			BlockNumber:       100,
+			Status:            1,
+			Type:              2,
+			GasUsed:           30000,
+			CumulativeGasUsed: 50000,
+			EffectiveGasPrice: big.NewInt(12),
+			ContractAddress:   &contract,
+			LogsBloom:         bloom,
+			Logs: []interfaces.Log{
+				{Address: contract, LogIndex: &logIndex, BlockHash: blockHash, BlockNumber: 100},
+			},
+		}, nil)
+
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{
+		TransactionHash:   domain.Hash(txHash.Hex()),
+		Status:            1,
+		Type:              2,
+		GasUsed:           30000,
+		CumulativeGasUsed: 50000,
+		EffectiveGasPrice: big.NewInt(12),
+		ContractAddress:   &canonicalContract,
+		LogsBloom:         bloom,
+	}).Return(nil)
+
+	// The node's block-wide log index replaces the position in the receipt
+	mockLogWriter.On("SaveLog", ctx, &domain.Log{
+		TransactionHash: domain.Hash(txHash.Hex()),
+		LogIndex:        7,
+		Address:         canonicalContract,
+		BlockNumber:     100,
+		BlockHash:       domain.Hash("0x" + strings.Repeat("d", 64)),
+	}).Return(nil)
+
+	idx := indexer.NewTransactionIndexer(indexer.TransactionIndexerDeps{
+		RPC:   mockRPC,
+		TxDB:  mockTxWriter,
+		LogDB: mockLogWriter,
+	})
+
+	err := idx.IndexTransactionReceipt(ctx, txHash)
+
+	assert.NoError(t, err)
+	mockTxWriter.AssertExpectations(t)
+	mockLogWriter.AssertExpectations(t)
+}
+
 func TestTransactionIndexer_IndexTransactionReceipt_RPCFailure(t *testing.T) {
 	mockRPC := new(mocks.MockPhoenixClient)
 	mockTxWriter := new(mocks.MockTransactionWriter)
@@ -132,7 +198,7 @@ func TestTransactionIndexer_IndexTransactionReceipt_FailedTransaction(t *testing
 	mockRPC.On("GetTransactionReceipt", ctx, txHash).
 		Return(failedReceipt, nil)
 
-	mockTxWriter.On("UpdateTransactionStatus", ctx, txHash.Hex(), 0, uint64(21000)).
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{TransactionHash: domain.Hash(txHash.Hex()), Status: 0, GasUsed: 21000}).
 		Return(nil)
 
 	idx := indexer.NewTransactionIndexer(indexer.TransactionIndexerDeps{
@@ -172,7 +238,7 @@ func TestTransactionIndexer_IndexTransactionReceipt_MultipleLogs(t *testing.T) {
 	mockRPC.On("GetTransactionReceipt", ctx, txHash).
 		Return(receipt, nil)
 
-	mockTxWriter.On("UpdateTransactionStatus", ctx, txHash.Hex(), 1, uint64(50000)).
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{TransactionHash: domain.Hash(txHash.Hex()), Status: 1, GasUsed: 50000}).
 		Return(nil)
 
 	mockLogWriter.On("SaveLog", ctx, mock.AnythingOfType("*domain.Log")).
@@ -211,7 +277,7 @@ func TestTransactionIndexer_IndexTransactionReceipt_LogSaveFailure(t *testing.T)
 	mockRPC.On("GetTransactionReceipt", ctx, txHash).
 		Return(receipt, nil)
 
-	mockTxWriter.On("UpdateTransactionStatus", ctx, txHash.Hex(), 1, uint64(21000)).
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{TransactionHash: domain.Hash(txHash.Hex()), Status: 1, GasUsed: 21000}).
 		Return(nil)
 
 	// Log save fails, but should continue
@@ -231,3 +297,324 @@ func TestTransactionIndexer_IndexTransactionReceipt_LogSaveFailure(t *testing.T)
 	assert.NoError(t, err)
 }
 
+func TestTransactionIndexer_IndexBlockReceipts_LogSaveFailureAborts(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+	mockLogWriter := new(mocks.MockLogWriter)
+
+	ctx := context.Background()
+	txHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+
+	mockRPC.On("GetTransactionReceipt", ctx, txHash).
+		Return(&interfaces.Receipt{
+			TransactionHash: txHash.Hex(),
+			Status:          1,
+			GasUsed:         21000,
+			Logs: []interfaces.Log{
+				{Address: "0x" + strings.Repeat("b", 40), Topics: []string{"0xtopic1"}},
+			},
+		}, nil)
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{TransactionHash: domain.Hash(txHash.Hex()), Status: 1, GasUsed: 21000}).
+		Return(nil)
+	mockLogWriter.On("SaveLog", ctx, mock.AnythingOfType("*domain.Log")).
+		Return(assert.AnError)
+
+	// The indexer's own writers must not be used inside a unit of work
+	idx := indexer.NewTransactionIndexer(indexer.TransactionIndexerDeps{
+		RPC:   mockRPC,
+		TxDB:  new(mocks.MockTransactionWriter),
+		LogDB: new(mocks.MockLogWriter),
+	})
+
+	repos := interfaces.Repos{
+		Transactions: struct {
+			interfaces.TransactionReader
+			interfaces.TransactionAcceptanceWriter
+			interfaces.TransactionInclusionReader
+			interfaces.TransactionInclusionWriter
+			*mocks.MockTransactionWriter
+		}{MockTransactionWriter: mockTxWriter},
+		Logs: struct {
+			interfaces.LogReader
+			*mocks.MockLogWriter
+		}{MockLogWriter: mockLogWriter},
+	}
+
+	err := idx.IndexBlockReceipts(ctx, repos, &interfaces.Block{
+		Transactions: []interfaces.Transaction{{Hash: txHash.Hex()}},
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	mockTxWriter.AssertExpectations(t)
+}
+
+func TestTransactionIndexer_IndexBlockReceipts_Batch(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+	mockLogWriter := new(mocks.MockLogWriter)
+
+	ctx := context.Background()
+	hashes := []common.Hash{
+		common.HexToHash("0x" + strings.Repeat("a", 64)),
+		common.HexToHash("0x" + strings.Repeat("b", 64)),
+	}
+
+	mockRPC.On("GetTransactionReceipts", ctx, hashes).
+		Return([]*interfaces.Receipt{
+			{
+				TransactionHash: hashes[0].Hex(),
+				Status:          1,
+				GasUsed:         21000,
+				Logs: []interfaces.Log{
+					{Address: "0x" + strings.Repeat("c", 40), Topics: []string{"0xtopic1"}},
+				},
+			},
+			{
+				TransactionHash: hashes[1].Hex(),
+				Status:          0,
+				GasUsed:         30000,
+			},
+		}, nil)
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{TransactionHash: domain.Hash(hashes[0].Hex()), Status: 1, GasUsed: 21000}).
+		Return(nil)
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{TransactionHash: domain.Hash(hashes[1].Hex()), Status: 0, GasUsed: 30000}).
+		Return(nil)
+	mockLogWriter.On("SaveLog", ctx, mock.AnythingOfType("*domain.Log")).
+		Return(nil)
+
+	idx := indexer.NewTransactionIndexer(indexer.TransactionIndexerDeps{
+		RPC:      mockRPC,
+		BatchRPC: mockRPC,
+	})
+
+	repos := interfaces.Repos{
+		Transactions: struct {
+			interfaces.TransactionReader
+			interfaces.TransactionAcceptanceWriter
+			interfaces.TransactionInclusionReader
+			interfaces.TransactionInclusionWriter
+			*mocks.MockTransactionWriter
+		}{MockTransactionWriter: mockTxWriter},
+		Logs: struct {
+			interfaces.LogReader
+			*mocks.MockLogWriter
+		}{MockLogWriter: mockLogWriter},
+	}
+
+	err := idx.IndexBlockReceipts(ctx, repos, &interfaces.Block{
+		Transactions: []interfaces.Transaction{{Hash: hashes[0].Hex()}, {Hash: hashes[1].Hex()}},
+	})
+
+	assert.NoError(t, err)
+	mockRPC.AssertNotCalled(t, "GetTransactionReceipt", mock.Anything, mock.Anything)
+	mockTxWriter.AssertNumberOfCalls(t, "SaveReceipt", 2)
+	mockLogWriter.AssertNumberOfCalls(t, "SaveLog", 1)
+}
+
+func TestTransactionIndexer_IndexBlockReceipts_MissingReceiptAborts(t *testing.T) {
+	hashes := []common.Hash{
+		common.HexToHash("0x" + strings.Repeat("a", 64)),
+		common.HexToHash("0x" + strings.Repeat("b", 64)),
+	}
+	found := &interfaces.Receipt{TransactionHash: hashes[0].Hex(), Status: 1, GasUsed: 21000}
+
+	tests := []struct {
+		name  string
+		batch bool
+	}{
+		{name: "concurrent"},
+		{name: "batch", batch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRPC := new(mocks.MockPhoenixClient)
+			mockTxWriter := new(mocks.MockTransactionWriter)
+
+			ctx := context.Background()
+
+			deps := indexer.TransactionIndexerDeps{RPC: mockRPC}
+			if tt.batch {
+				deps.BatchRPC = mockRPC
+				mockRPC.On("GetTransactionReceipts", ctx, hashes).
+					Return([]*interfaces.Receipt{found, nil}, nil)
+			} else {
+				mockRPC.On("GetTransactionReceipt", ctx, hashes[0]).Return(found, nil)
+				mockRPC.On("GetTransactionReceipt", ctx, hashes[1]).Return(nil, nil)
+			}
+			mockTxWriter.On("SaveReceipt", ctx, mock.AnythingOfType("*domain.Receipt")).Return(nil)
+
+			repos := interfaces.Repos{
+				Transactions: struct {
+					interfaces.TransactionReader
+					interfaces.TransactionAcceptanceWriter
+					interfaces.TransactionInclusionReader
+					interfaces.TransactionInclusionWriter
+					*mocks.MockTransactionWriter
+				}{MockTransactionWriter: mockTxWriter},
+				Logs: struct {
+					interfaces.LogReader
+					*mocks.MockLogWriter
+				}{MockLogWriter: new(mocks.MockLogWriter)},
+			}
+
+			err := indexer.NewTransactionIndexer(deps).IndexBlockReceipts(ctx, repos, &interfaces.Block{
+				Transactions: []interfaces.Transaction{{Hash: hashes[0].Hex()}, {Hash: hashes[1].Hex()}},
+			})
+
+			// The block is rolled back and retried rather than committed without the receipt
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "receipt "+hashes[1].Hex()+" not found")
+			}
+		})
+	}
+}
+
+func TestTransactionIndexer_IndexBlockReceipts_LogsCarryBlockContext(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+	mockLogWriter := new(mocks.MockLogWriter)
+
+	ctx := context.Background()
+	txHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+	blockHash := "0x" + strings.Repeat("f", 64)
+
+	mockRPC.On("GetTransactionReceipt", ctx, txHash).
+		Return(&interfaces.Receipt{
+			TransactionHash: txHash.Hex(),
+			Status:          1,
+			GasUsed:         21000,
+			// A re-included transaction's log may be reported under another block
+			Logs: []interfaces.Log{
+				{Address: "0x" + strings.Repeat("b", 40), Topics: []string{"0xtopic1"}, BlockHash: "0x" + strings.Repeat("e", 64), BlockNumber: 99},
+			},
+		}, nil)
+	mockTxWriter.On("SaveReceipt", ctx, &domain.Receipt{TransactionHash: domain.Hash(txHash.Hex()), Status: 1, GasUsed: 21000}).
+		Return(nil)
+	// The log stays with the including block
+	mockLogWriter.On("SaveLog", ctx, &domain.Log{
+		TransactionHash: domain.Hash(txHash.Hex()),
+		LogIndex:        0,
+		Address:         domain.Address("0x" + strings.Repeat("b", 40)),
+		Topics:          []string{"0xtopic1"},
+		BlockNumber:     100,
+		BlockHash:       domain.Hash(blockHash),
+		Timestamp:       1706150400000,
+	}).Return(nil)
+
+	idx := indexer.NewTransactionIndexer(indexer.TransactionIndexerDeps{RPC: mockRPC})
+
+	repos := interfaces.Repos{
+		Transactions: struct {
+			interfaces.TransactionReader
+			interfaces.TransactionAcceptanceWriter
+			interfaces.TransactionInclusionReader
+			interfaces.TransactionInclusionWriter
+			*mocks.MockTransactionWriter
+		}{MockTransactionWriter: mockTxWriter},
+		Logs: struct {
+			interfaces.LogReader
+			*mocks.MockLogWriter
+		}{MockLogWriter: mockLogWriter},
+	}
+
+	err := idx.IndexBlockReceipts(ctx, repos, &interfaces.Block{
+		Hash:         blockHash,
+		Number:       100,
+		Timestamp:    1706150400000,
+		Transactions: []interfaces.Transaction{{Hash: txHash.Hex()}},
+	})
+
+	assert.NoError(t, err)
+	mockLogWriter.AssertExpectations(t)
+}
+
+func TestTransactionIndexer_IndexTransactionReceipt_RecordsDecodeAnomalies(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+	mockAnomalyWriter := new(mocks.MockDecodeAnomalyWriter)
+
+	ctx := context.Background()
+	txHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+	blockHash := "0x" + strings.Repeat("d", 64)
+
+	receipt := &interfaces.Receipt{
+		TransactionHash: txHash.Hex(),
+		BlockHash:       blockHash,
+		Status:          1,
+		GasUsed:         21000,
+		Anomalies: []interfaces.DecodeAnomaly{
+			{Kind: interfaces.DecodeKindLog, Index: 0, Raw: `{"data":"0xzz"}`, Reason: "invalid hex string"},
+		},
+	}
+	mockRPC.On("GetTransactionReceipt", ctx, txHash).Return(receipt, nil)
+	mockTxWriter.On("SaveReceipt", ctx, mock.AnythingOfType("*domain.Receipt")).Return(nil)
+	mockAnomalyWriter.On("SaveDecodeAnomaly", ctx, mock.MatchedBy(func(a *domain.DecodeAnomaly) bool {
+		return a.BlockHash.String() == blockHash &&
+			a.TransactionHash != nil && a.TransactionHash.String() == txHash.Hex() &&
+			a.Kind == domain.AnomalyKindLog &&
+			a.ItemIndex == 0
+	})).Return(nil).Once()
+
+	idx := indexer.NewTransactionIndexer(indexer.TransactionIndexerDeps{
+		RPC:       mockRPC,
+		TxDB:      mockTxWriter,
+		LogDB:     new(mocks.MockLogWriter),
+		AnomalyDB: mockAnomalyWriter,
+	})
+
+	err := idx.IndexTransactionReceipt(ctx, txHash)
+	assert.NoError(t, err)
+	mockAnomalyWriter.AssertExpectations(t)
+}
+
+func TestTransactionIndexer_IndexBlockReceipts_ConcurrentFetch(t *testing.T) {
+	mockRPC := new(mocks.MockPhoenixClient)
+	mockTxWriter := new(mocks.MockTransactionWriter)
+	mockLogWriter := new(mocks.MockLogWriter)
+
+	ctx := context.Background()
+	var txs []interfaces.Transaction
+	for _, c := range []string{"1", "2", "3", "4"} {
+		hash := common.HexToHash("0x" + strings.Repeat(c, 64))
+		txs = append(txs, interfaces.Transaction{Hash: hash.Hex()})
+		mockRPC.On("GetTransactionReceipt", ctx, hash).
+			Return(&interfaces.Receipt{TransactionHash: hash.Hex(), Status: 1}, nil)
+	}
+
+	var saved []string
+	mockTxWriter.On("SaveReceipt", ctx, mock.AnythingOfType("*domain.Receipt")).
+		Run(func(args mock.Arguments) {
+			saved = append(saved, args.Get(1).(*domain.Receipt).TransactionHash.String())
+		}).
+		Return(nil)
+
+	idx := indexer.NewTransactionIndexer(indexer.TransactionIndexerDeps{
+		RPC:     mockRPC,
+		Workers: 3,
+	})
+
+	repos := interfaces.Repos{
+		Transactions: struct {
+			interfaces.TransactionReader
+			interfaces.TransactionAcceptanceWriter
+			interfaces.TransactionInclusionReader
+			interfaces.TransactionInclusionWriter
+			*mocks.MockTransactionWriter
+		}{MockTransactionWriter: mockTxWriter},
+		Logs: struct {
+			interfaces.LogReader
+			*mocks.MockLogWriter
+		}{MockLogWriter: mockLogWriter},
+	}
+
+	err := idx.IndexBlockReceipts(ctx, repos, &interfaces.Block{Transactions: txs})
+
+	assert.NoError(t, err)
+	mockRPC.AssertExpectations(t)
+	if assert.Len(t, saved, len(txs)) {
+		for i, tx := range txs {
+			assert.Equal(t, tx.Hash, saved[i]) // Saved in block order
+		}
+	}
+}
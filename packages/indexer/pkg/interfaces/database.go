@@ -10,16 +10,27 @@ import (
 // BlockWriter defines methods for writing blocks (ISP: Write operations only)
 type BlockWriter interface {
 	SaveBlock(ctx context.Context, block *domain.Block) error
-	UpdateBlock(ctx context.Context, hash string, updates map[string]interface{}) error
+	UpdateBlock(ctx context.Context, hash domain.Hash, update domain.BlockUpdate) error
 }
 
 // BlockReader defines methods for reading blocks (ISP: Read operations only)
 type BlockReader interface {
-	GetBlockByHash(ctx context.Context, hash string) (*domain.Block, error)
+	GetBlockByHash(ctx context.Context, hash domain.Hash) (*domain.Block, error)
 	GetBlockByNumber(ctx context.Context, number int64) (*domain.Block, error)
 	GetLatestBlocks(ctx context.Context, limit int) ([]*domain.Block, error)
 }
 
+// ChainBlockReader defines methods for reading the selected-parent chain (ISP: Chain reads only)
+type ChainBlockReader interface {
+	GetChainBlocksAboveBlueScore(ctx context.Context, blueScore uint64) ([]*domain.Block, error)
+}
+
+// DAGGapReader finds DAG blocks that are referenced but not indexed (ISP: Gap detection only)
+type DAGGapReader interface {
+	GetMissingParents(ctx context.Context, fromNumber, toNumber int64) ([]domain.Hash, error)
+	FilterMissingBlocks(ctx context.Context, hashes []domain.Hash) ([]domain.Hash, error)
+}
+
 // BlockStatistics defines methods for block statistics (ISP: Statistics only)
 type BlockStatistics interface {
 	GetBlockCount(ctx context.Context) (int64, error)
@@ -29,13 +40,31 @@ type BlockStatistics interface {
 // TransactionWriter defines methods for writing transactions (ISP: Write operations only)
 type TransactionWriter interface {
 	SaveTransaction(ctx context.Context, tx *domain.Transaction) error
-	UpdateTransactionStatus(ctx context.Context, hash string, status int, gasUsed uint64) error
+	UpdateTransaction(ctx context.Context, hash domain.Hash, update domain.TransactionUpdate) error
+	UpdateTransactionStatus(ctx context.Context, hash domain.Hash, status int, gasUsed uint64) error
+	SaveReceipt(ctx context.Context, receipt *domain.Receipt) error
+}
+
+// TransactionAcceptanceWriter re-derives transaction acceptance after chain changes (ISP: Acceptance only)
+type TransactionAcceptanceWriter interface {
+	RecomputeTransactionAcceptance(ctx context.Context, blockHashes []domain.Hash) (int64, error)
 }
 
 // TransactionReader defines methods for reading transactions (ISP: Read operations only)
 type TransactionReader interface {
-	GetTransactionByHash(ctx context.Context, hash string) (*domain.Transaction, error)
-	GetTransactionsByBlockHash(ctx context.Context, blockHash string) ([]*domain.Transaction, error)
+	GetTransactionByHash(ctx context.Context, hash domain.Hash) (*domain.Transaction, error)
+	GetTransactionsByBlockHash(ctx context.Context, blockHash domain.Hash) ([]*domain.Transaction, error)
+}
+
+// TransactionInclusionWriter settles which block's inclusion of a transaction the chain accepted (ISP: Inclusions only)
+type TransactionInclusionWriter interface {
+	RecomputeTransactionInclusions(ctx context.Context, blockHashes []domain.Hash) (int64, error)
+}
+
+// TransactionInclusionReader defines methods for reading transaction inclusions (ISP: Read operations only)
+type TransactionInclusionReader interface {
+	GetInclusionsByTransactionHash(ctx context.Context, hash domain.Hash) ([]*domain.TransactionInclusion, error)
+	GetInclusionsByBlockHash(ctx context.Context, blockHash domain.Hash) ([]*domain.TransactionInclusion, error)
 }
 
 // LogWriter defines methods for writing logs (ISP: Write operations only)
@@ -45,34 +74,76 @@ type LogWriter interface {
 
 // LogReader defines methods for reading logs (ISP: Read operations only)
 type LogReader interface {
-	GetLogsByTransactionHash(ctx context.Context, txHash string) ([]*domain.Log, error)
-	GetLogsByAddress(ctx context.Context, address string, fromBlock, toBlock int64) ([]*domain.Log, error)
+	GetLogsByTransactionHash(ctx context.Context, txHash domain.Hash) ([]*domain.Log, error)
+	GetLogsByAddress(ctx context.Context, address domain.Address, fromBlock, toBlock int64) ([]*domain.Log, error)
 }
 
 // DAGWriter defines methods for writing DAG relationships (ISP: DAG write operations only)
 type DAGWriter interface {
-	SaveDAGRelationship(ctx context.Context, childHash, parentHash string, isSelectedParent bool) error
-	SaveGHOSTDAGData(ctx context.Context, blockHash string, data *domain.GHOSTDAGData) error
+	SaveDAGRelationship(ctx context.Context, childHash, parentHash domain.Hash, isSelectedParent bool) error
+	SaveGHOSTDAGData(ctx context.Context, blockHash domain.Hash, data *domain.GHOSTDAGData) error
+	SaveMergeSet(ctx context.Context, blockHash domain.Hash, mergeSet []domain.MergeSetMember) error
 }
 
 // DAGReader defines methods for reading DAG relationships (ISP: DAG read operations only)
 type DAGReader interface {
-	GetBlockParents(ctx context.Context, blockHash string) ([]string, error)
-	GetBlockChildren(ctx context.Context, blockHash string) ([]string, error)
-	GetGHOSTDAGData(ctx context.Context, blockHash string) (*domain.GHOSTDAGData, error)
+	GetBlockParents(ctx context.Context, blockHash domain.Hash) ([]domain.Hash, error)
+	GetBlockChildren(ctx context.Context, blockHash domain.Hash) ([]domain.Hash, error)
+	GetGHOSTDAGData(ctx context.Context, blockHash domain.Hash) (*domain.GHOSTDAGData, error)
+	GetMergedBlocks(ctx context.Context, blockHash domain.Hash) ([]*domain.MergeSetMember, error)
+	GetMergingChainBlock(ctx context.Context, blockHash domain.Hash) (*domain.MergeSetMember, error)
+	GetPast(ctx context.Context, hash domain.Hash, depth int, page domain.DAGPage) ([]*domain.DAGNode, error)
+	GetFuture(ctx context.Context, hash domain.Hash, depth int, page domain.DAGPage) ([]*domain.DAGNode, error)
+	GetAnticone(ctx context.Context, hash domain.Hash, window uint64, page domain.DAGPage) ([]*domain.DAGNode, error)
+	GetLowestCommonChainAncestor(ctx context.Context, a, b domain.Hash) (*domain.DAGNode, error)
+	GetSelectedChainPath(ctx context.Context, from, to domain.Hash) ([]*domain.DAGNode, error)
+}
+
+// DAGWindowReader loads a range of the indexed DAG at once (ISP: Offline DAG analysis only)
+type DAGWindowReader interface {
+	GetDAGWindow(ctx context.Context, fromNumber, toNumber int64) ([]*domain.DAGBlock, error)
+	GetDAGWindowByBlueScore(ctx context.Context, fromBlueScore, toBlueScore uint64) ([]*domain.DAGBlock, error)
 }
 
 // AddressWriter defines methods for writing address data (ISP: Address write operations only)
 type AddressWriter interface {
-	SaveAddress(ctx context.Context, address *domain.Address) error
-	UpdateAddressBalance(ctx context.Context, address string, balance *big.Int) error
-	UpdateAddressNonce(ctx context.Context, address string, nonce uint64) error
+	SaveAddress(ctx context.Context, account *domain.Account) error
+	UpdateAddress(ctx context.Context, address domain.Address, update domain.AccountUpdate) error
+	UpdateAddressBalance(ctx context.Context, address domain.Address, balance *big.Int) error
+	UpdateAddressNonce(ctx context.Context, address domain.Address, nonce uint64) error
 }
 
 // AddressReader defines methods for reading address data (ISP: Address read operations only)
 type AddressReader interface {
-	GetAddress(ctx context.Context, address string) (*domain.Address, error)
-	GetAddressBalance(ctx context.Context, address string) (*big.Int, error)
+	GetAddress(ctx context.Context, address domain.Address) (*domain.Account, error)
+	GetAddressBalance(ctx context.Context, address domain.Address) (*big.Int, error)
+}
+
+// BulkWriter writes many rows per round trip for backfilling (ISP: Bulk writes only)
+type BulkWriter interface {
+	WriteBatch(ctx context.Context, blocks []*domain.Block) error
+	WriteLogs(ctx context.Context, logs []*domain.Log) error
+}
+
+// ReorgEventPublisher records chain reorganizations and notifies consumers (ISP: Reorg events only)
+type ReorgEventPublisher interface {
+	PublishReorg(ctx context.Context, event *domain.ReorgEvent) error
+}
+
+// DecodeAnomalyWriter records items dropped from RPC responses (ISP: Anomaly writes only)
+type DecodeAnomalyWriter interface {
+	SaveDecodeAnomaly(ctx context.Context, anomaly *domain.DecodeAnomaly) error
+}
+
+// DecodeAnomalyReader reads items dropped from RPC responses (ISP: Anomaly reads only)
+type DecodeAnomalyReader interface {
+	GetDecodeAnomaliesByBlockHash(ctx context.Context, blockHash domain.Hash) ([]*domain.DecodeAnomaly, error)
+}
+
+// CheckpointStore defines methods for persisting sync progress (ISP: Checkpoint operations only)
+type CheckpointStore interface {
+	GetCheckpoint(ctx context.Context, name string) (*domain.Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, checkpoint *domain.Checkpoint) error
 }
 
 // Composite interfaces for convenience (but still segregated)
@@ -82,12 +153,17 @@ type AddressReader interface {
 type BlockRepository interface {
 	BlockReader
 	BlockWriter
+	ChainBlockReader
+	DAGGapReader
 }
 
 // TransactionRepository combines read and write operations for transactions
 type TransactionRepository interface {
 	TransactionReader
 	TransactionWriter
+	TransactionAcceptanceWriter
+	TransactionInclusionReader
+	TransactionInclusionWriter
 }
 
 // LogRepository combines read and write operations for logs
@@ -107,3 +183,29 @@ type AddressRepository interface {
 	AddressReader
 	AddressWriter
 }
+
+// DecodeAnomalyRepository combines read and write operations for decode anomalies
+type DecodeAnomalyRepository interface {
+	DecodeAnomalyReader
+	DecodeAnomalyWriter
+}
+
+// Repos groups the repositories that take part in a single unit of work
+type Repos struct {
+	Blocks       BlockRepository
+	Transactions TransactionRepository
+	Logs         LogRepository
+	DAG          DAGRepository
+	Addresses    AddressRepository
+	Checkpoints  CheckpointStore
+	ReorgEvents  ReorgEventPublisher
+	Anomalies    DecodeAnomalyRepository
+	Bulk         BulkWriter
+	Nested       UnitOfWork // Runs fn in a savepoint when the repositories share a transaction
+}
+
+// UnitOfWork runs a set of repository calls atomically (ISP: Transaction boundaries only)
+// If fn returns an error, every write made through repos is rolled back.
+type UnitOfWork interface {
+	WithTx(ctx context.Context, fn func(repos Repos) error) error
+}
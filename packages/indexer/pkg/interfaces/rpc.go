@@ -2,7 +2,9 @@ package interfaces
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -22,6 +24,11 @@ type BlockByHashReader interface {
 	GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (*Block, error)
 }
 
+// TransactionByHashReader reads transactions by hash (ISP: Single responsibility)
+type TransactionByHashReader interface {
+	GetTransactionByHash(ctx context.Context, hash common.Hash) (*Transaction, error)
+}
+
 // ReceiptReader reads transaction receipts (ISP: Single responsibility)
 type ReceiptReader interface {
 	GetTransactionReceipt(ctx context.Context, hash common.Hash) (*Receipt, error)
@@ -37,6 +44,28 @@ type CodeReader interface {
 	GetCode(ctx context.Context, address common.Address) ([]byte, error)
 }
 
+// BatchBlockReader reads many blocks by number in one round trip (ISP: Single responsibility)
+type BatchBlockReader interface {
+	GetBlocksByNumber(ctx context.Context, numbers []*big.Int, fullTx bool) ([]*Block, error)
+}
+
+// BatchReceiptReader reads many transaction receipts in one round trip (ISP: Single responsibility)
+type BatchReceiptReader interface {
+	GetTransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*Receipt, error)
+}
+
+// NewBlockSubscriber pushes new blocks as the node learns about them (ISP: Single responsibility)
+type NewBlockSubscriber interface {
+	SubscribeNewBlocks(ctx context.Context, ch chan<- *BlockHeader) (Subscription, error)
+}
+
+// Subscription is an active push subscription.
+// Err delivers at most one error if the subscription fails and is closed once it ends.
+type Subscription interface {
+	Unsubscribe()
+	Err() <-chan error
+}
+
 // Phoenix-specific RPC interfaces
 
 // DAGInfoReader reads DAG information (ISP: Single responsibility)
@@ -49,6 +78,11 @@ type BlueScoreReader interface {
 	GetBlueScore(ctx context.Context, blockNumber *big.Int) (uint64, error)
 }
 
+// BlockGHOSTDAGDataReader reads the GHOSTDAG data of a specific block (ISP: Single responsibility)
+type BlockGHOSTDAGDataReader interface {
+	GetBlockGHOSTDAGData(ctx context.Context, hash common.Hash) (*GHOSTDAGData, error)
+}
+
 // BlockParentsReader reads block parents (ISP: Single responsibility)
 type BlockParentsReader interface {
 	GetBlockParents(ctx context.Context, hash common.Hash) ([]common.Hash, error)
@@ -64,6 +98,7 @@ type PhoenixRPCClient interface {
 	DAGInfoReader
 	BlueScoreReader
 	BlockParentsReader
+	BlockGHOSTDAGDataReader
 }
 
 // Data structures for RPC responses
@@ -77,11 +112,50 @@ type Block struct {
 	Miner            string
 	GasLimit         uint64
 	GasUsed          uint64
-	BaseFeePerGas    *uint64
+	BaseFeePerGas    *big.Int
 	BlueScore        uint64
 	IsChainBlock     bool
 	SelectedParent   string
-	Transactions     []Transaction
+	Transactions     []Transaction // Empty when the block was fetched with fullTx=false
+
+	// Transaction hashes in block order, for blocks fetched with or without full transactions
+	TransactionHashes []string
+
+	// Entries in the node's transaction list, including any dropped as anomalies
+	TransactionCount int
+
+	// Header fields
+	TransactionsRoot string
+	StateRoot        string
+	ReceiptsRoot     string
+	Size             uint64
+	Difficulty       *big.Int
+	Nonce            string
+	ExtraData        []byte
+	LogsBloom        string
+	MixHash          string
+
+	// DAG header fields; zero values when the node does not report them
+	BlueWork     *big.Int
+	DAAScore     uint64
+	PruningPoint string
+
+	// Transactions dropped because they could not be decoded; only set in lenient decoding
+	Anomalies []DecodeAnomaly
+}
+
+// IsHashOnly returns true if the block lists its transactions by hash only
+func (b *Block) IsHashOnly() bool {
+	return len(b.Transactions) == 0 && len(b.TransactionHashes) > 0
+}
+
+// BlockHeader is a block announced by a subscription
+type BlockHeader struct {
+	Hash         string
+	Number       int64
+	ParentHashes []string
+	BlueScore    uint64
+	Timestamp    int64
 }
 
 // Transaction represents a transaction from Phoenix RPC
@@ -94,21 +168,70 @@ type Transaction struct {
 	GasPrice *big.Int
 	Nonce   uint64
 	Input   []byte
+
+	// EIP-2718 typed transaction fields; nil or empty when the type lacks them
+	Type                 uint8
+	ChainID              *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	AccessList           []AccessTuple
+	MaxFeePerBlobGas     *big.Int
+	BlobVersionedHashes  []string
+
+	// Signature
+	V *big.Int
+	R string
+	S string
+
+	// Position in the block: the node's transactionIndex, else the position in the block's
+	// list including undecodable entries. nil for transactions not read from a block.
+	Index *uint64
+}
+
+// AccessTuple is an EIP-2930 access list entry
+type AccessTuple struct {
+	Address     string
+	StorageKeys []string
 }
 
 // Receipt represents a transaction receipt
 type Receipt struct {
-	TransactionHash string
-	Status          int
-	GasUsed         uint64
-	Logs            []Log
+	TransactionHash   string
+	BlockHash         string
+	BlockNumber       int64
+	Status            int
+	Type              uint8
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	EffectiveGasPrice *big.Int // nil when the node does not report it
+	ContractAddress   *string  // nil unless the transaction deployed a contract
+	LogsBloom         string
+	Logs              []Log
+	Anomalies         []DecodeAnomaly // Logs dropped because they could not be decoded; only set in lenient decoding
 }
 
 // Log represents an event log
 type Log struct {
-	Address string
-	Topics  []string
-	Data    []byte
+	Address     string
+	Topics      []string
+	Data        []byte
+	LogIndex    *uint64 // Position in the block; nil when the node does not report it
+	BlockHash   string
+	BlockNumber int64
+}
+
+// Kinds of list items a decode anomaly can refer to
+const (
+	DecodeKindTransaction = "transaction"
+	DecodeKindLog         = "log"
+)
+
+// DecodeAnomaly is a list item that was dropped from an RPC response because it could not be decoded
+type DecodeAnomaly struct {
+	Kind   string // DecodeKindTransaction or DecodeKindLog
+	Index  int    // Position in the list it was dropped from
+	Raw    string // The item's JSON as received
+	Reason string
 }
 
 // FilterQuery represents a filter query for logs
@@ -127,3 +250,33 @@ type DAGInfo struct {
 	MergeSetReds  []string
 }
 
+// GHOSTDAGData represents the GHOSTDAG data the node computed for a block
+type GHOSTDAGData struct {
+	BlueScore          uint64
+	BlueWork           *big.Int
+	SelectedParent     string
+	MergeSetBlues      []string
+	MergeSetReds       []string
+	BluesAnticoneSizes []int // In MergeSetBlues order
+}
+
+// BatchError reports the elements of a batched call that failed.
+// Results for the other elements are still valid.
+type BatchError struct {
+	Errors map[int]error // Keyed by position in the request
+}
+
+func (e *BatchError) Error() string {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	if len(indexes) == 0 {
+		return "batch: no errors"
+	}
+
+	first := indexes[0]
+	return fmt.Sprintf("batch: %d calls failed, first at index %d: %v", len(indexes), first, e.Errors[first])
+}
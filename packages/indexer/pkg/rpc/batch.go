@@ -0,0 +1,193 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// methodNotFound is the JSON-RPC error code for unsupported methods
+const methodNotFound = -32601
+
+// BatchElem is a single call in a JSON-RPC batch
+type BatchElem struct {
+	Method string
+	Args   []interface{}
+	// Result is a pointer the result is unmarshalled into; it is left untouched for a null result
+	Result interface{}
+	// Error is set if this call failed; it does not affect other elements
+	Error error
+}
+
+// BatchCall sends all elements in a single HTTP request.
+// The returned error covers transport failures for the whole batch, which are retried
+// like single calls; errors for individual calls are reported in each element's Error.
+func (c *PhoenixClient) BatchCall(ctx context.Context, elems []BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	requests := make([]rpcRequest, len(elems))
+	byID := make(map[uint64]int, len(elems))
+	for i, elem := range elems {
+		args := elem.Args
+		if args == nil {
+			args = []interface{}{}
+		}
+
+		id := c.nextID.Add(1)
+		requests[i] = rpcRequest{
+			JSONRPC: "2.0",
+			Method:  elem.Method,
+			Params:  args,
+			ID:      id,
+		}
+		byID[id] = i
+	}
+
+	reqBody, err := json.Marshal(requests)
+	if err != nil {
+		return fmt.Errorf("marshal batch: %w", err)
+	}
+
+	body, err := c.post(ctx, reqBody, fmt.Sprintf("batch(%d)", len(elems)))
+	if err != nil {
+		return err
+	}
+
+	var responses []rpcResponse
+	if err := json.Unmarshal(body, &responses); err != nil {
+		// Nodes without batch support answer with a single error object
+		var single rpcResponse
+		if json.Unmarshal(body, &single) == nil && single.Error != nil {
+			return fmt.Errorf("batch rejected: %w", single.Error)
+		}
+		return fmt.Errorf("unmarshal batch response: %w", err)
+	}
+
+	// Responses may arrive in any order
+	answered := make([]bool, len(elems))
+	for i := range responses {
+		idx, ok := byID[responses[i].ID]
+		if !ok || answered[idx] {
+			continue
+		}
+		answered[idx] = true
+		elems[idx].Error = responses[i].decode(elems[idx].Result)
+	}
+
+	for i := range elems {
+		if !answered[i] {
+			elems[i].Error = fmt.Errorf("no response for %s", elems[i].Method)
+		}
+	}
+
+	return nil
+}
+
+// GetBlocksByNumber implements interfaces.BatchBlockReader.
+// Blocks are returned in request order, nil where a block does not exist.
+// If some calls fail, the other blocks are returned along with an *interfaces.BatchError.
+func (c *PhoenixClient) GetBlocksByNumber(
+	ctx context.Context,
+	numbers []*big.Int,
+	fullTx bool,
+) ([]*interfaces.Block, error) {
+	results := make([]*rpcBlock, len(numbers))
+	elems := make([]BatchElem, len(numbers))
+	for i, number := range numbers {
+		elems[i] = BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{fmt.Sprintf("0x%x", number), fullTx},
+			Result: &results[i],
+		}
+	}
+
+	if err := c.BatchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("eth_getBlockByNumber batch: %w", err)
+	}
+
+	blocks := make([]*interfaces.Block, len(numbers))
+	batchErr := &interfaces.BatchError{Errors: make(map[int]error)}
+	for i, elem := range elems {
+		if elem.Error != nil {
+			batchErr.Errors[i] = fmt.Errorf("block %s: %w", numbers[i], elem.Error)
+			continue
+		}
+		if results[i] == nil {
+			continue // Block not found
+		}
+
+		block, err := c.convertBlock(results[i])
+		if err != nil {
+			batchErr.Errors[i] = fmt.Errorf("block %s: %w", numbers[i], err)
+			continue
+		}
+		blocks[i] = block
+	}
+
+	if len(batchErr.Errors) > 0 {
+		return blocks, batchErr
+	}
+	return blocks, nil
+}
+
+// GetTransactionReceipts implements interfaces.BatchReceiptReader.
+// Receipts are returned in request order, nil where a receipt does not exist.
+// If some calls fail, the other receipts are returned along with an *interfaces.BatchError.
+func (c *PhoenixClient) GetTransactionReceipts(
+	ctx context.Context,
+	hashes []common.Hash,
+) ([]*interfaces.Receipt, error) {
+	results := make([]*rpcReceipt, len(hashes))
+	elems := make([]BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{hash.Hex()},
+			Result: &results[i],
+		}
+	}
+
+	if err := c.BatchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("eth_getTransactionReceipt batch: %w", err)
+	}
+
+	receipts, batchErr := c.convertReceipts(results, func(i int) error { return elems[i].Error })
+	if batchErr != nil {
+		return receipts, batchErr
+	}
+	return receipts, nil
+}
+
+// convertReceipts converts raw receipts, collecting per-element errors
+func (c *PhoenixClient) convertReceipts(results []*rpcReceipt, elemErr func(i int) error) ([]*interfaces.Receipt, *interfaces.BatchError) {
+	receipts := make([]*interfaces.Receipt, len(results))
+	batchErr := &interfaces.BatchError{Errors: make(map[int]error)}
+	for i, result := range results {
+		if err := elemErr(i); err != nil {
+			batchErr.Errors[i] = err
+			continue
+		}
+		if result == nil {
+			continue // Receipt not found
+		}
+
+		receipt, err := c.convertReceipt(result)
+		if err != nil {
+			batchErr.Errors[i] = err
+			continue
+		}
+		receipts[i] = receipt
+	}
+
+	if len(batchErr.Errors) > 0 {
+		return receipts, batchErr
+	}
+	return receipts, nil
+}
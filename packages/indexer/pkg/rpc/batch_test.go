@@ -0,0 +1,234 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/rpc"
+)
+
+type batchRequest struct {
+	ID     uint64        `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// newBatchServer answers each call in a batch with handle(call), in reverse order.
+// handle returns a result, or an error object when the result is a *rpc.RPCError.
+func newBatchServer(t *testing.T, requests *int32, handle func(req batchRequest) interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		var batch []batchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+
+		responses := make([]map[string]interface{}, 0, len(batch))
+		for i := len(batch) - 1; i >= 0; i-- {
+			resp := map[string]interface{}{"jsonrpc": "2.0", "id": batch[i].ID}
+			result := handle(batch[i])
+			if rpcErr, ok := result.(*rpc.RPCError); ok {
+				resp["error"] = rpcErr
+			} else {
+				resp["result"] = result
+			}
+			responses = append(responses, resp)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(responses)
+	}))
+}
+
+func testRPCBlock(number string, txHashes ...string) map[string]interface{} {
+	txs := make([]interface{}, 0, len(txHashes))
+	for _, hash := range txHashes {
+		txs = append(txs, map[string]interface{}{
+			"hash":  hash,
+			"from":  "0x" + strings.Repeat("d", 40),
+			"gas":   "0x5208",
+			"nonce": "0x0",
+			"input": "0x",
+		})
+	}
+
+	return map[string]interface{}{
+		"hash":         "0x" + strings.Repeat("a", 64),
+		"number":       number,
+		"timestamp":    "0x65abc123",
+		"parentHashes": []string{"0x" + strings.Repeat("b", 64)},
+		"gasLimit":     "0x1c9c380",
+		"gasUsed":      "0x5208",
+		"blueScore":    number,
+		"isChainBlock": true,
+		"transactions": txs,
+	}
+}
+
+func testRPCReceipt(hash string) map[string]interface{} {
+	return map[string]interface{}{
+		"transactionHash": hash,
+		"status":          "0x1",
+		"gasUsed":         "0x5208",
+		"logs":            []interface{}{},
+	}
+}
+
+func TestPhoenixClient_BatchCall(t *testing.T) {
+	var requests int32
+	seenIDs := make(map[uint64]bool)
+	server := newBatchServer(t, &requests, func(req batchRequest) interface{} {
+		assert.False(t, seenIDs[req.ID], "request IDs must be unique")
+		seenIDs[req.ID] = true
+
+		switch req.Method {
+		case "eth_blockNumber":
+			return "0x3e8"
+		case "eth_chainId":
+			return &rpc.RPCError{Code: -32000, Message: "boom"}
+		default:
+			return nil
+		}
+	})
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	var number, chainID, missing string
+	elems := []rpc.BatchElem{
+		{Method: "eth_blockNumber", Result: &number},
+		{Method: "eth_chainId", Result: &chainID},
+		{Method: "eth_getBlockByHash", Args: []interface{}{"0x1", false}, Result: &missing},
+	}
+
+	err := client.BatchCall(context.Background(), elems)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests, "batch should be a single HTTP request")
+	assert.NoError(t, elems[0].Error)
+	assert.Equal(t, "0x3e8", number)
+	assert.Error(t, elems[1].Error)
+	assert.Contains(t, elems[1].Error.Error(), "boom")
+	assert.NoError(t, elems[2].Error)
+	assert.Empty(t, missing)
+}
+
+func TestPhoenixClient_BatchCall_RetriesWholeBatch(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var batch []batchRequest
+		json.NewDecoder(r.Body).Decode(&batch)
+		responses := make([]map[string]interface{}, len(batch))
+		for i, req := range batch {
+			responses[i] = map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "0x1"}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL, rpc.WithRetryDelay(time.Millisecond))
+
+	var a, b string
+	elems := []rpc.BatchElem{
+		{Method: "eth_blockNumber", Result: &a},
+		{Method: "eth_blockNumber", Result: &b},
+	}
+
+	err := client.BatchCall(context.Background(), elems)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), attempts)
+	assert.Equal(t, "0x1", a)
+	assert.Equal(t, "0x1", b)
+}
+
+func TestPhoenixClient_BatchCall_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      nil,
+			"error":   map[string]interface{}{"code": -32600, "message": "batch requests not supported"},
+		})
+	}))
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	var result string
+	err := client.BatchCall(context.Background(), []rpc.BatchElem{{Method: "eth_blockNumber", Result: &result}})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported")
+}
+
+func TestPhoenixClient_GetBlocksByNumber_PartialFailure(t *testing.T) {
+	var requests int32
+	server := newBatchServer(t, &requests, func(req batchRequest) interface{} {
+		switch req.Params[0] {
+		case "0x1":
+			return testRPCBlock("0x1")
+		case "0x2":
+			return &rpc.RPCError{Code: -32000, Message: "header not found"}
+		default:
+			return nil
+		}
+	})
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	blocks, err := client.GetBlocksByNumber(context.Background(),
+		[]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, true)
+
+	var batchErr *interfaces.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Len(t, batchErr.Errors, 1)
+	assert.Contains(t, batchErr.Errors[1].Error(), "header not found")
+
+	require.Len(t, blocks, 3)
+	require.NotNil(t, blocks[0])
+	assert.Equal(t, int64(1), blocks[0].Number)
+	assert.Nil(t, blocks[1])
+	assert.Nil(t, blocks[2], "missing block should be nil")
+	assert.Equal(t, int32(1), requests)
+}
+
+func TestPhoenixClient_GetTransactionReceipts(t *testing.T) {
+	var requests int32
+	server := newBatchServer(t, &requests, func(req batchRequest) interface{} {
+		return testRPCReceipt(req.Params[0].(string))
+	})
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	hashes := []common.Hash{
+		common.HexToHash("0x" + strings.Repeat("1", 64)),
+		common.HexToHash("0x" + strings.Repeat("2", 64)),
+	}
+
+	receipts, err := client.GetTransactionReceipts(context.Background(), hashes)
+
+	require.NoError(t, err)
+	require.Len(t, receipts, 2)
+	assert.Equal(t, hashes[0].Hex(), receipts[0].TransactionHash)
+	assert.Equal(t, hashes[1].Hex(), receipts[1].TransactionHash)
+	assert.Equal(t, int32(1), requests)
+}
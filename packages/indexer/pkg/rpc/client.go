@@ -8,6 +8,7 @@ import (
 	"io"
 	"math/big"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -22,6 +23,8 @@ type PhoenixClient struct {
 	rpcURL     string
 	maxRetries int
 	retryDelay time.Duration
+	nextID     atomic.Uint64 // JSON-RPC request IDs, unique per client
+	decodeMode DecodeMode
 	logger     *zap.Logger
 }
 
@@ -42,6 +45,14 @@ func WithRetryDelay(delay time.Duration) ClientOption {
 	}
 }
 
+// WithDecodeMode sets what happens to transactions and logs that cannot be decoded.
+// The default is DecodeStrict.
+func WithDecodeMode(mode DecodeMode) ClientOption {
+	return func(c *PhoenixClient) {
+		c.decodeMode = mode
+	}
+}
+
 // WithLogger sets the logger
 func WithLogger(logger *zap.Logger) ClientOption {
 	return func(c *PhoenixClient) {
@@ -60,6 +71,7 @@ func NewPhoenixClient(rpcURL string, opts ...ClientOption) *PhoenixClient {
 		rpcURL:     rpcURL,
 		maxRetries: 3,
 		retryDelay: time.Second,
+		decodeMode: DecodeStrict,
 		logger:     logger,
 	}
 
@@ -101,7 +113,7 @@ func (c *PhoenixClient) GetBlockByNumber(
 		return nil, nil // Block not found
 	}
 
-	return result.toBlock()
+	return c.convertBlock(result)
 }
 
 // GetBlockByHash implements interfaces.BlockByHashReader
@@ -121,7 +133,31 @@ func (c *PhoenixClient) GetBlockByHash(
 		return nil, nil // Block not found
 	}
 
-	return result.toBlock()
+	return c.convertBlock(result)
+}
+
+// GetTransactionByHash implements interfaces.TransactionByHashReader
+func (c *PhoenixClient) GetTransactionByHash(
+	ctx context.Context,
+	hash common.Hash,
+) (*interfaces.Transaction, error) {
+	var result json.RawMessage
+	err := c.callRPC(ctx, "eth_getTransactionByHash",
+		[]interface{}{hash.Hex()}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("eth_getTransactionByHash: %w", err)
+	}
+
+	if result == nil {
+		return nil, nil // Transaction not found
+	}
+
+	tx, err := decodeTransaction(result)
+	if err != nil {
+		return nil, fmt.Errorf("eth_getTransactionByHash: decode transaction %s: %w", hash.Hex(), err)
+	}
+
+	return tx, nil
 }
 
 // GetTransactionReceipt implements interfaces.ReceiptReader
@@ -140,7 +176,7 @@ func (c *PhoenixClient) GetTransactionReceipt(
 		return nil, nil // Receipt not found
 	}
 
-	return result.toReceipt()
+	return c.convertReceipt(result)
 }
 
 // GetLogs implements interfaces.LogReader
@@ -148,7 +184,7 @@ func (c *PhoenixClient) GetLogs(
 	ctx context.Context,
 	filter interfaces.FilterQuery,
 ) ([]interfaces.Log, error) {
-	var result []interfaces.Log
+	var result []json.RawMessage
 
 	params := make(map[string]interface{})
 	if filter.FromBlock != nil {
@@ -170,7 +206,13 @@ func (c *PhoenixClient) GetLogs(
 		return nil, fmt.Errorf("eth_getLogs: %w", err)
 	}
 
-	return result, nil
+	// Logs have no block to record anomalies against, so they are always decoded strictly
+	logs, _, err := decodeItems(result, interfaces.DecodeKindLog, DecodeStrict, decodeLog)
+	if err != nil {
+		return nil, fmt.Errorf("eth_getLogs: %w", err)
+	}
+
+	return logs, nil
 }
 
 // GetCode implements interfaces.CodeReader
@@ -199,6 +241,25 @@ func (c *PhoenixClient) GetDAGInfo(ctx context.Context) (*interfaces.DAGInfo, er
 	return result, nil
 }
 
+// GetBlockGHOSTDAGData implements interfaces.BlockGHOSTDAGDataReader
+func (c *PhoenixClient) GetBlockGHOSTDAGData(
+	ctx context.Context,
+	hash common.Hash,
+) (*interfaces.GHOSTDAGData, error) {
+	var result *rpcGHOSTDAGData
+	err := c.callRPC(ctx, "phoenix_getBlockGHOSTDAGData",
+		[]interface{}{hash.Hex()}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("phoenix_getBlockGHOSTDAGData: %w", err)
+	}
+
+	if result == nil {
+		return nil, nil // Block not found
+	}
+
+	return result.toGHOSTDAGData()
+}
+
 // GetBlueScore implements interfaces.BlueScoreReader
 func (c *PhoenixClient) GetBlueScore(
 	ctx context.Context,
@@ -241,6 +302,42 @@ func (c *PhoenixClient) GetBlockParents(
 	return parents, nil
 }
 
+// convertBlock converts a raw block in the client's decode mode, logging dropped transactions
+func (c *PhoenixClient) convertBlock(rb *rpcBlock) (*interfaces.Block, error) {
+	block, err := rb.toBlock(c.decodeMode)
+	if err != nil {
+		return nil, fmt.Errorf("block %s: %w", rb.Hash, err)
+	}
+
+	for _, anomaly := range block.Anomalies {
+		c.logger.Warn("dropped undecodable block item",
+			zap.String("blockHash", block.Hash),
+			zap.String("kind", anomaly.Kind),
+			zap.Int("index", anomaly.Index),
+			zap.String("reason", anomaly.Reason))
+	}
+
+	return block, nil
+}
+
+// convertReceipt converts a raw receipt in the client's decode mode, logging dropped logs
+func (c *PhoenixClient) convertReceipt(rr *rpcReceipt) (*interfaces.Receipt, error) {
+	receipt, err := rr.toReceipt(c.decodeMode)
+	if err != nil {
+		return nil, fmt.Errorf("receipt %s: %w", rr.TransactionHash, err)
+	}
+
+	for _, anomaly := range receipt.Anomalies {
+		c.logger.Warn("dropped undecodable receipt item",
+			zap.String("txHash", receipt.TransactionHash),
+			zap.String("kind", anomaly.Kind),
+			zap.Int("index", anomaly.Index),
+			zap.String("reason", anomaly.Reason))
+	}
+
+	return receipt, nil
+}
+
 // callRPC performs an RPC call with retry logic
 func (c *PhoenixClient) callRPC(
 	ctx context.Context,
@@ -248,11 +345,11 @@ func (c *PhoenixClient) callRPC(
 	params []interface{},
 	result interface{},
 ) error {
-	request := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"method":  method,
-		"params":  params,
-		"id":      1,
+	request := rpcRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      c.nextID.Add(1),
 	}
 
 	reqBody, err := json.Marshal(request)
@@ -260,12 +357,28 @@ func (c *PhoenixClient) callRPC(
 		return fmt.Errorf("marshal request: %w", err)
 	}
 
+	body, err := c.post(ctx, reqBody, method)
+	if err != nil {
+		return err
+	}
+
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return rpcResp.decode(result)
+}
+
+// post sends a request body, retrying transport and HTTP failures with exponential backoff.
+// label identifies the call in logs.
+func (c *PhoenixClient) post(ctx context.Context, reqBody []byte, label string) ([]byte, error) {
 	var lastErr error
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 
@@ -274,7 +387,7 @@ func (c *PhoenixClient) callRPC(
 			backoff := c.retryDelay * time.Duration(1<<uint(attempt-1))
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
+				return nil, ctx.Err()
 			case <-time.After(backoff):
 			}
 		}
@@ -282,7 +395,7 @@ func (c *PhoenixClient) callRPC(
 		req, err := http.NewRequestWithContext(ctx, "POST", c.rpcURL,
 			bytes.NewReader(reqBody))
 		if err != nil {
-			return fmt.Errorf("create request: %w", err)
+			return nil, fmt.Errorf("create request: %w", err)
 		}
 
 		req.Header.Set("Content-Type", "application/json")
@@ -291,7 +404,7 @@ func (c *PhoenixClient) callRPC(
 		if err != nil {
 			lastErr = err
 			c.logger.Debug("RPC call failed, retrying",
-				zap.String("method", method),
+				zap.String("method", label),
 				zap.Int("attempt", attempt+1),
 				zap.Error(err))
 			continue
@@ -310,35 +423,8 @@ func (c *PhoenixClient) callRPC(
 			continue
 		}
 
-		var rpcResp struct {
-			Result json.RawMessage `json:"result"`
-			Error  *struct {
-				Code    int    `json:"code"`
-				Message string `json:"message"`
-			} `json:"error"`
-		}
-
-		if err := json.Unmarshal(body, &rpcResp); err != nil {
-			return fmt.Errorf("unmarshal response: %w", err)
-		}
-
-		if rpcResp.Error != nil {
-			return fmt.Errorf("RPC error %d: %s",
-				rpcResp.Error.Code, rpcResp.Error.Message)
-		}
-
-		// Handle null result
-		if string(rpcResp.Result) == "null" {
-			return nil // Caller should check for nil result
-		}
-
-		if err := json.Unmarshal(rpcResp.Result, result); err != nil {
-			return fmt.Errorf("unmarshal result: %w", err)
-		}
-
-		return nil
+		return body, nil
 	}
 
-	return fmt.Errorf("max retries (%d) exceeded: %w", c.maxRetries, lastErr)
+	return nil, fmt.Errorf("max retries (%d) exceeded: %w", c.maxRetries, lastErr)
 }
-
@@ -90,6 +90,21 @@ func TestPhoenixClient_GetBlockByNumber(t *testing.T) {
 			"blueScore":    "0x64",      // 100
 			"isChainBlock": true,
 			"transactions": []interface{}{},
+
+			"baseFeePerGas": "0x10000000000000001", // Does not fit in a uint64
+
+			"transactionsRoot": "0x" + strings.Repeat("1", 64),
+			"stateRoot":        "0x" + strings.Repeat("2", 64),
+			"receiptsRoot":     "0x" + strings.Repeat("3", 64),
+			"size":             "0x220",
+			"difficulty":       "0x2540be400",
+			"nonce":            "0x0000000000000042",
+			"extraData":        "0x00ff",
+			"logsBloom":        "0x" + strings.Repeat("0", 512),
+			"mixHash":          "0x" + strings.Repeat("4", 64),
+			"blueWork":         "0x1000000000000000000000000", // Does not fit in a uint64
+			"daaScore":         "0x1f4",
+			"pruningPoint":     "0x" + strings.Repeat("5", 64),
 		},
 	}
 
@@ -112,6 +127,20 @@ func TestPhoenixClient_GetBlockByNumber(t *testing.T) {
 	assert.True(t, strings.HasPrefix(block.Hash, "0x"))
 	assert.Equal(t, int64(100), block.Number)
 	assert.Equal(t, 1, len(block.ParentHashes))
+	assert.Equal(t, "18446744073709551617", block.BaseFeePerGas.String())
+
+	assert.Equal(t, "0x"+strings.Repeat("1", 64), block.TransactionsRoot)
+	assert.Equal(t, "0x"+strings.Repeat("2", 64), block.StateRoot)
+	assert.Equal(t, "0x"+strings.Repeat("3", 64), block.ReceiptsRoot)
+	assert.Equal(t, uint64(544), block.Size)
+	assert.Equal(t, big.NewInt(10000000000), block.Difficulty)
+	assert.Equal(t, "0x0000000000000042", block.Nonce)
+	assert.Equal(t, []byte{0x00, 0xff}, block.ExtraData)
+	assert.Equal(t, "0x"+strings.Repeat("0", 512), block.LogsBloom)
+	assert.Equal(t, "0x"+strings.Repeat("4", 64), block.MixHash)
+	assert.Equal(t, "79228162514264337593543950336", block.BlueWork.String())
+	assert.Equal(t, uint64(500), block.DAAScore)
+	assert.Equal(t, "0x"+strings.Repeat("5", 64), block.PruningPoint)
 }
 
 func TestPhoenixClient_GetBlockByHash(t *testing.T) {
@@ -154,12 +183,201 @@ func TestPhoenixClient_GetBlockByHash(t *testing.T) {
 	assert.Equal(t, 2, len(block.ParentHashes))
 }
 
+func TestPhoenixClient_GetBlockByNumber_TypedTransactions(t *testing.T) {
+	legacy := map[string]interface{}{
+		"hash":     "0x" + strings.Repeat("1", 64),
+		"from":     "0x" + strings.Repeat("c", 40),
+		"to":       "0x" + strings.Repeat("d", 40),
+		"value":    "0x0",
+		"gas":      "0x5208",
+		"gasPrice": "0x3b9aca00",
+		"nonce":    "0x0",
+		"input":    "0x",
+		"v":        "0x1b",
+		"r":        "0x" + strings.Repeat("e", 64),
+		"s":        "0x" + strings.Repeat("f", 64),
+	}
+	dynamicFee := map[string]interface{}{
+		"hash":                 "0x" + strings.Repeat("2", 64),
+		"from":                 "0x" + strings.Repeat("c", 40),
+		"to":                   "0x" + strings.Repeat("d", 40),
+		"value":                "0x0",
+		"gas":                  "0x5208",
+		"gasPrice":             "0x3b9aca00",
+		"nonce":                "0x1",
+		"input":                "0x",
+		"type":                 "0x2",
+		"chainId":              "0x5a0", // 1440
+		"maxFeePerGas":         "0x77359400",
+		"maxPriorityFeePerGas": "0x3b9aca00",
+		"accessList": []interface{}{
+			map[string]interface{}{
+				"address":     "0x" + strings.Repeat("a", 40),
+				"storageKeys": []interface{}{"0x" + strings.Repeat("0", 64)},
+			},
+		},
+		"v": "0x1",
+		"r": "0x" + strings.Repeat("e", 64),
+		"s": "0x" + strings.Repeat("f", 64),
+	}
+	blob := map[string]interface{}{
+		"hash":                 "0x" + strings.Repeat("3", 64),
+		"from":                 "0x" + strings.Repeat("c", 40),
+		"to":                   "0x" + strings.Repeat("d", 40),
+		"value":                "0x0",
+		"gas":                  "0x5208",
+		"nonce":                "0x2",
+		"input":                "0x",
+		"type":                 "0x3",
+		"chainId":              "0x5a0",
+		"maxFeePerGas":         "0x77359400",
+		"maxPriorityFeePerGas": "0x3b9aca00",
+		"maxFeePerBlobGas":     "0x10000000000000000", // Does not fit in a uint64
+		"accessList":           []interface{}{},
+		"blobVersionedHashes":  []interface{}{"0x01" + strings.Repeat("b", 62)},
+		"v":                    "0x0",
+		"r":                    "0x" + strings.Repeat("e", 64),
+		"s":                    "0x" + strings.Repeat("f", 64),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result": map[string]interface{}{
+				"hash":         "0x" + strings.Repeat("a", 64),
+				"number":       "0x64",
+				"timestamp":    "0x65abc123",
+				"parentHashes": []string{},
+				"gasLimit":     "0x1c9c380",
+				"gasUsed":      "0xf618",
+				"blueScore":    "0x64",
+				"transactions": []interface{}{legacy, dynamicFee, blob},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	block, err := client.GetBlockByNumber(context.Background(), big.NewInt(100), true)
+	require.NoError(t, err)
+	require.Len(t, block.Transactions, 3)
+
+	legacyTx := block.Transactions[0]
+	assert.Equal(t, uint8(0), legacyTx.Type)
+	assert.Nil(t, legacyTx.ChainID)
+	assert.Nil(t, legacyTx.MaxFeePerGas)
+	assert.Nil(t, legacyTx.AccessList)
+	assert.Equal(t, big.NewInt(27), legacyTx.V)
+
+	dynamicTx := block.Transactions[1]
+	assert.Equal(t, uint8(2), dynamicTx.Type)
+	assert.Equal(t, big.NewInt(1440), dynamicTx.ChainID)
+	assert.Equal(t, big.NewInt(2000000000), dynamicTx.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(1000000000), dynamicTx.MaxPriorityFeePerGas)
+	require.Len(t, dynamicTx.AccessList, 1)
+	assert.Equal(t, "0x"+strings.Repeat("a", 40), dynamicTx.AccessList[0].Address)
+	assert.Equal(t, []string{"0x" + strings.Repeat("0", 64)}, dynamicTx.AccessList[0].StorageKeys)
+	assert.Equal(t, "0x"+strings.Repeat("e", 64), dynamicTx.R)
+
+	blobTx := block.Transactions[2]
+	assert.Equal(t, uint8(3), blobTx.Type)
+	assert.Nil(t, blobTx.GasPrice)
+	assert.Equal(t, "18446744073709551616", blobTx.MaxFeePerBlobGas.String())
+	assert.Equal(t, []string{"0x01" + strings.Repeat("b", 62)}, blobTx.BlobVersionedHashes)
+	assert.Empty(t, blobTx.AccessList)
+}
+
+func TestPhoenixClient_GetBlockByNumber_HashOnly(t *testing.T) {
+	txHashes := []string{"0x" + strings.Repeat("1", 64), "0x" + strings.Repeat("2", 64)}
+	block := testRPCBlock("0x64")
+	block["transactions"] = txHashes
+
+	var fullTx interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		fullTx = req["params"].([]interface{})[1]
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": block})
+	}))
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	result, err := client.GetBlockByNumber(context.Background(), big.NewInt(100), false)
+	require.NoError(t, err)
+	assert.Equal(t, false, fullTx)
+	assert.Empty(t, result.Transactions)
+	assert.Equal(t, txHashes, result.TransactionHashes)
+	assert.True(t, result.IsHashOnly())
+}
+
+func TestPhoenixClient_GetBlockByNumber_HashOnlyInvalidHash(t *testing.T) {
+	block := testRPCBlock("0x64")
+	block["transactions"] = []string{"0x" + strings.Repeat("1", 64), "0x1234"}
+
+	server := newResultServer(block)
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	result, err := client.GetBlockByNumber(context.Background(), big.NewInt(100), false)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestPhoenixClient_GetTransactionByHash(t *testing.T) {
+	txHash := "0x" + strings.Repeat("c", 64)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result interface{} // Unknown hashes return null
+		if req["method"] == "eth_getTransactionByHash" && req["params"].([]interface{})[0] == txHash {
+			result = map[string]interface{}{
+				"hash":     txHash,
+				"from":     "0x" + strings.Repeat("d", 40),
+				"to":       "0x" + strings.Repeat("e", 40),
+				"value":    "0xde0b6b3a7640000",
+				"gas":      "0x5208",
+				"gasPrice": "0x3b9aca00",
+				"nonce":    "0x7",
+				"input":    "0x",
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	tx, err := client.GetTransactionByHash(context.Background(), common.HexToHash(txHash))
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	assert.Equal(t, txHash, tx.Hash)
+	assert.Equal(t, uint64(7), tx.Nonce)
+	assert.Equal(t, "1000000000000000000", tx.Value.String())
+
+	tx, err = client.GetTransactionByHash(context.Background(), common.HexToHash("0x"+strings.Repeat("f", 64)))
+	require.NoError(t, err)
+	assert.Nil(t, tx)
+}
+
 func TestPhoenixClient_GetTransactionReceipt(t *testing.T) {
 	mockReceipt := map[string]interface{}{
 		"jsonrpc": "2.0",
 		"id":      1,
 		"result": map[string]interface{}{
 			"transactionHash": "0xtx123",
+			"blockHash":       "0x" + strings.Repeat("a", 64),
+			"blockNumber":     "0x64",
 			"status":         "0x1", // 1 = success
 			"gasUsed":        "0x5208", // 21000 in hex
 			"logs":           []interface{}{},
@@ -186,6 +404,62 @@ func TestPhoenixClient_GetTransactionReceipt(t *testing.T) {
 	assert.Equal(t, "0xtx123", receipt.TransactionHash)
 	assert.Equal(t, 1, receipt.Status)
 	assert.Equal(t, uint64(21000), receipt.GasUsed)
+	assert.Equal(t, "0x"+strings.Repeat("a", 64), receipt.BlockHash)
+	assert.Equal(t, int64(100), receipt.BlockNumber)
+}
+
+func TestPhoenixClient_GetTransactionReceipt_Deployment(t *testing.T) {
+	blockHash := "0x" + strings.Repeat("a", 64)
+	contract := "0x" + strings.Repeat("c", 40)
+	bloom := "0x" + strings.Repeat("0", 512)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result": map[string]interface{}{
+				"transactionHash":   "0x" + strings.Repeat("b", 64),
+				"blockHash":         blockHash,
+				"blockNumber":       "0x64",
+				"status":            "0x1",
+				"type":              "0x2",
+				"gasUsed":           "0x7530",
+				"cumulativeGasUsed": "0xc350",
+				"effectiveGasPrice": "0x10000000000000000", // Does not fit in a uint64
+				"contractAddress":   contract,
+				"logsBloom":         bloom,
+				"logs": []interface{}{
+					map[string]interface{}{
+						"address":     contract,
+						"topics":      []interface{}{},
+						"data":        "0x",
+						"logIndex":    "0x3",
+						"blockHash":   blockHash,
+						"blockNumber": "0x64",
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	receipt, err := client.GetTransactionReceipt(context.Background(), common.HexToHash("0x"+strings.Repeat("b", 64)))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(2), receipt.Type)
+	assert.Equal(t, uint64(50000), receipt.CumulativeGasUsed)
+	assert.Equal(t, "18446744073709551616", receipt.EffectiveGasPrice.String())
+	require.NotNil(t, receipt.ContractAddress)
+	assert.Equal(t, contract, *receipt.ContractAddress)
+	assert.Equal(t, bloom, receipt.LogsBloom)
+
+	require.Len(t, receipt.Logs, 1)
+	require.NotNil(t, receipt.Logs[0].LogIndex)
+	assert.Equal(t, uint64(3), *receipt.Logs[0].LogIndex)
+	assert.Equal(t, blockHash, receipt.Logs[0].BlockHash)
+	assert.Equal(t, int64(100), receipt.Logs[0].BlockNumber)
 }
 
 func TestPhoenixClient_GetBlockParents(t *testing.T) {
@@ -216,6 +490,65 @@ func TestPhoenixClient_GetBlockParents(t *testing.T) {
 	assert.Equal(t, common.HexToHash("0xparent2"), parents[1])
 }
 
+func TestPhoenixClient_GetBlockGHOSTDAGData(t *testing.T) {
+	blue := "0x" + strings.Repeat("b", 64)
+	red := "0x" + strings.Repeat("c", 64)
+
+	mockResponse := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"result": map[string]interface{}{
+			"blueScore":          "0x3e8",
+			"blueWork":           "0x1388",
+			"selectedParent":     blue,
+			"mergeSetBlues":      []string{blue},
+			"mergeSetReds":       []string{red},
+			"bluesAnticoneSizes": []string{"0x2"},
+		},
+	}
+
+	var params []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		if req["method"] == "phoenix_getBlockGHOSTDAGData" {
+			params = req["params"].([]interface{})
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(mockResponse)
+		}
+	}))
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	blockHash := common.HexToHash("0x" + strings.Repeat("a", 64))
+	data, err := client.GetBlockGHOSTDAGData(context.Background(), blockHash)
+	require.NoError(t, err)
+	require.NotNil(t, data)
+	assert.Equal(t, []interface{}{blockHash.Hex()}, params)
+	assert.Equal(t, uint64(1000), data.BlueScore)
+	assert.Equal(t, big.NewInt(5000), data.BlueWork)
+	assert.Equal(t, blue, data.SelectedParent)
+	assert.Equal(t, []string{blue}, data.MergeSetBlues)
+	assert.Equal(t, []string{red}, data.MergeSetReds)
+	assert.Equal(t, []int{2}, data.BluesAnticoneSizes)
+}
+
+func TestPhoenixClient_GetBlockGHOSTDAGData_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": nil})
+	}))
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	data, err := client.GetBlockGHOSTDAGData(context.Background(), common.HexToHash("0x01"))
+	require.NoError(t, err)
+	assert.Nil(t, data)
+}
+
 func TestPhoenixClient_RetryLogic(t *testing.T) {
 	attempts := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
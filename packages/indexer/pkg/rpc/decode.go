@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// DecodeMode decides what happens to list items (transactions, logs) that cannot be decoded
+type DecodeMode int
+
+const (
+	// DecodeStrict fails the whole response on the first malformed item
+	DecodeStrict DecodeMode = iota
+	// DecodeLenient drops malformed items and reports each one as a decode anomaly
+	DecodeLenient
+)
+
+// String implements fmt.Stringer
+func (m DecodeMode) String() string {
+	switch m {
+	case DecodeStrict:
+		return "strict"
+	case DecodeLenient:
+		return "lenient"
+	default:
+		return fmt.Sprintf("DecodeMode(%d)", int(m))
+	}
+}
+
+// ParseDecodeMode parses "strict" or "lenient"
+func ParseDecodeMode(s string) (DecodeMode, error) {
+	switch s {
+	case "strict":
+		return DecodeStrict, nil
+	case "lenient":
+		return DecodeLenient, nil
+	default:
+		return DecodeStrict, fmt.Errorf("unknown decode mode %q", s)
+	}
+}
+
+// DecodeError reports the list item that failed a strict decode
+type DecodeError struct {
+	Kind  string // interfaces.DecodeKindTransaction or interfaces.DecodeKindLog
+	Index int    // Position in the list
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode %s %d: %v", e.Kind, e.Index, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeItems decodes every raw item of a list, keeping the order of the items that decode.
+// In strict mode the first failure is returned as a *DecodeError; in lenient mode
+// failing items are dropped and returned as anomalies.
+func decodeItems[T any](
+	raw []json.RawMessage,
+	kind string,
+	mode DecodeMode,
+	decode func(json.RawMessage) (*T, error),
+) ([]T, []interfaces.DecodeAnomaly, error) {
+	items := make([]T, 0, len(raw))
+	var anomalies []interfaces.DecodeAnomaly
+	for i, item := range raw {
+		decoded, err := decode(item)
+		if err == nil {
+			items = append(items, *decoded)
+			continue
+		}
+
+		if mode != DecodeLenient {
+			return nil, nil, &DecodeError{Kind: kind, Index: i, Err: err}
+		}
+		anomalies = append(anomalies, interfaces.DecodeAnomaly{
+			Kind:   kind,
+			Index:  i,
+			Raw:    string(item),
+			Reason: err.Error(),
+		})
+	}
+
+	return items, anomalies, nil
+}
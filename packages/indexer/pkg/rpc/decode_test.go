@@ -0,0 +1,178 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/rpc"
+)
+
+// newResultServer answers every call with result
+func newResultServer(result interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+}
+
+// blockWithMalformedTransactions has a valid transaction at 0 and malformed ones at 1 and 2
+func blockWithMalformedTransactions() map[string]interface{} {
+	block := testRPCBlock("0x64", "0x"+strings.Repeat("1", 64))
+	block["transactions"] = append(block["transactions"].([]interface{}),
+		map[string]interface{}{ // Gas is not a quantity
+			"hash":  "0x" + strings.Repeat("2", 64),
+			"from":  "0x" + strings.Repeat("d", 40),
+			"gas":   "lots",
+			"nonce": "0x0",
+		},
+		map[string]interface{}{ // Input is not hex
+			"hash":  "0x" + strings.Repeat("3", 64),
+			"from":  "0x" + strings.Repeat("d", 40),
+			"gas":   "0x5208",
+			"nonce": "0x0",
+			"input": "0xzz",
+		},
+	)
+	return block
+}
+
+func TestParseDecodeMode(t *testing.T) {
+	mode, err := rpc.ParseDecodeMode("strict")
+	require.NoError(t, err)
+	assert.Equal(t, rpc.DecodeStrict, mode)
+
+	mode, err = rpc.ParseDecodeMode("lenient")
+	require.NoError(t, err)
+	assert.Equal(t, rpc.DecodeLenient, mode)
+	assert.Equal(t, "lenient", mode.String())
+
+	_, err = rpc.ParseDecodeMode("loose")
+	assert.Error(t, err)
+}
+
+func TestPhoenixClient_GetBlockByNumber_StrictDecodeFailsBlock(t *testing.T) {
+	server := newResultServer(blockWithMalformedTransactions())
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	block, err := client.GetBlockByNumber(context.Background(), big.NewInt(100), true)
+	require.Error(t, err)
+	assert.Nil(t, block)
+
+	var decodeErr *rpc.DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, interfaces.DecodeKindTransaction, decodeErr.Kind)
+	assert.Equal(t, 1, decodeErr.Index)
+}
+
+func TestPhoenixClient_GetBlockByNumber_LenientDecodeRecordsAnomalies(t *testing.T) {
+	server := newResultServer(blockWithMalformedTransactions())
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL, rpc.WithDecodeMode(rpc.DecodeLenient))
+
+	block, err := client.GetBlockByNumber(context.Background(), big.NewInt(100), true)
+	require.NoError(t, err)
+	require.Len(t, block.Transactions, 1)
+	assert.Equal(t, "0x"+strings.Repeat("1", 64), block.Transactions[0].Hash)
+
+	require.Len(t, block.Anomalies, 2)
+	assert.Equal(t, interfaces.DecodeKindTransaction, block.Anomalies[0].Kind)
+	assert.Equal(t, 1, block.Anomalies[0].Index)
+	assert.Contains(t, block.Anomalies[0].Raw, `"gas":"lots"`)
+	assert.NotEmpty(t, block.Anomalies[0].Reason)
+	assert.Equal(t, 2, block.Anomalies[1].Index)
+	assert.Contains(t, block.Anomalies[1].Raw, `"input":"0xzz"`)
+}
+
+func TestPhoenixClient_GetBlockByNumber_LenientDecodeKeepsPositions(t *testing.T) {
+	block := blockWithMalformedTransactions()
+	block["transactions"] = append(block["transactions"].([]interface{}),
+		map[string]interface{}{
+			"hash":  "0x" + strings.Repeat("4", 64),
+			"from":  "0x" + strings.Repeat("d", 40),
+			"gas":   "0x5208",
+			"nonce": "0x1",
+		},
+		map[string]interface{}{
+			"hash":             "0x" + strings.Repeat("5", 64),
+			"from":             "0x" + strings.Repeat("d", 40),
+			"gas":              "0x5208",
+			"nonce":            "0x2",
+			"transactionIndex": "0x7",
+		},
+	)
+	server := newResultServer(block)
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL, rpc.WithDecodeMode(rpc.DecodeLenient))
+
+	got, err := client.GetBlockByNumber(context.Background(), big.NewInt(100), true)
+	require.NoError(t, err)
+	require.Len(t, got.Transactions, 3)
+	assert.Equal(t, 5, got.TransactionCount)
+
+	// Dropped transactions still take up their positions; the node's index wins when reported
+	var indexes []uint64
+	for _, tx := range got.Transactions {
+		require.NotNil(t, tx.Index)
+		indexes = append(indexes, *tx.Index)
+	}
+	assert.Equal(t, []uint64{0, 3, 7}, indexes)
+}
+
+func TestPhoenixClient_GetBlockByNumber_MissingRequiredField(t *testing.T) {
+	block := testRPCBlock("0x64")
+	block["transactions"] = []interface{}{
+		map[string]interface{}{"hash": "0x" + strings.Repeat("1", 64), "gas": "0x5208"}, // No nonce
+	}
+	server := newResultServer(block)
+	defer server.Close()
+
+	client := rpc.NewPhoenixClient(server.URL)
+
+	_, err := client.GetBlockByNumber(context.Background(), big.NewInt(100), true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing nonce")
+}
+
+func TestPhoenixClient_GetTransactionReceipt_MalformedLogData(t *testing.T) {
+	address := "0x" + strings.Repeat("c", 40)
+	receipt := testRPCReceipt("0x" + strings.Repeat("b", 64))
+	receipt["logs"] = []interface{}{
+		map[string]interface{}{"address": address, "topics": []interface{}{}, "data": "0xabc"}, // Odd length
+		map[string]interface{}{"address": address, "topics": []interface{}{}, "data": "0x0102"},
+	}
+	server := newResultServer(receipt)
+	defer server.Close()
+
+	hash := common.HexToHash("0x" + strings.Repeat("b", 64))
+
+	strict := rpc.NewPhoenixClient(server.URL)
+	_, err := strict.GetTransactionReceipt(context.Background(), hash)
+	var decodeErr *rpc.DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, interfaces.DecodeKindLog, decodeErr.Kind)
+	assert.Equal(t, 0, decodeErr.Index)
+
+	lenient := rpc.NewPhoenixClient(server.URL, rpc.WithDecodeMode(rpc.DecodeLenient))
+	got, err := lenient.GetTransactionReceipt(context.Background(), hash)
+	require.NoError(t, err)
+	require.Len(t, got.Logs, 1)
+	assert.Equal(t, []byte{0x01, 0x02}, got.Logs[0].Data)
+	require.Len(t, got.Anomalies, 1)
+	assert.Equal(t, interfaces.DecodeKindLog, got.Anomalies[0].Kind)
+	assert.Equal(t, 0, got.Anomalies[0].Index)
+}
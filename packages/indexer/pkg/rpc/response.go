@@ -1,8 +1,12 @@
 package rpc
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
 )
@@ -23,11 +27,22 @@ type rpcBlock struct {
 	TransactionsRoot string   `json:"transactionsRoot"`
 	StateRoot        string   `json:"stateRoot"`
 	ReceiptsRoot     string   `json:"receiptsRoot"`
-	Transactions     []interface{} `json:"transactions"`
+	Size             string   `json:"size"`
+	Difficulty       string   `json:"difficulty"`
+	Nonce            string   `json:"nonce"`
+	ExtraData        string   `json:"extraData"`
+	LogsBloom        string   `json:"logsBloom"`
+	MixHash          string   `json:"mixHash"`
+	BlueWork         string   `json:"blueWork"`
+	DAAScore         string   `json:"daaScore"`
+	PruningPoint     string   `json:"pruningPoint"`
+
+	// Decoded one by one so a malformed transaction is reported rather than failing the whole block
+	Transactions []json.RawMessage `json:"transactions"`
 }
 
-// toBlock converts rpcBlock to interfaces.Block
-func (rb *rpcBlock) toBlock() (*interfaces.Block, error) {
+// toBlock converts rpcBlock to interfaces.Block; mode decides what malformed transactions do
+func (rb *rpcBlock) toBlock(mode DecodeMode) (*interfaces.Block, error) {
 	number, err := hexutil.DecodeBig(rb.Number)
 	if err != nil {
 		return nil, err
@@ -48,13 +63,12 @@ func (rb *rpcBlock) toBlock() (*interfaces.Block, error) {
 		return nil, err
 	}
 
-	var baseFeePerGas *uint64
+	var baseFeePerGas *big.Int
 	if rb.BaseFeePerGas != nil {
-		bf, err := hexutil.DecodeUint64(*rb.BaseFeePerGas)
+		baseFeePerGas, err = hexutil.DecodeBig(*rb.BaseFeePerGas)
 		if err != nil {
 			return nil, err
 		}
-		baseFeePerGas = &bf
 	}
 
 	blueScore, err := hexutil.DecodeUint64(rb.BlueScore)
@@ -62,19 +76,67 @@ func (rb *rpcBlock) toBlock() (*interfaces.Block, error) {
 		return nil, err
 	}
 
-	// Parse transactions
-	transactions := make([]interfaces.Transaction, 0, len(rb.Transactions))
-	for _, txData := range rb.Transactions {
-		txMap, ok := txData.(map[string]interface{})
-		if !ok {
+	// Header fields the node may omit
+	var size, daaScore uint64
+	var difficulty, blueWork *big.Int
+	for _, field := range []struct {
+		name string
+		raw  string
+		dst  *uint64
+	}{
+		{"size", rb.Size, &size},
+		{"daaScore", rb.DAAScore, &daaScore},
+	} {
+		if field.raw == "" {
+			continue
+		}
+		if *field.dst, err = hexutil.DecodeUint64(field.raw); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", field.name, err)
+		}
+	}
+	for _, field := range []struct {
+		name string
+		raw  string
+		dst  **big.Int
+	}{
+		{"difficulty", rb.Difficulty, &difficulty},
+		{"blueWork", rb.BlueWork, &blueWork},
+	} {
+		if field.raw == "" {
 			continue
 		}
+		if *field.dst, err = hexutil.DecodeBig(field.raw); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", field.name, err)
+		}
+	}
+
+	var extraData []byte
+	if rb.ExtraData != "" {
+		if extraData, err = hexutil.Decode(rb.ExtraData); err != nil {
+			return nil, fmt.Errorf("decode extraData: %w", err)
+		}
+	}
 
-		tx, err := parseTransaction(txMap)
+	// Blocks fetched with fullTx=false list transaction hashes instead of objects
+	var transactions []interfaces.Transaction
+	var txHashes []string
+	var anomalies []interfaces.DecodeAnomaly
+	if isHashList(rb.Transactions) {
+		txHashes, anomalies, err = decodeItems(rb.Transactions, interfaces.DecodeKindTransaction, mode, decodeTransactionHash)
 		if err != nil {
-			continue // Skip invalid transactions
+			return nil, err
 		}
-		transactions = append(transactions, *tx)
+	} else {
+		transactions, anomalies, err = decodeItems(rb.Transactions, interfaces.DecodeKindTransaction, mode, decodeTransaction)
+		if err != nil {
+			return nil, err
+		}
+
+		txHashes = make([]string, len(transactions))
+		for i, tx := range transactions {
+			txHashes[i] = tx.Hash
+		}
+		setTransactionPositions(transactions, anomalies)
 	}
 
 	return &interfaces.Block{
@@ -90,19 +152,133 @@ func (rb *rpcBlock) toBlock() (*interfaces.Block, error) {
 		IsChainBlock:   rb.IsChainBlock,
 		SelectedParent: rb.SelectedParent,
 		Transactions:   transactions,
+
+		TransactionHashes: txHashes,
+		TransactionCount:  len(rb.Transactions),
+
+		TransactionsRoot: rb.TransactionsRoot,
+		StateRoot:        rb.StateRoot,
+		ReceiptsRoot:     rb.ReceiptsRoot,
+		Size:             size,
+		Difficulty:       difficulty,
+		Nonce:            rb.Nonce,
+		ExtraData:        extraData,
+		LogsBloom:        rb.LogsBloom,
+		MixHash:          rb.MixHash,
+		BlueWork:         blueWork,
+		DAAScore:         daaScore,
+		PruningPoint:     rb.PruningPoint,
+
+		Anomalies: anomalies,
+	}, nil
+}
+
+// rpcHeader represents a header pushed by newHeads or blockAdded subscriptions
+type rpcHeader struct {
+	Hash         string   `json:"hash"`
+	Number       string   `json:"number"`
+	ParentHash   string   `json:"parentHash"`
+	ParentHashes []string `json:"parentHashes"`
+	BlueScore    *string  `json:"blueScore"`
+	Timestamp    string   `json:"timestamp"`
+}
+
+// toHeader converts rpcHeader to interfaces.BlockHeader
+func (rh *rpcHeader) toHeader() (*interfaces.BlockHeader, error) {
+	number, err := hexutil.DecodeBig(rh.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp, err := hexutil.DecodeBig(rh.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	var blueScore uint64
+	if rh.BlueScore != nil {
+		blueScore, err = hexutil.DecodeUint64(*rh.BlueScore)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// newHeads only carries the selected parent
+	parentHashes := rh.ParentHashes
+	if len(parentHashes) == 0 && rh.ParentHash != "" {
+		parentHashes = []string{rh.ParentHash}
+	}
+
+	return &interfaces.BlockHeader{
+		Hash:         rh.Hash,
+		Number:       number.Int64(),
+		ParentHashes: parentHashes,
+		BlueScore:    blueScore,
+		Timestamp:    timestamp.Int64(),
+	}, nil
+}
+
+// rpcGHOSTDAGData represents a block's GHOSTDAG data from Phoenix RPC
+type rpcGHOSTDAGData struct {
+	BlueScore          string           `json:"blueScore"`
+	BlueWork           string           `json:"blueWork"`
+	SelectedParent     string           `json:"selectedParent"`
+	MergeSetBlues      []string         `json:"mergeSetBlues"`
+	MergeSetReds       []string         `json:"mergeSetReds"`
+	BluesAnticoneSizes []hexutil.Uint64 `json:"bluesAnticoneSizes"`
+}
+
+// toGHOSTDAGData converts rpcGHOSTDAGData to interfaces.GHOSTDAGData
+func (rg *rpcGHOSTDAGData) toGHOSTDAGData() (*interfaces.GHOSTDAGData, error) {
+	blueScore, err := hexutil.DecodeUint64(rg.BlueScore)
+	if err != nil {
+		return nil, fmt.Errorf("blueScore: %w", err)
+	}
+
+	blueWork, err := hexutil.DecodeBig(rg.BlueWork)
+	if err != nil {
+		return nil, fmt.Errorf("blueWork: %w", err)
+	}
+
+	if len(rg.BluesAnticoneSizes) != len(rg.MergeSetBlues) {
+		return nil, fmt.Errorf("got %d blues anticone sizes for %d merge set blues",
+			len(rg.BluesAnticoneSizes), len(rg.MergeSetBlues))
+	}
+
+	anticoneSizes := make([]int, len(rg.BluesAnticoneSizes))
+	for i, size := range rg.BluesAnticoneSizes {
+		anticoneSizes[i] = int(size)
+	}
+
+	return &interfaces.GHOSTDAGData{
+		BlueScore:          blueScore,
+		BlueWork:           blueWork,
+		SelectedParent:     rg.SelectedParent,
+		MergeSetBlues:      rg.MergeSetBlues,
+		MergeSetReds:       rg.MergeSetReds,
+		BluesAnticoneSizes: anticoneSizes,
 	}, nil
 }
 
 // rpcReceipt represents a transaction receipt from Phoenix RPC
 type rpcReceipt struct {
-	TransactionHash string        `json:"transactionHash"`
-	Status          string        `json:"status"`
-	GasUsed         string        `json:"gasUsed"`
-	Logs            []interface{} `json:"logs"`
+	TransactionHash   string  `json:"transactionHash"`
+	BlockHash         string  `json:"blockHash"`
+	BlockNumber       string  `json:"blockNumber"`
+	Status            string  `json:"status"`
+	Type              string  `json:"type"`
+	GasUsed           string  `json:"gasUsed"`
+	CumulativeGasUsed string  `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *string `json:"effectiveGasPrice"`
+	ContractAddress   *string `json:"contractAddress"`
+	LogsBloom         string  `json:"logsBloom"`
+
+	// Decoded one by one so a malformed log is reported rather than failing the whole receipt
+	Logs []json.RawMessage `json:"logs"`
 }
 
-// toReceipt converts rpcReceipt to interfaces.Receipt
-func (rr *rpcReceipt) toReceipt() (*interfaces.Receipt, error) {
+// toReceipt converts rpcReceipt to interfaces.Receipt; mode decides what malformed logs do
+func (rr *rpcReceipt) toReceipt(mode DecodeMode) (*interfaces.Receipt, error) {
 	status, err := hexutil.DecodeUint64(rr.Status)
 	if err != nil {
 		return nil, err
@@ -113,107 +289,325 @@ func (rr *rpcReceipt) toReceipt() (*interfaces.Receipt, error) {
 		return nil, err
 	}
 
-	logs := make([]interfaces.Log, 0, len(rr.Logs))
-	for _, logData := range rr.Logs {
-		logMap, ok := logData.(map[string]interface{})
-		if !ok {
-			continue
+	var blockNumber uint64
+	if rr.BlockNumber != "" {
+		blockNumber, err = hexutil.DecodeUint64(rr.BlockNumber)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		log, err := parseLog(logMap)
+	var cumulativeGasUsed uint64
+	if rr.CumulativeGasUsed != "" {
+		cumulativeGasUsed, err = hexutil.DecodeUint64(rr.CumulativeGasUsed)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("decode cumulativeGasUsed: %w", err)
 		}
-		logs = append(logs, *log)
 	}
 
-	return &interfaces.Receipt{
-		TransactionHash: rr.TransactionHash,
-		Status:          int(status),
-		GasUsed:         gasUsed,
-		Logs:            logs,
-	}, nil
-}
-
-func parseTransaction(txMap map[string]interface{}) (*interfaces.Transaction, error) {
-	hash, _ := txMap["hash"].(string)
-	from, _ := txMap["from"].(string)
-	toStr, _ := txMap["to"].(string)
-	var to *string
-	if toStr != "" {
-		to = &toStr
+	var effectiveGasPrice *big.Int
+	if rr.EffectiveGasPrice != nil {
+		effectiveGasPrice, err = hexutil.DecodeBig(*rr.EffectiveGasPrice)
+		if err != nil {
+			return nil, fmt.Errorf("decode effectiveGasPrice: %w", err)
+		}
 	}
 
-	valueStr, _ := txMap["value"].(string)
-	value := big.NewInt(0)
-	if valueStr != "" {
-		var err error
-		value, err = hexutil.DecodeBig(valueStr)
+	var txType uint64
+	if rr.Type != "" {
+		txType, err = hexutil.DecodeUint64(rr.Type)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("decode type: %w", err)
+		}
+		if txType > 0xff {
+			return nil, fmt.Errorf("transaction type %d out of range", txType)
 		}
 	}
 
-	gasStr, _ := txMap["gas"].(string)
-	gas, err := hexutil.DecodeUint64(gasStr)
+	var contractAddress *string
+	if rr.ContractAddress != nil && *rr.ContractAddress != "" {
+		contractAddress = rr.ContractAddress
+	}
+
+	logs, anomalies, err := decodeItems(rr.Logs, interfaces.DecodeKindLog, mode, decodeLog)
 	if err != nil {
 		return nil, err
 	}
 
-	gasPriceStr, _ := txMap["gasPrice"].(string)
-	var gasPrice *big.Int
-	if gasPriceStr != "" {
-		gasPrice, err = hexutil.DecodeBig(gasPriceStr)
-		if err != nil {
-			return nil, err
+	return &interfaces.Receipt{
+		TransactionHash:   rr.TransactionHash,
+		BlockHash:         rr.BlockHash,
+		BlockNumber:       int64(blockNumber),
+		Status:            int(status),
+		Type:              uint8(txType),
+		GasUsed:           gasUsed,
+		CumulativeGasUsed: cumulativeGasUsed,
+		EffectiveGasPrice: effectiveGasPrice,
+		ContractAddress:   contractAddress,
+		LogsBloom:         rr.LogsBloom,
+		Logs:              logs,
+		Anomalies:         anomalies,
+	}, nil
+}
+
+// rpcTransaction represents a full transaction object from Phoenix RPC.
+// Quantities decode strictly; typed transaction fields are only present for the types that define them.
+type rpcTransaction struct {
+	Hash                 string           `json:"hash"`
+	From                 string           `json:"from"`
+	To                   *string          `json:"to"`
+	Value                *hexutil.Big     `json:"value"`
+	Gas                  *hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big     `json:"gasPrice"`
+	Nonce                *hexutil.Uint64  `json:"nonce"`
+	Input                *hexutil.Bytes   `json:"input"`
+	Type                 *hexutil.Uint64  `json:"type"`
+	ChainID              *hexutil.Big     `json:"chainId"`
+	MaxFeePerGas         *hexutil.Big     `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big     `json:"maxPriorityFeePerGas"`
+	AccessList           []rpcAccessTuple `json:"accessList"`
+	MaxFeePerBlobGas     *hexutil.Big     `json:"maxFeePerBlobGas"`
+	BlobVersionedHashes  []string         `json:"blobVersionedHashes"`
+	V                    *hexutil.Big     `json:"v"`
+	R                    string           `json:"r"`
+	S                    string           `json:"s"`
+	TransactionIndex     *hexutil.Uint64  `json:"transactionIndex"`
+}
+
+// rpcAccessTuple represents an EIP-2930 access list entry
+type rpcAccessTuple struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+// setTransactionPositions gives transactions the node did not index their position in the
+// node's list, counting the anomalies dropped before them
+func setTransactionPositions(transactions []interfaces.Transaction, anomalies []interfaces.DecodeAnomaly) {
+	dropped := make(map[int]bool, len(anomalies))
+	for _, anomaly := range anomalies {
+		dropped[anomaly.Index] = true
+	}
+
+	position := 0
+	for i := range transactions {
+		for dropped[position] {
+			position++
+		}
+		if transactions[i].Index == nil {
+			index := uint64(position)
+			transactions[i].Index = &index
 		}
+		position++
 	}
+}
 
-	nonceStr, _ := txMap["nonce"].(string)
-	nonce, err := hexutil.DecodeUint64(nonceStr)
-	if err != nil {
+// isHashList reports whether a block's transaction list holds hashes rather than objects
+func isHashList(raw []json.RawMessage) bool {
+	if len(raw) == 0 {
+		return false
+	}
+	item := bytes.TrimSpace(raw[0])
+	return len(item) > 0 && item[0] == '"'
+}
+
+// decodeTransactionHash decodes a single entry of a hash-only transaction list
+func decodeTransactionHash(raw json.RawMessage) (*string, error) {
+	var hash string
+	if err := json.Unmarshal(raw, &hash); err != nil {
 		return nil, err
 	}
 
-	inputStr, _ := txMap["input"].(string)
-	input, err := hexutil.Decode(inputStr)
+	decoded, err := hexutil.Decode(hash)
 	if err != nil {
-		input = []byte{}
-	}
-
-	return &interfaces.Transaction{
-		Hash:     hash,
-		From:     from,
-		To:       to,
-		Value:    value,
-		Gas:      gas,
-		GasPrice: gasPrice,
-		Nonce:    nonce,
-		Input:    input,
-	}, nil
+		return nil, fmt.Errorf("decode hash: %w", err)
+	}
+	if len(decoded) != common.HashLength {
+		return nil, fmt.Errorf("hash has %d bytes, want %d", len(decoded), common.HashLength)
+	}
+
+	return &hash, nil
 }
 
-func parseLog(logMap map[string]interface{}) (*interfaces.Log, error) {
-	address, _ := logMap["address"].(string)
+// decodeTransaction decodes a single transaction object
+func decodeTransaction(raw json.RawMessage) (*interfaces.Transaction, error) {
+	var rt rpcTransaction
+	if err := json.Unmarshal(raw, &rt); err != nil {
+		return nil, err
+	}
+	return rt.toTransaction()
+}
 
-	topicsData, _ := logMap["topics"].([]interface{})
-	topics := make([]string, 0, len(topicsData))
-	for _, topic := range topicsData {
-		if topicStr, ok := topic.(string); ok {
-			topics = append(topics, topicStr)
+// toTransaction converts rpcTransaction to interfaces.Transaction
+func (rt *rpcTransaction) toTransaction() (*interfaces.Transaction, error) {
+	if rt.Hash == "" {
+		return nil, fmt.Errorf("missing hash")
+	}
+	if rt.Gas == nil {
+		return nil, fmt.Errorf("missing gas")
+	}
+	if rt.Nonce == nil {
+		return nil, fmt.Errorf("missing nonce")
+	}
+
+	var txType uint64
+	if rt.Type != nil {
+		txType = uint64(*rt.Type)
+		if txType > 0xff {
+			return nil, fmt.Errorf("transaction type %d out of range", txType)
 		}
 	}
 
-	dataStr, _ := logMap["data"].(string)
-	data, err := hexutil.Decode(dataStr)
-	if err != nil {
-		data = []byte{}
+	var to *string
+	if rt.To != nil && *rt.To != "" {
+		to = rt.To
+	}
+
+	value := big.NewInt(0)
+	if rt.Value != nil {
+		value = rt.Value.ToInt()
+	}
+
+	input := []byte{}
+	if rt.Input != nil {
+		input = *rt.Input
+	}
+
+	tx := &interfaces.Transaction{
+		Hash:                 rt.Hash,
+		From:                 rt.From,
+		To:                   to,
+		Value:                value,
+		Gas:                  uint64(*rt.Gas),
+		GasPrice:             optionalBig(rt.GasPrice),
+		Nonce:                uint64(*rt.Nonce),
+		Input:                input,
+		Type:                 uint8(txType),
+		ChainID:              optionalBig(rt.ChainID),
+		MaxFeePerGas:         optionalBig(rt.MaxFeePerGas),
+		MaxPriorityFeePerGas: optionalBig(rt.MaxPriorityFeePerGas),
+		MaxFeePerBlobGas:     optionalBig(rt.MaxFeePerBlobGas),
+		BlobVersionedHashes:  rt.BlobVersionedHashes,
+		V:                    optionalBig(rt.V),
+		R:                    rt.R,
+		S:                    rt.S,
+		Index:                (*uint64)(rt.TransactionIndex),
+	}
+
+	// Absent, null and empty access lists all decode to nil
+	for _, entry := range rt.AccessList {
+		tuple := interfaces.AccessTuple{Address: entry.Address, StorageKeys: entry.StorageKeys}
+		if tuple.StorageKeys == nil {
+			tuple.StorageKeys = []string{}
+		}
+		tx.AccessList = append(tx.AccessList, tuple)
+	}
+
+	return tx, nil
+}
+
+// optionalBig returns the value of a quantity that may be absent or null
+func optionalBig(v *hexutil.Big) *big.Int {
+	if v == nil {
+		return nil
+	}
+	return v.ToInt()
+}
+
+// rpcLog represents an event log from Phoenix RPC
+type rpcLog struct {
+	Address     string          `json:"address"`
+	Topics      []string        `json:"topics"`
+	Data        *hexutil.Bytes  `json:"data"`
+	LogIndex    *hexutil.Uint64 `json:"logIndex"`
+	BlockHash   string          `json:"blockHash"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+}
+
+// decodeLog decodes a single log object
+func decodeLog(raw json.RawMessage) (*interfaces.Log, error) {
+	var rl rpcLog
+	if err := json.Unmarshal(raw, &rl); err != nil {
+		return nil, err
+	}
+	return rl.toLog()
+}
+
+// toLog converts rpcLog to interfaces.Log
+func (rl *rpcLog) toLog() (*interfaces.Log, error) {
+	if rl.Address == "" {
+		return nil, fmt.Errorf("missing address")
+	}
+
+	topics := rl.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+
+	data := []byte{}
+	if rl.Data != nil {
+		data = *rl.Data
+	}
+
+	var logIndex *uint64
+	if rl.LogIndex != nil {
+		index := uint64(*rl.LogIndex)
+		logIndex = &index
+	}
+
+	var blockNumber uint64
+	if rl.BlockNumber != nil {
+		blockNumber = uint64(*rl.BlockNumber)
 	}
 
 	return &interfaces.Log{
-		Address: address,
-		Topics:  topics,
-		Data:    data,
+		Address:     rl.Address,
+		Topics:      topics,
+		Data:        data,
+		LogIndex:    logIndex,
+		BlockHash:   rl.BlockHash,
+		BlockNumber: int64(blockNumber),
 	}, nil
 }
 
+// rpcRequest is a JSON-RPC 2.0 request
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+	ID      uint64        `json:"id"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response
+type rpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// decode unmarshals the result into out, leaving out untouched for a null result
+func (r *rpcResponse) decode(out interface{}) error {
+	if r.Error != nil {
+		return r.Error
+	}
+
+	// Handle null result
+	if len(r.Result) == 0 || string(r.Result) == "null" {
+		return nil // Caller should check for nil result
+	}
+
+	if err := json.Unmarshal(r.Result, out); err != nil {
+		return fmt.Errorf("unmarshal result: %w", err)
+	}
+
+	return nil
+}
+
+// RPCError is an error object returned by the node
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
@@ -0,0 +1,491 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+)
+
+// ErrWSClosed is returned for calls on a closed WebSocket client
+var ErrWSClosed = errors.New("websocket client closed")
+
+// WSClient is a JSON-RPC client over WebSocket, used for push subscriptions.
+// The connection is dialed on first use; once it drops, all of its subscriptions
+// fail and the next subscribe call dials a new one.
+type WSClient struct {
+	url         string
+	dialer      *websocket.Dialer
+	callTimeout time.Duration
+	nextID      atomic.Uint64
+
+	mu     sync.Mutex
+	conn   *wsConn
+	closed bool
+
+	logger *zap.Logger
+}
+
+// WSOption configures WSClient
+type WSOption func(*WSClient)
+
+// WithWSLogger sets the logger
+func WithWSLogger(logger *zap.Logger) WSOption {
+	return func(c *WSClient) {
+		c.logger = logger
+	}
+}
+
+// WithWSCallTimeout bounds subscribe and unsubscribe calls
+func WithWSCallTimeout(timeout time.Duration) WSOption {
+	return func(c *WSClient) {
+		c.callTimeout = timeout
+	}
+}
+
+// NewWSClient creates a new WebSocket RPC client. No connection is made until the first subscription.
+func NewWSClient(wsURL string, opts ...WSOption) *WSClient {
+	client := &WSClient{
+		url: wsURL,
+		dialer: &websocket.Dialer{
+			HandshakeTimeout: 10 * time.Second,
+		},
+		callTimeout: 10 * time.Second,
+		logger:      zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+// SubscribeNewHeads subscribes to eth_subscribe("newHeads")
+func (c *WSClient) SubscribeNewHeads(
+	ctx context.Context,
+	ch chan<- *interfaces.BlockHeader,
+) (interfaces.Subscription, error) {
+	return c.subscribeHeaders(ctx, "eth", "newHeads", ch)
+}
+
+// SubscribeBlockAdded subscribes to phoenix_subscribe("blockAdded"), which announces
+// every block added to the DAG rather than only selected chain heads
+func (c *WSClient) SubscribeBlockAdded(
+	ctx context.Context,
+	ch chan<- *interfaces.BlockHeader,
+) (interfaces.Subscription, error) {
+	return c.subscribeHeaders(ctx, "phoenix", "blockAdded", ch)
+}
+
+// SubscribeNewBlocks implements interfaces.NewBlockSubscriber.
+// It prefers blockAdded and falls back to newHeads on nodes without the Phoenix namespace.
+func (c *WSClient) SubscribeNewBlocks(
+	ctx context.Context,
+	ch chan<- *interfaces.BlockHeader,
+) (interfaces.Subscription, error) {
+	sub, err := c.SubscribeBlockAdded(ctx, ch)
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == methodNotFound {
+		c.logger.Debug("blockAdded subscription not supported, using newHeads")
+		return c.SubscribeNewHeads(ctx, ch)
+	}
+
+	return sub, err
+}
+
+// Close closes the connection and ends all subscriptions
+func (c *WSClient) Close() error {
+	c.mu.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.closed = true
+	c.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return conn.close(ErrWSClosed)
+}
+
+// subscribeHeaders subscribes to a header channel and forwards decoded headers to ch
+func (c *WSClient) subscribeHeaders(
+	ctx context.Context,
+	namespace string,
+	channel string,
+	ch chan<- *interfaces.BlockHeader,
+) (interfaces.Subscription, error) {
+	deliver := func(quit <-chan struct{}, raw json.RawMessage) error {
+		var header rpcHeader
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return fmt.Errorf("unmarshal header: %w", err)
+		}
+
+		converted, err := header.toHeader()
+		if err != nil {
+			return fmt.Errorf("convert header: %w", err)
+		}
+
+		select {
+		case ch <- converted:
+		case <-quit:
+		}
+		return nil
+	}
+
+	sub, err := c.subscribe(ctx, namespace, channel, deliver)
+	if err != nil {
+		return nil, fmt.Errorf("%s_subscribe %s: %w", namespace, channel, err)
+	}
+	return sub, nil
+}
+
+// subscribe registers a subscription on the current connection, dialing one if needed
+func (c *WSClient) subscribe(
+	ctx context.Context,
+	namespace string,
+	channel string,
+	deliver func(quit <-chan struct{}, raw json.RawMessage) error,
+) (*wsSubscription, error) {
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &wsSubscription{
+		client:    c,
+		conn:      conn,
+		namespace: namespace,
+		deliver:   deliver,
+		quit:      make(chan struct{}),
+		err:       make(chan error, 1),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	var id string
+	if err := c.call(ctx, conn, namespace+"_subscribe", []interface{}{channel}, &id, sub); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("subscribed",
+		zap.String("namespace", namespace),
+		zap.String("channel", channel),
+		zap.String("id", id))
+
+	return sub, nil
+}
+
+// connect returns the current connection, dialing a new one if there is none
+func (c *WSClient) connect(ctx context.Context) (*wsConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrWSClosed
+	}
+	if c.conn != nil && !c.conn.isClosed() {
+		return c.conn, nil
+	}
+
+	ws, _, err := c.dialer.DialContext(ctx, c.url, nil)
+	if err != nil {
+		c.logger.Warn("websocket dial failed",
+			zap.String("url", c.url),
+			zap.Error(err))
+		return nil, fmt.Errorf("dial %s: %w", c.url, err)
+	}
+
+	conn := &wsConn{
+		ws:      ws,
+		pending: make(map[uint64]*wsPending),
+		subs:    make(map[string]*wsSubscription),
+		done:    make(chan struct{}),
+		logger:  c.logger,
+	}
+	go conn.readLoop()
+
+	c.conn = conn
+	c.logger.Info("websocket connected", zap.String("url", c.url))
+
+	return conn, nil
+}
+
+// call sends a request and waits for its response.
+// If sub is set, it is registered when the response arrives, before any notification is read.
+func (c *WSClient) call(
+	ctx context.Context,
+	conn *wsConn,
+	method string,
+	params []interface{},
+	result interface{},
+	sub *wsSubscription,
+) error {
+	id := c.nextID.Add(1)
+	pending := &wsPending{resp: make(chan *rpcResponse, 1), sub: sub}
+	if err := conn.addPending(id, pending); err != nil {
+		return err
+	}
+	defer conn.removePending(id)
+
+	req := rpcRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      id,
+	}
+	if err := conn.write(ctx, req); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-pending.resp:
+		return resp.decode(result)
+	case <-conn.done:
+		return conn.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wsPending is a call waiting for its response
+type wsPending struct {
+	resp chan *rpcResponse
+	sub  *wsSubscription // Registered under the returned ID, for subscribe calls
+}
+
+// wsMessage is any message read from the connection: a response or a notification
+type wsMessage struct {
+	ID     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// wsNotification is the params object of a subscription notification
+type wsNotification struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// wsConn is a single WebSocket connection with its pending calls and subscriptions
+type wsConn struct {
+	ws      *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[uint64]*wsPending
+	subs    map[string]*wsSubscription
+	err     error // Why the connection closed; set before done is closed
+	done    chan struct{}
+
+	logger *zap.Logger
+}
+
+// write sends a request; gorilla connections allow one concurrent writer
+func (wc *wsConn) write(ctx context.Context, req rpcRequest) error {
+	wc.writeMu.Lock()
+	defer wc.writeMu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		wc.ws.SetWriteDeadline(deadline)
+	} else {
+		wc.ws.SetWriteDeadline(time.Time{})
+	}
+
+	if err := wc.ws.WriteJSON(req); err != nil {
+		return fmt.Errorf("write %s: %w", req.Method, err)
+	}
+	return nil
+}
+
+// readLoop dispatches responses and notifications until the connection fails
+func (wc *wsConn) readLoop() {
+	for {
+		var msg wsMessage
+		if err := wc.ws.ReadJSON(&msg); err != nil {
+			wc.close(fmt.Errorf("websocket read: %w", err))
+			return
+		}
+
+		switch {
+		case msg.ID != nil:
+			wc.handleResponse(*msg.ID, &msg)
+		case strings.HasSuffix(msg.Method, "_subscription"):
+			wc.handleNotification(msg.Params)
+		}
+	}
+}
+
+// handleResponse hands a response to its waiting call
+func (wc *wsConn) handleResponse(id uint64, msg *wsMessage) {
+	wc.mu.Lock()
+	pending, ok := wc.pending[id]
+	if ok {
+		delete(wc.pending, id)
+	}
+
+	// Register subscriptions here so notifications right behind the response are not lost
+	if ok && pending.sub != nil && msg.Error == nil {
+		var subID string
+		if err := json.Unmarshal(msg.Result, &subID); err == nil {
+			pending.sub.id = subID
+			wc.subs[subID] = pending.sub
+		}
+	}
+	wc.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	pending.resp <- &rpcResponse{
+		ID:     id,
+		Result: msg.Result,
+		Error:  msg.Error,
+	}
+}
+
+// handleNotification delivers a notification to its subscription.
+// Delivery blocks until the subscriber receives it, so a stalled reader stalls the connection.
+func (wc *wsConn) handleNotification(params json.RawMessage) {
+	var notification wsNotification
+	if err := json.Unmarshal(params, &notification); err != nil {
+		wc.logger.Warn("invalid subscription notification", zap.Error(err))
+		return
+	}
+
+	wc.mu.Lock()
+	sub, ok := wc.subs[notification.Subscription]
+	wc.mu.Unlock()
+	if !ok {
+		return // Unsubscribed
+	}
+
+	if err := sub.deliver(sub.quit, notification.Result); err != nil {
+		wc.logger.Warn("dropping subscription notification",
+			zap.String("subscription", notification.Subscription),
+			zap.Error(err))
+	}
+}
+
+func (wc *wsConn) addPending(id uint64, pending *wsPending) error {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+
+	if wc.err != nil {
+		return wc.err
+	}
+	wc.pending[id] = pending
+	return nil
+}
+
+func (wc *wsConn) removePending(id uint64) {
+	wc.mu.Lock()
+	delete(wc.pending, id)
+	wc.mu.Unlock()
+}
+
+func (wc *wsConn) removeSub(id string) {
+	wc.mu.Lock()
+	delete(wc.subs, id)
+	wc.mu.Unlock()
+}
+
+func (wc *wsConn) isClosed() bool {
+	select {
+	case <-wc.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close fails pending calls and subscriptions with err; only the first call has an effect
+func (wc *wsConn) close(err error) error {
+	wc.mu.Lock()
+	if wc.err != nil {
+		wc.mu.Unlock()
+		return nil
+	}
+	wc.err = err
+	subs := wc.subs
+	wc.subs = make(map[string]*wsSubscription)
+	close(wc.done)
+	wc.mu.Unlock()
+
+	closeErr := wc.ws.Close()
+
+	for _, sub := range subs {
+		sub.terminate(err)
+	}
+
+	if !errors.Is(err, ErrWSClosed) {
+		wc.logger.Warn("websocket connection lost",
+			zap.Int("subscriptions", len(subs)),
+			zap.Error(err))
+	}
+
+	return closeErr
+}
+
+// wsSubscription implements interfaces.Subscription
+type wsSubscription struct {
+	client    *WSClient
+	conn      *wsConn
+	namespace string
+	id        string
+	deliver   func(quit <-chan struct{}, raw json.RawMessage) error
+
+	quit chan struct{}
+	err  chan error
+	once sync.Once
+}
+
+// Unsubscribe ends the subscription and closes the Err channel without an error
+func (s *wsSubscription) Unsubscribe() {
+	s.conn.removeSub(s.id)
+	s.terminate(nil)
+
+	if s.conn.isClosed() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.client.callTimeout)
+	defer cancel()
+
+	var ok bool
+	if err := s.client.call(ctx, s.conn, s.namespace+"_unsubscribe", []interface{}{s.id}, &ok, nil); err != nil {
+		s.client.logger.Debug("unsubscribe failed",
+			zap.String("id", s.id),
+			zap.Error(err))
+	}
+}
+
+// Err implements interfaces.Subscription
+func (s *wsSubscription) Err() <-chan error {
+	return s.err
+}
+
+// terminate stops delivery and reports err, if any, on the Err channel
+func (s *wsSubscription) terminate(err error) {
+	s.once.Do(func() {
+		close(s.quit)
+		if err != nil {
+			s.err <- err
+		}
+		close(s.err)
+	})
+}
@@ -0,0 +1,267 @@
+package rpc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/interfaces"
+	"github.com/BlockDAGPhoenix/phoenix-explorer/indexer/pkg/rpc"
+)
+
+// wsNode is an in-process stand-in for a node's WebSocket endpoint
+type wsNode struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	connections int
+	methods     []string
+	conns       []*websocket.Conn
+
+	// Methods answered with "method not found"
+	unsupported map[string]bool
+	// Headers pushed right after a subscription is confirmed
+	headers []map[string]interface{}
+}
+
+func newWSNode(t *testing.T) *wsNode {
+	node := &wsNode{unsupported: make(map[string]bool)}
+	upgrader := websocket.Upgrader{}
+
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+
+		node.mu.Lock()
+		node.connections++
+		node.conns = append(node.conns, conn)
+		node.mu.Unlock()
+
+		go node.serve(conn)
+	}))
+	t.Cleanup(node.server.Close)
+
+	return node
+}
+
+func (n *wsNode) url() string {
+	return "ws" + strings.TrimPrefix(n.server.URL, "http")
+}
+
+func (n *wsNode) serve(conn *websocket.Conn) {
+	defer conn.Close()
+
+	for {
+		var req struct {
+			ID     uint64        `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		n.mu.Lock()
+		n.methods = append(n.methods, req.Method)
+		unsupported := n.unsupported[req.Method]
+		headers := n.headers
+		n.mu.Unlock()
+
+		if unsupported {
+			conn.WriteJSON(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"error":   map[string]interface{}{"code": -32601, "message": "method not found"},
+			})
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(req.Method, "_subscribe"):
+			namespace := strings.TrimSuffix(req.Method, "_subscribe")
+			conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "0xsub"})
+
+			// Notifications follow the confirmation immediately
+			for _, header := range headers {
+				conn.WriteJSON(map[string]interface{}{
+					"jsonrpc": "2.0",
+					"method":  namespace + "_subscription",
+					"params":  map[string]interface{}{"subscription": "0xsub", "result": header},
+				})
+			}
+		case strings.HasSuffix(req.Method, "_unsubscribe"):
+			conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": true})
+		}
+	}
+}
+
+// dropConnections closes every open connection from the server side
+func (n *wsNode) dropConnections() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, conn := range n.conns {
+		conn.Close()
+	}
+	n.conns = nil
+}
+
+func (n *wsNode) calledMethods() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.methods...)
+}
+
+func testHeader(number string) map[string]interface{} {
+	return map[string]interface{}{
+		"hash":       "0x" + strings.Repeat("a", 64),
+		"number":     number,
+		"parentHash": "0x" + strings.Repeat("b", 64),
+		"timestamp":  "0x65abc123",
+	}
+}
+
+func receiveHeader(t *testing.T, ch <-chan *interfaces.BlockHeader) *interfaces.BlockHeader {
+	t.Helper()
+
+	select {
+	case header := <-ch:
+		return header
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "no header received")
+		return nil
+	}
+}
+
+func TestWSClient_SubscribeNewHeads(t *testing.T) {
+	node := newWSNode(t)
+	node.headers = []map[string]interface{}{testHeader("0x64"), testHeader("0x65")}
+
+	client := rpc.NewWSClient(node.url())
+	defer client.Close()
+
+	headers := make(chan *interfaces.BlockHeader)
+	sub, err := client.SubscribeNewHeads(context.Background(), headers)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	first := receiveHeader(t, headers)
+	assert.Equal(t, int64(100), first.Number)
+	assert.Equal(t, []string{"0x" + strings.Repeat("b", 64)}, first.ParentHashes)
+	assert.Equal(t, int64(101), receiveHeader(t, headers).Number)
+	assert.Equal(t, []string{"eth_subscribe"}, node.calledMethods())
+}
+
+func TestWSClient_SubscribeNewBlocks_PrefersBlockAdded(t *testing.T) {
+	node := newWSNode(t)
+	header := testHeader("0x64")
+	header["parentHashes"] = []string{"0x" + strings.Repeat("b", 64), "0x" + strings.Repeat("c", 64)}
+	header["blueScore"] = "0x70"
+	node.headers = []map[string]interface{}{header}
+
+	client := rpc.NewWSClient(node.url())
+	defer client.Close()
+
+	headers := make(chan *interfaces.BlockHeader)
+	sub, err := client.SubscribeNewBlocks(context.Background(), headers)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	received := receiveHeader(t, headers)
+	assert.Len(t, received.ParentHashes, 2)
+	assert.Equal(t, uint64(0x70), received.BlueScore)
+	assert.Equal(t, []string{"phoenix_subscribe"}, node.calledMethods())
+}
+
+func TestWSClient_SubscribeNewBlocks_FallsBackToNewHeads(t *testing.T) {
+	node := newWSNode(t)
+	node.unsupported["phoenix_subscribe"] = true
+	node.headers = []map[string]interface{}{testHeader("0x64")}
+
+	client := rpc.NewWSClient(node.url())
+	defer client.Close()
+
+	headers := make(chan *interfaces.BlockHeader)
+	sub, err := client.SubscribeNewBlocks(context.Background(), headers)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	assert.Equal(t, int64(100), receiveHeader(t, headers).Number)
+	assert.Equal(t, []string{"phoenix_subscribe", "eth_subscribe"}, node.calledMethods())
+}
+
+func TestWSClient_ConnectionDropEndsSubscription(t *testing.T) {
+	node := newWSNode(t)
+
+	client := rpc.NewWSClient(node.url())
+	defer client.Close()
+
+	headers := make(chan *interfaces.BlockHeader)
+	sub, err := client.SubscribeNewHeads(context.Background(), headers)
+	require.NoError(t, err)
+
+	node.dropConnections()
+
+	select {
+	case err := <-sub.Err():
+		assert.Error(t, err)
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "subscription did not report the dropped connection")
+	}
+
+	// The next subscription dials a new connection
+	_, err = client.SubscribeNewHeads(context.Background(), headers)
+	require.NoError(t, err)
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	assert.Equal(t, 2, node.connections)
+}
+
+func TestWSClient_Unsubscribe(t *testing.T) {
+	node := newWSNode(t)
+
+	client := rpc.NewWSClient(node.url())
+	defer client.Close()
+
+	sub, err := client.SubscribeNewHeads(context.Background(), make(chan *interfaces.BlockHeader))
+	require.NoError(t, err)
+
+	sub.Unsubscribe()
+
+	err, ok := <-sub.Err()
+	assert.False(t, ok, "Err should be closed")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eth_subscribe", "eth_unsubscribe"}, node.calledMethods())
+}
+
+func TestWSClient_DialFailure(t *testing.T) {
+	client := rpc.NewWSClient("ws://127.0.0.1:1")
+
+	_, err := client.SubscribeNewHeads(context.Background(), make(chan *interfaces.BlockHeader))
+	assert.Error(t, err)
+}
+
+func TestWSClient_Closed(t *testing.T) {
+	node := newWSNode(t)
+
+	client := rpc.NewWSClient(node.url())
+	sub, err := client.SubscribeNewHeads(context.Background(), make(chan *interfaces.BlockHeader))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+
+	err = <-sub.Err()
+	assert.ErrorIs(t, err, rpc.ErrWSClosed)
+
+	_, err = client.SubscribeNewHeads(context.Background(), make(chan *interfaces.BlockHeader))
+	assert.ErrorIs(t, err, rpc.ErrWSClosed)
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"math/big"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/mock"
@@ -43,6 +44,14 @@ func (m *MockPhoenixClient) GetBlockByHash(ctx context.Context, hash common.Hash
 	return args.Get(0).(*interfaces.Block), args.Error(1)
 }
 
+func (m *MockPhoenixClient) GetTransactionByHash(ctx context.Context, hash common.Hash) (*interfaces.Transaction, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.Transaction), args.Error(1)
+}
+
 func (m *MockPhoenixClient) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*interfaces.Receipt, error) {
 	args := m.Called(ctx, hash)
 	if args.Get(0) == nil {
@@ -80,6 +89,14 @@ func (m *MockPhoenixClient) GetBlueScore(ctx context.Context, blockNumber *big.I
 	return args.Get(0).(uint64), args.Error(1)
 }
 
+func (m *MockPhoenixClient) GetBlockGHOSTDAGData(ctx context.Context, hash common.Hash) (*interfaces.GHOSTDAGData, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.GHOSTDAGData), args.Error(1)
+}
+
 func (m *MockPhoenixClient) GetBlockParents(ctx context.Context, hash common.Hash) ([]common.Hash, error) {
 	args := m.Called(ctx, hash)
 	if args.Get(0) == nil {
@@ -98,8 +115,8 @@ func (m *MockBlockWriter) SaveBlock(ctx context.Context, block *domain.Block) er
 	return args.Error(0)
 }
 
-func (m *MockBlockWriter) UpdateBlock(ctx context.Context, hash string, updates map[string]interface{}) error {
-	args := m.Called(ctx, hash, updates)
+func (m *MockBlockWriter) UpdateBlock(ctx context.Context, hash domain.Hash, update domain.BlockUpdate) error {
+	args := m.Called(ctx, hash, update)
 	return args.Error(0)
 }
 
@@ -113,11 +130,21 @@ func (m *MockTransactionWriter) SaveTransaction(ctx context.Context, tx *domain.
 	return args.Error(0)
 }
 
-func (m *MockTransactionWriter) UpdateTransactionStatus(ctx context.Context, hash string, status int, gasUsed uint64) error {
+func (m *MockTransactionWriter) UpdateTransaction(ctx context.Context, hash domain.Hash, update domain.TransactionUpdate) error {
+	args := m.Called(ctx, hash, update)
+	return args.Error(0)
+}
+
+func (m *MockTransactionWriter) UpdateTransactionStatus(ctx context.Context, hash domain.Hash, status int, gasUsed uint64) error {
 	args := m.Called(ctx, hash, status, gasUsed)
 	return args.Error(0)
 }
 
+func (m *MockTransactionWriter) SaveReceipt(ctx context.Context, receipt *domain.Receipt) error {
+	args := m.Called(ctx, receipt)
+	return args.Error(0)
+}
+
 // MockLogWriter is a mock implementation of LogWriter
 type MockLogWriter struct {
 	mock.Mock
@@ -133,13 +160,266 @@ type MockDAGWriter struct {
 	mock.Mock
 }
 
-func (m *MockDAGWriter) SaveDAGRelationship(ctx context.Context, childHash, parentHash string, isSelectedParent bool) error {
+func (m *MockDAGWriter) SaveDAGRelationship(ctx context.Context, childHash, parentHash domain.Hash, isSelectedParent bool) error {
 	args := m.Called(ctx, childHash, parentHash, isSelectedParent)
 	return args.Error(0)
 }
 
-func (m *MockDAGWriter) SaveGHOSTDAGData(ctx context.Context, blockHash string, data *domain.GHOSTDAGData) error {
+func (m *MockDAGWriter) SaveGHOSTDAGData(ctx context.Context, blockHash domain.Hash, data *domain.GHOSTDAGData) error {
 	args := m.Called(ctx, blockHash, data)
 	return args.Error(0)
 }
 
+func (m *MockDAGWriter) SaveMergeSet(ctx context.Context, blockHash domain.Hash, mergeSet []domain.MergeSetMember) error {
+	args := m.Called(ctx, blockHash, mergeSet)
+	return args.Error(0)
+}
+
+// MockBlockReader is a mock implementation of BlockReader
+type MockBlockReader struct {
+	mock.Mock
+}
+
+func (m *MockBlockReader) GetBlockByHash(ctx context.Context, hash domain.Hash) (*domain.Block, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Block), args.Error(1)
+}
+
+func (m *MockBlockReader) GetBlockByNumber(ctx context.Context, number int64) (*domain.Block, error) {
+	args := m.Called(ctx, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Block), args.Error(1)
+}
+
+func (m *MockBlockReader) GetLatestBlocks(ctx context.Context, limit int) ([]*domain.Block, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Block), args.Error(1)
+}
+
+// MockTransactionReader is a mock implementation of TransactionReader
+type MockTransactionReader struct {
+	mock.Mock
+}
+
+func (m *MockTransactionReader) GetTransactionByHash(ctx context.Context, hash domain.Hash) (*domain.Transaction, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionReader) GetTransactionsByBlockHash(ctx context.Context, blockHash domain.Hash) ([]*domain.Transaction, error) {
+	args := m.Called(ctx, blockHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Transaction), args.Error(1)
+}
+
+// MockDAGGapReader is a mock implementation of DAGGapReader
+type MockDAGGapReader struct {
+	mock.Mock
+}
+
+func (m *MockDAGGapReader) GetMissingParents(ctx context.Context, fromNumber, toNumber int64) ([]domain.Hash, error) {
+	args := m.Called(ctx, fromNumber, toNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Hash), args.Error(1)
+}
+
+func (m *MockDAGGapReader) FilterMissingBlocks(ctx context.Context, hashes []domain.Hash) ([]domain.Hash, error) {
+	args := m.Called(ctx, hashes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Hash), args.Error(1)
+}
+
+// MockDAGWindowReader is a mock implementation of DAGWindowReader
+type MockDAGWindowReader struct {
+	mock.Mock
+}
+
+func (m *MockDAGWindowReader) GetDAGWindow(ctx context.Context, fromNumber, toNumber int64) ([]*domain.DAGBlock, error) {
+	args := m.Called(ctx, fromNumber, toNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DAGBlock), args.Error(1)
+}
+
+func (m *MockDAGWindowReader) GetDAGWindowByBlueScore(ctx context.Context, fromBlueScore, toBlueScore uint64) ([]*domain.DAGBlock, error) {
+	args := m.Called(ctx, fromBlueScore, toBlueScore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DAGBlock), args.Error(1)
+}
+
+// MockCheckpointStore is a mock implementation of CheckpointStore
+type MockCheckpointStore struct {
+	mock.Mock
+}
+
+func (m *MockCheckpointStore) GetCheckpoint(ctx context.Context, name string) (*domain.Checkpoint, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Checkpoint), args.Error(1)
+}
+
+func (m *MockCheckpointStore) SaveCheckpoint(ctx context.Context, checkpoint *domain.Checkpoint) error {
+	args := m.Called(ctx, checkpoint)
+	return args.Error(0)
+}
+
+// MockChainBlockReader is a mock implementation of ChainBlockReader
+type MockChainBlockReader struct {
+	mock.Mock
+}
+
+func (m *MockChainBlockReader) GetChainBlocksAboveBlueScore(ctx context.Context, blueScore uint64) ([]*domain.Block, error) {
+	args := m.Called(ctx, blueScore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Block), args.Error(1)
+}
+
+// MockTransactionAcceptanceWriter is a mock implementation of TransactionAcceptanceWriter
+type MockTransactionAcceptanceWriter struct {
+	mock.Mock
+}
+
+func (m *MockTransactionAcceptanceWriter) RecomputeTransactionAcceptance(ctx context.Context, blockHashes []domain.Hash) (int64, error) {
+	args := m.Called(ctx, blockHashes)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockTransactionInclusionWriter is a mock implementation of TransactionInclusionWriter
+type MockTransactionInclusionWriter struct {
+	mock.Mock
+}
+
+func (m *MockTransactionInclusionWriter) RecomputeTransactionInclusions(ctx context.Context, blockHashes []domain.Hash) (int64, error) {
+	args := m.Called(ctx, blockHashes)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockReorgEventPublisher is a mock implementation of ReorgEventPublisher
+type MockReorgEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockReorgEventPublisher) PublishReorg(ctx context.Context, event *domain.ReorgEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// MockDecodeAnomalyWriter is a mock implementation of DecodeAnomalyWriter
+type MockDecodeAnomalyWriter struct {
+	mock.Mock
+}
+
+func (m *MockDecodeAnomalyWriter) SaveDecodeAnomaly(ctx context.Context, anomaly *domain.DecodeAnomaly) error {
+	args := m.Called(ctx, anomaly)
+	return args.Error(0)
+}
+
+// MockUnitOfWork is a mock implementation of UnitOfWork.
+// WithTx runs fn against Repos unless an error is configured for the call.
+type MockUnitOfWork struct {
+	mock.Mock
+	Repos interfaces.Repos
+}
+
+func (m *MockUnitOfWork) WithTx(ctx context.Context, fn func(repos interfaces.Repos) error) error {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m.Repos)
+}
+
+// MockBulkWriter is a mock implementation of BulkWriter
+type MockBulkWriter struct {
+	mock.Mock
+}
+
+func (m *MockBulkWriter) WriteBatch(ctx context.Context, blocks []*domain.Block) error {
+	args := m.Called(ctx, blocks)
+	return args.Error(0)
+}
+
+func (m *MockBulkWriter) WriteLogs(ctx context.Context, logs []*domain.Log) error {
+	args := m.Called(ctx, logs)
+	return args.Error(0)
+}
+
+func (m *MockPhoenixClient) GetBlocksByNumber(ctx context.Context, numbers []*big.Int, fullTx bool) ([]*interfaces.Block, error) {
+	args := m.Called(ctx, numbers, fullTx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*interfaces.Block), args.Error(1)
+}
+
+func (m *MockPhoenixClient) GetTransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*interfaces.Receipt, error) {
+	args := m.Called(ctx, hashes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*interfaces.Receipt), args.Error(1)
+}
+
+// MockBlockSubscriber is a mock implementation of NewBlockSubscriber.
+// Each subscription it hands out is also sent on Subscriptions, if set, so tests can drive it.
+type MockBlockSubscriber struct {
+	mock.Mock
+	Subscriptions chan *MockSubscription
+}
+
+func (m *MockBlockSubscriber) SubscribeNewBlocks(ctx context.Context, ch chan<- *interfaces.BlockHeader) (interfaces.Subscription, error) {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return nil, err
+	}
+
+	sub := &MockSubscription{Headers: ch, ErrCh: make(chan error, 1)}
+	if m.Subscriptions != nil {
+		m.Subscriptions <- sub
+	}
+	return sub, nil
+}
+
+// MockSubscription is a subscription driven by the test through Headers and ErrCh
+type MockSubscription struct {
+	Headers      chan<- *interfaces.BlockHeader
+	ErrCh        chan error
+	unsubscribed atomic.Bool
+}
+
+func (s *MockSubscription) Unsubscribe() {
+	s.unsubscribed.Store(true)
+}
+
+func (s *MockSubscription) Err() <-chan error {
+	return s.ErrCh
+}
+
+// Unsubscribed reports whether Unsubscribe was called
+func (s *MockSubscription) Unsubscribed() bool {
+	return s.unsubscribed.Load()
+}